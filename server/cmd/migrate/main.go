@@ -61,13 +61,23 @@ func main() {
 }
 
 func migrateUp(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := dedupStudyProgress(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
 		// Core users
 		&models.User{},
 		&models.UserDetail{},
+		&models.APIKey{},
+
+		// Audit
+		&models.AuditLog{},
 
 		// Notifications
 		&models.Notification{},
+		&models.NotificationDelivery{},
+		&models.NotificationPreference{},
 
 		// Document types and templates
 		&models.DocumentType{},
@@ -83,13 +93,23 @@ func migrateUp(db *gorm.DB) error {
 		// Documents
 		&models.Document{},
 		&models.DocumentPDF{},
+		&models.DocumentRevocation{},
+		&models.DocumentArchiveJob{},
+		&models.DocumentDownloadLog{},
 
 		// Evaluations
 		&models.Evaluation{},
 		&models.EvaluationQuestion{},
+		&models.EvaluationQuestionOption{},
+		&models.EvaluationAttempt{},
 		&models.EvaluationAnswer{},
 		&models.EvaluationScore{},
 		&models.EvaluationDoc{},
+		&models.EvaluationDrawRule{},
+
+		// Question bank
+		&models.BankQuestion{},
+		&models.BankQuestionOption{},
 
 		// Study materials
 		&models.StudyMaterial{},
@@ -99,6 +119,63 @@ func migrateUp(db *gorm.DB) error {
 		&models.StudyAnnotation{},
 		&models.StudyProgress{},
 	)
+	if err != nil {
+		return err
+	}
+
+	return dropLegacyIndexes(db)
+}
+
+// dedupStudyProgress keeps one study_progress row per user and subsection so
+// AutoMigrate can create idx_study_progress_user_subsection. A completed row
+// wins over an incomplete one, then the most recently completed.
+func dedupStudyProgress(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.StudyProgress{}) || migrator.HasIndex(&models.StudyProgress{}, "idx_study_progress_user_subsection") {
+		return nil
+	}
+
+	result := db.Exec(`DELETE FROM study_progress sp
+		USING (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY user_id, subsection_id
+				ORDER BY completed DESC, completed_at DESC NULLS LAST, id
+			) AS rn
+			FROM study_progress
+		) ranked
+		WHERE sp.id = ranked.id AND ranked.rn > 1`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Info().Int64("rows", result.RowsAffected).Msg("Removed duplicate study progress rows")
+	}
+	return nil
+}
+
+// dropLegacyIndexes removes indexes replaced by partial ones that ignore
+// soft-deleted rows or empty national IDs
+func dropLegacyIndexes(db *gorm.DB) error {
+	migrator := db.Migrator()
+
+	legacy := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.UserDetail{}, "idx_user_details_national_id"},
+		{&models.User{}, "idx_users_national_id"},
+	}
+
+	for _, idx := range legacy {
+		if !migrator.HasIndex(idx.model, idx.name) {
+			continue
+		}
+		if err := migrator.DropIndex(idx.model, idx.name); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func migrateDown(db *gorm.DB) error {
@@ -106,17 +183,26 @@ func migrateDown(db *gorm.DB) error {
 
 	// Drop in reverse order to handle foreign keys
 	tables := []interface{}{
+		&models.AuditLog{},
 		&models.StudyProgress{},
 		&models.StudyAnnotation{},
 		&models.StudyResource{},
 		&models.StudySubsection{},
 		&models.StudySection{},
 		&models.StudyMaterial{},
+		&models.BankQuestionOption{},
+		&models.BankQuestion{},
+		&models.EvaluationDrawRule{},
 		&models.EvaluationDoc{},
 		&models.EvaluationScore{},
 		&models.EvaluationAnswer{},
+		&models.EvaluationAttempt{},
+		&models.EvaluationQuestionOption{},
 		&models.EvaluationQuestion{},
 		&models.Evaluation{},
+		&models.DocumentDownloadLog{},
+		&models.DocumentArchiveJob{},
+		&models.DocumentRevocation{},
 		&models.DocumentPDF{},
 		&models.Document{},
 		&models.EventParticipant{},
@@ -126,7 +212,10 @@ func migrateDown(db *gorm.DB) error {
 		&models.DocumentTemplate{},
 		&models.DocumentCategory{},
 		&models.DocumentType{},
+		&models.NotificationPreference{},
+		&models.NotificationDelivery{},
 		&models.Notification{},
+		&models.APIKey{},
 		&models.UserDetail{},
 		&models.User{},
 	}
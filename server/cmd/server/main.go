@@ -8,14 +8,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"server/internal/app"
+	"server/internal/client/filesvc"
+	"server/internal/client/mailer"
 	"server/internal/config"
+	"server/internal/dto"
 	"server/internal/middleware"
+	"server/internal/service"
 	"server/pkg/shared/logger"
 )
 
@@ -39,10 +44,48 @@ func main() {
 	}
 
 	// Initialize Keycloak middleware (required)
-	if err := initKeycloak(cfg); err != nil {
+	keycloak, err := initKeycloak(cfg)
+	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize Keycloak")
 	}
 
+	// Load permission matrix (required)
+	authz, err := middleware.NewAuthorizer(middleware.AuthorizerConfig{
+		PermissionsFile: cfg.Keycloak.PermissionsFile,
+		ClientID:        cfg.Keycloak.ClientID,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load permission matrix")
+	}
+
+	// Load revocation list signing key
+	revocationKey, ephemeral, err := service.LoadRevocationSigningKey(cfg.RevList.SigningKeyFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load revocation signing key")
+	}
+	if ephemeral {
+		// an ephemeral key changes on every restart and invalidates cached revocation lists
+		if cfg.Server.Environment != "development" {
+			log.Fatal().Str("SERVER_ENVIRONMENT", cfg.Server.Environment).Msg("REVOCATION_SIGNING_KEY_FILE is required outside development")
+		}
+		log.Warn().Msg("REVOCATION_SIGNING_KEY_FILE not set, signing revocation list with an ephemeral key")
+	}
+
+	// Load notification templates
+	notificationTemplates, err := service.LoadNotificationTemplates(cfg.Notify.TemplatesFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load notification templates")
+	}
+	if cfg.SMTP.Host == "" {
+		log.Warn().Msg("SMTP_HOST not set, email notifications are disabled")
+	}
+
+	// Evaluation report PDF rendering (optional)
+	reportConfig, err := evaluationReportConfig(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid evaluation report configuration")
+	}
+
 	// Initialize connections
 	conn := initConnections(cfg)
 
@@ -51,8 +94,52 @@ func main() {
 		DB:    conn.db,
 		Redis: conn.redis,
 		NATS:  conn.nats,
+		FileSvc: filesvc.New(filesvc.Config{
+			BaseURL:      cfg.FileSvc.URL,
+			Timeout:      time.Duration(cfg.FileSvc.TimeoutSeconds) * time.Second,
+			MaxRetries:   cfg.FileSvc.MaxRetries,
+			RetryBackoff: time.Duration(cfg.FileSvc.RetryBackoffMS) * time.Millisecond,
+		}),
+		Archive: service.DocumentArchiveConfig{
+			Dir:       cfg.Archive.Dir,
+			TTL:       time.Duration(cfg.Archive.TTLHours) * time.Hour,
+			SyncLimit: cfg.Archive.SyncLimit,
+		},
+		RevList: service.RevocationListConfig{
+			SigningKey: revocationKey,
+			Issuer:     cfg.RevList.Issuer,
+			Refresh:    time.Duration(cfg.RevList.RefreshMinutes) * time.Minute,
+		},
+		Mailer: mailer.New(mailer.Config{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			TLSMode:  cfg.SMTP.TLSMode,
+			Timeout:  time.Duration(cfg.SMTP.TimeoutSeconds) * time.Second,
+		}),
+		Notify: service.NotificationConfig{
+			Templates:      notificationTemplates,
+			DefaultLocale:  cfg.Notify.DefaultLocale,
+			VerifyBaseURL:  cfg.Notify.VerifyBaseURL,
+			MaxAttempts:    cfg.Notify.MaxAttempts,
+			RetryBackoff:   time.Duration(cfg.Notify.RetryBackoffSeconds) * time.Second,
+			PollInterval:   time.Duration(cfg.Notify.PollSeconds) * time.Second,
+			DigestInterval: time.Duration(cfg.Notify.DigestPollSeconds) * time.Second,
+		},
+		Certification: certificationConfig(cfg),
+		Report:        reportConfig,
+		Provisioning:  provisioningConfig(cfg),
+		Authz:         authz,
+		Keycloak:      keycloak,
 	})
 
+	// Start background workers (PDF results, notifications)
+	if err := application.StartWorkers(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start workers")
+	}
+
 	// Start server
 	go startServer(application, cfg)
 
@@ -60,11 +147,61 @@ func main() {
 	waitForShutdown(application)
 }
 
+// provisioningConfig sets how far the national_id claim is trusted
+func provisioningConfig(cfg *config.Config) service.UserProvisioningConfig {
+	return service.UserProvisioningConfig{
+		RequireNationalID:      cfg.Keycloak.RequireNationalID,
+		NationalIDAdminManaged: cfg.Keycloak.NationalIDAdminManaged,
+	}
+}
+
+// certificationConfig builds the QR placement for certificates issued on evaluation pass
+func certificationConfig(cfg *config.Config) service.CertificationConfig {
+	if cfg.Cert.QRBaseURL == "" {
+		return service.CertificationConfig{}
+	}
+	return service.CertificationConfig{
+		QR: &dto.QRConfigRequest{
+			BaseURL:     cfg.Cert.QRBaseURL,
+			QRSizeCM:    cfg.Cert.QRSizeCM,
+			QRMarginYCM: cfg.Cert.QRMarginYCM,
+			QRPage:      cfg.Cert.QRPage,
+		},
+	}
+}
+
+// evaluationReportConfig builds the PDF rendering of evaluation reports; it stays
+// disabled until both the template and the QR base URL are set
+func evaluationReportConfig(cfg *config.Config) (service.EvaluationReportConfig, error) {
+	reportCfg := service.EvaluationReportConfig{AutoPDF: cfg.Report.AutoPDF}
+	if cfg.Report.TemplateFileID == "" || cfg.Report.QRBaseURL == "" {
+		return reportCfg, nil
+	}
+
+	templateFileID, err := uuid.Parse(cfg.Report.TemplateFileID)
+	if err != nil {
+		return reportCfg, fmt.Errorf("EVALUATION_REPORT_TEMPLATE_FILE_ID: %w", err)
+	}
+	reportCfg.TemplateFileID = &templateFileID
+	reportCfg.QR = &dto.QRConfigRequest{
+		BaseURL:     cfg.Report.QRBaseURL,
+		QRSizeCM:    cfg.Cert.QRSizeCM,
+		QRMarginYCM: cfg.Cert.QRMarginYCM,
+		QRPage:      cfg.Cert.QRPage,
+	}
+	return reportCfg, nil
+}
+
 // initKeycloak initializes Keycloak authentication
-func initKeycloak(cfg *config.Config) error {
-	return middleware.InitKeycloakMiddleware(middleware.KeycloakConfig{
-		SSOURL: cfg.Keycloak.SSOURL,
-		Realm:  cfg.Keycloak.Realm,
+func initKeycloak(cfg *config.Config) (*middleware.KeycloakMiddleware, error) {
+	return middleware.NewKeycloakMiddleware(middleware.KeycloakConfig{
+		SSOURL:            cfg.Keycloak.SSOURL,
+		Realm:             cfg.Keycloak.Realm,
+		Issuer:            cfg.Keycloak.Issuer,
+		JWKSFile:          cfg.Keycloak.JWKSFile,
+		Audiences:         cfg.Keycloak.Audiences,
+		AuthorizedParties: cfg.Keycloak.AuthorizedParties,
+		ClockSkew:         time.Duration(cfg.Keycloak.ClockSkewSeconds) * time.Second,
 	})
 }
 
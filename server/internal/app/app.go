@@ -9,33 +9,73 @@ import (
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
+	"server/internal/client/filesvc"
+	"server/internal/client/mailer"
 	"server/internal/handler"
+	"server/internal/middleware"
 	"server/internal/repository"
 	"server/internal/service"
 	"server/internal/worker"
 )
 
 type App struct {
-	db        *gorm.DB
-	redis     *redis.Client
-	nats      *nats.Conn
-	fiber     *fiber.App
-	pdfWorker *worker.FNPDFWorker
+	db            *gorm.DB
+	redis         *redis.Client
+	nats          *nats.Conn
+	fileSvc       *filesvc.Client
+	archive       service.DocumentArchiveConfig
+	revList       service.RevocationListConfig
+	mailer        *mailer.Client
+	notify        service.NotificationConfig
+	certification service.CertificationConfig
+	report        service.EvaluationReportConfig
+	provisioning  service.UserProvisioningConfig
+	authz         *middleware.Authorizer
+	keycloak      *middleware.KeycloakMiddleware
+	fiber         *fiber.App
+	pdfWorker     *worker.FNPDFWorker
+
+	notificationWorker *worker.FNNotificationWorker
+	digestWorker       *worker.FNNotificationDigestWorker
+	notificationHub    *service.NotificationHub
+	archiveSvc         service.FNDocumentArchiveService
 }
 
 type Config struct {
-	DB    *gorm.DB
-	Redis *redis.Client
-	NATS  *nats.Conn
+	DB            *gorm.DB
+	Redis         *redis.Client
+	NATS          *nats.Conn
+	FileSvc       *filesvc.Client
+	Archive       service.DocumentArchiveConfig
+	RevList       service.RevocationListConfig
+	Mailer        *mailer.Client
+	Notify        service.NotificationConfig
+	Certification service.CertificationConfig
+	Report        service.EvaluationReportConfig
+	Provisioning  service.UserProvisioningConfig
+	Authz         *middleware.Authorizer
+	Keycloak      *middleware.KeycloakMiddleware
 }
 
 func New(cfg Config) *App {
 	app := &App{
-		db:    cfg.DB,
-		redis: cfg.Redis,
-		nats:  cfg.NATS,
+		db:            cfg.DB,
+		redis:         cfg.Redis,
+		nats:          cfg.NATS,
+		fileSvc:       cfg.FileSvc,
+		archive:       cfg.Archive,
+		revList:       cfg.RevList,
+		mailer:        cfg.Mailer,
+		notify:        cfg.Notify,
+		certification: cfg.Certification,
+		report:        cfg.Report,
+		provisioning:  cfg.Provisioning,
+		authz:         cfg.Authz,
+		keycloak:      cfg.Keycloak,
 	}
 
+	app.notificationHub = service.NewNotificationHub(app.nats, repository.NewFNNotificationRepository(app.db))
+
 	app.initRouter()
 	app.initWorkers()
 
@@ -44,8 +84,21 @@ func New(cfg Config) *App {
 
 func (a *App) initRouter() {
 	router := NewRouter(RouterConfig{
-		DX: a.buildDXHandlers(),
-		FN: a.buildFNHandlers(),
+		DX:       a.buildDXHandlers(),
+		FN:       a.buildFNHandlers(),
+		Authz:    a.authz,
+		Keycloak: a.keycloak,
+		Users: service.NewFNUserService(
+			repository.NewFNUserRepository(a.db),
+			repository.NewFNUserDetailRepository(a.db),
+			a.provisioning,
+		),
+		APIKeys: service.NewFNAPIKeyService(
+			repository.NewFNAPIKeyRepository(a.db),
+			repository.NewFNUserRepository(a.db),
+			a.authz.APIKeyScopes(),
+		),
+		Audit: service.NewFNAuditService(repository.NewFNAuditLogRepository(a.db)),
 	})
 	a.fiber = router.Setup()
 }
@@ -56,30 +109,92 @@ func (a *App) initWorkers() {
 	fnDocPDFRepo := repository.NewFNDocumentPDFRepository(a.db)
 	fnEventRepo := repository.NewFNEventRepository(a.db)
 	fnUserDetailRepo := repository.NewFNUserDetailRepository(a.db)
+	fnRevocationRepo := repository.NewFNDocumentRevocationRepository(a.db)
+	fnParticipantRepo := repository.NewFNEventParticipantRepository(a.db)
+	fnUserRepo := repository.NewFNUserRepository(a.db)
 
 	fnDocActionSvc := service.NewFNDocumentActionService(
 		fnDocRepo,
 		fnDocPDFRepo,
 		fnEventRepo,
 		fnUserDetailRepo,
+		fnRevocationRepo,
+		fnParticipantRepo,
+		fnUserRepo,
+		repository.NewFNStudyMaterialRepository(a.db),
+		a.nats,
+	)
+
+	fnReportSvc := service.NewFNEvaluationReportService(
+		repository.NewFNEvaluationRepository(a.db),
+		fnUserRepo,
+		a.fileSvc,
 		a.nats,
+		a.report,
 	)
 
 	// create and store pdf worker
-	a.pdfWorker = worker.NewFNPDFWorker(a.nats, fnDocActionSvc)
+	a.pdfWorker = worker.NewFNPDFWorker(a.nats, fnDocActionSvc, fnReportSvc)
+
+	// notification dispatcher
+	fnNotificationSvc := service.NewFNNotificationService(
+		repository.NewFNNotificationRepository(a.db),
+		fnDocRepo,
+		fnEventRepo,
+		a.mailer,
+		a.notificationHub,
+		a.notify,
+	)
+	a.notificationWorker = worker.NewFNNotificationWorker(a.nats, fnNotificationSvc, a.notify.PollInterval)
+	a.digestWorker = worker.NewFNNotificationDigestWorker(fnNotificationSvc, a.notify.DigestInterval)
 }
 
 func (a *App) StartWorkers(ctx context.Context) error {
-	if a.pdfWorker != nil {
+	if a.archiveSvc != nil {
+		if n, err := a.archiveSvc.FailInterrupted(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to sweep interrupted archive jobs")
+		} else if n > 0 {
+			log.Info().Int64("failed", n).Msg("archive jobs interrupted by the last shutdown marked as failed")
+		}
+	}
+	if err := a.notificationHub.Start(); err != nil {
+		log.Error().Err(err).Msg("failed to start notification hub")
+		return err
+	}
+	if a.pdfWorker != nil && a.nats != nil {
 		if err := a.pdfWorker.Start(ctx); err != nil {
 			log.Error().Err(err).Msg("failed to start PDF worker")
 			return err
 		}
 		log.Info().Msg("PDF worker started successfully")
 	}
+	if a.notificationWorker != nil {
+		if err := a.notificationWorker.Start(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to start notification worker")
+			return err
+		}
+	}
+	if a.digestWorker != nil {
+		if err := a.digestWorker.Start(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to start notification digest worker")
+			return err
+		}
+	}
 	return nil
 }
 
+func (a *App) stopWorkers() {
+	if a.digestWorker != nil {
+		_ = a.digestWorker.Stop()
+	}
+	if a.notificationWorker != nil {
+		_ = a.notificationWorker.Stop()
+	}
+	if a.pdfWorker != nil && a.nats != nil {
+		_ = a.pdfWorker.Stop()
+	}
+}
+
 func (a *App) buildDXHandlers() *DXHandlers {
 	// dx repositories
 	userRepo := repository.NewUserRepository(a.db)
@@ -103,7 +218,7 @@ func (a *App) buildDXHandlers() *DXHandlers {
 	documentSvc := service.NewDocumentService(documentRepo)
 	eventSvc := service.NewEventService(eventRepo)
 	eventParticipantSvc := service.NewEventParticipantService(eventParticipantRepo)
-	notificationSvc := service.NewNotificationService(notificationRepo)
+	notificationSvc := service.NewNotificationService(notificationRepo, a.notificationHub)
 	evaluationSvc := service.NewEvaluationService(evaluationRepo)
 	studyMaterialSvc := service.NewStudyMaterialService(studyMaterialRepo)
 
@@ -130,22 +245,99 @@ func (a *App) buildFNHandlers() *FNHandlers {
 	fnUserDetailRepo := repository.NewFNUserDetailRepository(a.db)
 	fnDocRepo := repository.NewFNDocumentRepository(a.db)
 	fnDocPDFRepo := repository.NewFNDocumentPDFRepository(a.db)
+	fnParticipantRepo := repository.NewFNEventParticipantRepository(a.db)
+	fnArchiveRepo := repository.NewFNDocumentArchiveRepository(a.db)
+	fnUserRepo := repository.NewFNUserRepository(a.db)
+	fnDownloadLogRepo := repository.NewFNDocumentDownloadLogRepository(a.db)
+	fnRevocationRepo := repository.NewFNDocumentRevocationRepository(a.db)
 
 	// fn services
-	fnDocTemplateSvc := service.NewFNDocumentTemplateService(fnDocTemplateRepo)
-	fnEventSvc := service.NewFNEventService(fnEventRepo, fnUserDetailRepo)
+	fnDocTemplateSvc := service.NewFNDocumentTemplateService(fnDocTemplateRepo, a.fileSvc)
+	fnEvaluationRepo := repository.NewFNEvaluationRepository(a.db)
+	fnQuestionBankRepo := repository.NewFNQuestionBankRepository(a.db)
+	fnStudyMaterialRepo := repository.NewFNStudyMaterialRepository(a.db)
+	fnEventSvc := service.NewFNEventService(fnEventRepo, fnUserDetailRepo, fnEvaluationRepo, fnStudyMaterialRepo, a.nats)
+	fnParticipantSvc := service.NewFNEventParticipantService(
+		fnParticipantRepo,
+		fnEventRepo,
+		fnUserDetailRepo,
+		fnDocRepo,
+		fnUserRepo,
+		fnStudyMaterialRepo,
+	)
 	fnDocActionSvc := service.NewFNDocumentActionService(
 		fnDocRepo,
 		fnDocPDFRepo,
 		fnEventRepo,
 		fnUserDetailRepo,
+		fnRevocationRepo,
+		fnParticipantRepo,
+		fnUserRepo,
+		fnStudyMaterialRepo,
 		a.nats,
 	)
+	fnExportSvc := service.NewFNExportService(fnEventRepo, fnParticipantRepo)
+	fnArchiveSvc := service.NewFNDocumentArchiveService(
+		fnArchiveRepo,
+		fnDocRepo,
+		fnEventRepo,
+		a.fileSvc,
+		a.archive,
+	)
+	a.archiveSvc = fnArchiveSvc
+	fnDownloadSvc := service.NewFNDocumentDownloadService(
+		fnDocRepo,
+		fnDocPDFRepo,
+		fnUserRepo,
+		fnDownloadLogRepo,
+		a.fileSvc,
+	)
+	fnUserSvc := service.NewFNUserService(fnUserRepo, fnUserDetailRepo, a.provisioning)
+	fnMeSvc := service.NewFNMeService(fnUserDetailRepo, fnDocRepo, fnParticipantRepo)
+	fnAPIKeySvc := service.NewFNAPIKeyService(repository.NewFNAPIKeyRepository(a.db), fnUserRepo, a.authz.APIKeyScopes())
+	fnAuditSvc := service.NewFNAuditService(repository.NewFNAuditLogRepository(a.db))
+	fnNotificationSvc := service.NewFNNotificationService(
+		repository.NewFNNotificationRepository(a.db),
+		fnDocRepo,
+		fnEventRepo,
+		a.mailer,
+		a.notificationHub,
+		a.notify,
+	)
+	fnRevocationSvc := service.NewFNRevocationService(fnRevocationRepo, fnDocRepo, a.revList)
+	fnReportSvc := service.NewFNEvaluationReportService(fnEvaluationRepo, fnUserRepo, a.fileSvc, a.nats, a.report)
+	fnCertificationSvc := service.NewFNEventCertificationService(
+		fnEventRepo,
+		fnParticipantRepo,
+		fnUserRepo,
+		fnUserDetailRepo,
+		fnStudyMaterialRepo,
+		fnDocActionSvc,
+		a.certification,
+	)
+	fnEvaluationSvc := service.NewFNEvaluationService(fnEvaluationRepo, fnQuestionBankRepo, fnCertificationSvc, fnReportSvc)
 
 	return &FNHandlers{
 		DocumentTemplate: handler.NewFNDocumentTemplateHandler(fnDocTemplateSvc),
 		Event:            handler.NewFNEventHandler(fnEventSvc),
+		EventParticipant: handler.NewFNEventParticipantHandler(fnParticipantSvc),
 		DocumentAction:   handler.NewFNDocumentActionHandler(fnDocActionSvc),
+		Export:           handler.NewFNExportHandler(fnExportSvc),
+		DocumentArchive:  handler.NewFNDocumentArchiveHandler(fnArchiveSvc),
+		DocumentDownload: handler.NewFNDocumentDownloadHandler(fnDownloadSvc, a.authz),
+		Me:               handler.NewFNMeHandler(fnUserSvc, fnMeSvc, a.authz),
+		APIKey:           handler.NewFNAPIKeyHandler(fnAPIKeySvc),
+		Audit:            handler.NewFNAuditHandler(fnAuditSvc),
+		Revocation:       handler.NewFNRevocationHandler(fnRevocationSvc),
+		Notification:     handler.NewFNNotificationHandler(fnNotificationSvc),
+		NotificationInbox: handler.NewFNNotificationInboxHandler(
+			service.NewFNNotificationInboxService(repository.NewFNNotificationRepository(a.db), a.notificationHub),
+		),
+		Evaluation:   handler.NewFNEvaluationHandler(fnEvaluationSvc, fnReportSvc, a.authz),
+		QuestionBank: handler.NewFNQuestionBankHandler(service.NewFNQuestionBankService(fnQuestionBankRepo)),
+		StudyMaterial: handler.NewFNStudyMaterialHandler(
+			service.NewFNStudyMaterialService(fnStudyMaterialRepo, fnCertificationSvc),
+		),
 	}
 }
 
@@ -154,12 +346,17 @@ func (a *App) Fiber() *fiber.App {
 }
 
 func (a *App) Shutdown() error {
+	// open notification streams would keep the server from shutting down
+	a.notificationHub.Stop()
+
 	if a.fiber != nil {
 		if err := a.fiber.Shutdown(); err != nil {
 			return err
 		}
 	}
 
+	a.stopWorkers()
+
 	if a.nats != nil {
 		a.nats.Close()
 	}
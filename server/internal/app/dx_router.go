@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 
 	"server/internal/handler"
+	"server/internal/middleware"
 )
 
 // DXHandlers groups all handlers for the DX (Document Exchange) module
@@ -24,12 +25,19 @@ type DXHandlers struct {
 
 // DXRouter handles DX (Document Exchange) related routes
 type DXRouter struct {
-	h *DXHandlers
+	h     *DXHandlers
+	authz *middleware.Authorizer
+	audit fiber.Handler
 }
 
-// NewDXRouter creates a new DXRouter instance
-func NewDXRouter(handlers *DXHandlers) *DXRouter {
-	return &DXRouter{h: handlers}
+// NewDXRouter creates a new DXRouter instance; every DX group is admin CRUD and audited
+func NewDXRouter(handlers *DXHandlers, authz *middleware.Authorizer, audit fiber.Handler) *DXRouter {
+	return &DXRouter{h: handlers, authz: authz, audit: audit}
+}
+
+// can returns the middleware enforcing a permission from the matrix
+func (r *DXRouter) can(permission string) fiber.Handler {
+	return r.authz.Require(permission)
 }
 
 // SetupHealthRoutes configures health check routes (public)
@@ -55,129 +63,134 @@ func (r *DXRouter) SetupRoutes(api fiber.Router) {
 
 // setupUserRoutes configures user routes
 func (r *DXRouter) setupUserRoutes(api fiber.Router) {
-	g := api.Group("/users")
-	g.Get("/", r.h.User.GetAll)
-	g.Get("/:id", r.h.User.GetByID)
-	g.Post("/", r.h.User.Create)
-	g.Put("/:id", r.h.User.Update)
-	g.Delete("/:id", r.h.User.Delete)
+	g := api.Group("/users", r.audit)
+	g.Get("/", r.h.User.GetAll, r.can("users.read"))
+	g.Get("/:id", r.h.User.GetByID, r.can("users.read"))
+	g.Post("/", r.h.User.Create, r.can("users.write"))
+	g.Put("/:id", r.h.User.Update, r.can("users.write"))
+	g.Delete("/:id", r.h.User.Delete, r.can("users.write"))
 }
 
 // setupUserDetailRoutes configures user detail routes (beneficiaries)
 func (r *DXRouter) setupUserDetailRoutes(api fiber.Router) {
-	g := api.Group("/user-details")
-	g.Get("/", r.h.UserDetail.GetAll)
-	g.Get("/:id", r.h.UserDetail.GetByID)
-	g.Get("/dni/:nationalId", r.h.UserDetail.GetByNationalID)
-	g.Post("/", r.h.UserDetail.Create)
-	g.Put("/:id", r.h.UserDetail.Update)
-	g.Delete("/:id", r.h.UserDetail.Delete)
+	g := api.Group("/user-details", r.audit)
+	g.Get("/", r.h.UserDetail.GetAll, r.can("user_details.read"))
+	g.Get("/:id", r.h.UserDetail.GetByID, r.can("user_details.read"))
+	g.Get("/dni/:nationalId", r.h.UserDetail.GetByNationalID, r.can("user_details.read"))
+	g.Post("/", r.h.UserDetail.Create, r.can("user_details.write"))
+	g.Put("/:id", r.h.UserDetail.Update, r.can("user_details.write"))
+	g.Delete("/:id", r.h.UserDetail.Delete, r.can("user_details.write"))
+	g.Patch("/:id/restore", r.h.UserDetail.Restore, r.can("user_details.write"))
+	g.Delete("/:id/purge", r.h.UserDetail.Purge, r.can("records.purge"))
 }
 
 // setupDocumentTypeRoutes configures document type routes
 func (r *DXRouter) setupDocumentTypeRoutes(api fiber.Router) {
-	g := api.Group("/document-types")
-	g.Get("/", r.h.DocumentType.GetAll)
-	g.Get("/active", r.h.DocumentType.GetActive)
-	g.Get("/:id", r.h.DocumentType.GetByID)
-	g.Get("/code/:code", r.h.DocumentType.GetByCode)
-	g.Post("/", r.h.DocumentType.Create)
-	g.Put("/:id", r.h.DocumentType.Update)
-	g.Delete("/:id", r.h.DocumentType.Delete)
+	g := api.Group("/document-types", r.audit)
+	g.Get("/", r.h.DocumentType.GetAll, r.can("catalog.read"))
+	g.Get("/active", r.h.DocumentType.GetActive, r.can("catalog.read"))
+	g.Get("/:id", r.h.DocumentType.GetByID, r.can("catalog.read"))
+	g.Get("/code/:code", r.h.DocumentType.GetByCode, r.can("catalog.read"))
+	g.Post("/", r.h.DocumentType.Create, r.can("catalog.write"))
+	g.Put("/:id", r.h.DocumentType.Update, r.can("catalog.write"))
+	g.Delete("/:id", r.h.DocumentType.Delete, r.can("catalog.write"))
 }
 
 // setupDocumentCategoryRoutes configures document category routes
 func (r *DXRouter) setupDocumentCategoryRoutes(api fiber.Router) {
-	g := api.Group("/document-categories")
-	g.Get("/", r.h.DocumentCategory.GetAll)
-	g.Get("/:id", r.h.DocumentCategory.GetByID)
-	g.Get("/document-type/:documentTypeId", r.h.DocumentCategory.GetByDocumentTypeID)
-	g.Post("/", r.h.DocumentCategory.Create)
-	g.Put("/:id", r.h.DocumentCategory.Update)
-	g.Delete("/:id", r.h.DocumentCategory.Delete)
+	g := api.Group("/document-categories", r.audit)
+	g.Get("/", r.h.DocumentCategory.GetAll, r.can("catalog.read"))
+	g.Get("/:id", r.h.DocumentCategory.GetByID, r.can("catalog.read"))
+	g.Get("/document-type/:documentTypeId", r.h.DocumentCategory.GetByDocumentTypeID, r.can("catalog.read"))
+	g.Post("/", r.h.DocumentCategory.Create, r.can("catalog.write"))
+	g.Put("/:id", r.h.DocumentCategory.Update, r.can("catalog.write"))
+	g.Delete("/:id", r.h.DocumentCategory.Delete, r.can("catalog.write"))
 }
 
-// setupDocumentTemplateRoutes configures document template routes
+// setupDocumentTemplateRoutes configures document template routes. DX CRUD ignores
+// organizational units, so unit-scoped staff use the FN routes instead.
 func (r *DXRouter) setupDocumentTemplateRoutes(api fiber.Router) {
-	g := api.Group("/document-templates")
-	g.Get("/", r.h.DocumentTemplate.GetAll)
-	g.Get("/active", r.h.DocumentTemplate.GetActive)
-	g.Get("/:id", r.h.DocumentTemplate.GetByID)
-	g.Get("/document-type/:documentTypeId", r.h.DocumentTemplate.GetByDocumentTypeID)
-	g.Post("/", r.h.DocumentTemplate.Create)
-	g.Put("/:id", r.h.DocumentTemplate.Update)
-	g.Delete("/:id", r.h.DocumentTemplate.Delete)
+	g := api.Group("/document-templates", r.audit)
+	g.Get("/", r.h.DocumentTemplate.GetAll, r.can("records.unscoped"))
+	g.Get("/active", r.h.DocumentTemplate.GetActive, r.can("records.unscoped"))
+	g.Get("/:id", r.h.DocumentTemplate.GetByID, r.can("records.unscoped"))
+	g.Get("/document-type/:documentTypeId", r.h.DocumentTemplate.GetByDocumentTypeID, r.can("records.unscoped"))
+	g.Post("/", r.h.DocumentTemplate.Create, r.can("records.unscoped"))
+	g.Put("/:id", r.h.DocumentTemplate.Update, r.can("records.unscoped"))
+	g.Delete("/:id", r.h.DocumentTemplate.Delete, r.can("records.unscoped"))
 }
 
-// setupDocumentRoutes configures document routes
+// setupDocumentRoutes configures document routes (unscoped, see setupDocumentTemplateRoutes)
 func (r *DXRouter) setupDocumentRoutes(api fiber.Router) {
-	g := api.Group("/documents")
-	g.Get("/", r.h.Document.GetAll)
-	g.Get("/:id", r.h.Document.GetByID)
-	g.Get("/serial/:serialCode", r.h.Document.GetBySerialCode)
-	g.Get("/verify/:verificationCode", r.h.Document.GetByVerificationCode)
-	g.Get("/event/:eventId", r.h.Document.GetByEventID)
-	g.Get("/user-detail/:userDetailId", r.h.Document.GetByUserDetailID)
-	g.Post("/", r.h.Document.Create)
-	g.Put("/:id", r.h.Document.Update)
-	g.Delete("/:id", r.h.Document.Delete)
-}
-
-// setupEventRoutes configures event routes
+	g := api.Group("/documents", r.audit)
+	g.Get("/", r.h.Document.GetAll, r.can("records.unscoped"))
+	g.Get("/:id", r.h.Document.GetByID, r.can("records.unscoped"))
+	g.Get("/serial/:serialCode", r.h.Document.GetBySerialCode, r.can("records.unscoped"))
+	g.Get("/verify/:verificationCode", r.h.Document.GetByVerificationCode, r.can("documents.verify"))
+	g.Get("/event/:eventId", r.h.Document.GetByEventID, r.can("records.unscoped"))
+	g.Get("/user-detail/:userDetailId", r.h.Document.GetByUserDetailID, r.can("records.unscoped"))
+	g.Post("/", r.h.Document.Create, r.can("records.unscoped"))
+	g.Put("/:id", r.h.Document.Update, r.can("records.unscoped"))
+	g.Delete("/:id", r.h.Document.Delete, r.can("records.unscoped"))
+	g.Patch("/:id/restore", r.h.Document.Restore, r.can("records.unscoped"))
+	g.Delete("/:id/purge", r.h.Document.Purge, r.can("records.purge"))
+}
+
+// setupEventRoutes configures event routes (unscoped, see setupDocumentTemplateRoutes)
 func (r *DXRouter) setupEventRoutes(api fiber.Router) {
-	g := api.Group("/events")
-	g.Get("/", r.h.Event.GetAll)
-	g.Get("/public", r.h.Event.GetPublic)
-	g.Get("/status/:status", r.h.Event.GetByStatus)
-	g.Get("/:id", r.h.Event.GetByID)
-	g.Get("/code/:code", r.h.Event.GetByCode)
-	g.Post("/", r.h.Event.Create)
-	g.Put("/:id", r.h.Event.Update)
-	g.Delete("/:id", r.h.Event.Delete)
-}
-
-// setupEventParticipantRoutes configures event participant routes
+	g := api.Group("/events", r.audit)
+	g.Get("/", r.h.Event.GetAll, r.can("records.unscoped"))
+	g.Get("/public", r.h.Event.GetPublic, r.can("events.public"))
+	g.Get("/status/:status", r.h.Event.GetByStatus, r.can("records.unscoped"))
+	g.Get("/:id", r.h.Event.GetByID, r.can("records.unscoped"))
+	g.Get("/code/:code", r.h.Event.GetByCode, r.can("records.unscoped"))
+	g.Post("/", r.h.Event.Create, r.can("records.unscoped"))
+	g.Put("/:id", r.h.Event.Update, r.can("records.unscoped"))
+	g.Delete("/:id", r.h.Event.Delete, r.can("records.unscoped"))
+}
+
+// setupEventParticipantRoutes configures event participant routes (unscoped, see setupDocumentTemplateRoutes)
 func (r *DXRouter) setupEventParticipantRoutes(api fiber.Router) {
-	g := api.Group("/event-participants")
-	g.Get("/:id", r.h.EventParticipant.GetByID)
-	g.Get("/event/:eventId", r.h.EventParticipant.GetByEventID)
-	g.Get("/event/:eventId/count", r.h.EventParticipant.CountByEventID)
-	g.Get("/user-detail/:userDetailId", r.h.EventParticipant.GetByUserDetailID)
-	g.Post("/", r.h.EventParticipant.Create)
-	g.Put("/:id", r.h.EventParticipant.Update)
-	g.Delete("/:id", r.h.EventParticipant.Delete)
+	g := api.Group("/event-participants", r.audit)
+	g.Get("/:id", r.h.EventParticipant.GetByID, r.can("records.unscoped"))
+	g.Get("/event/:eventId", r.h.EventParticipant.GetByEventID, r.can("records.unscoped"))
+	g.Get("/event/:eventId/count", r.h.EventParticipant.CountByEventID, r.can("records.unscoped"))
+	g.Get("/user-detail/:userDetailId", r.h.EventParticipant.GetByUserDetailID, r.can("records.unscoped"))
+	g.Post("/", r.h.EventParticipant.Create, r.can("records.unscoped"))
+	g.Put("/:id", r.h.EventParticipant.Update, r.can("records.unscoped"))
+	g.Delete("/:id", r.h.EventParticipant.Delete, r.can("records.unscoped"))
 }
 
 // setupNotificationRoutes configures notification routes
 func (r *DXRouter) setupNotificationRoutes(api fiber.Router) {
-	g := api.Group("/notifications")
-	g.Get("/:id", r.h.Notification.GetByID)
-	g.Get("/user/:userId", r.h.Notification.GetByUserID)
-	g.Get("/user/:userId/unread", r.h.Notification.GetUnreadByUserID)
-	g.Get("/user/:userId/unread/count", r.h.Notification.CountUnreadByUserID)
-	g.Post("/", r.h.Notification.Create)
-	g.Patch("/:id/read", r.h.Notification.MarkAsRead)
-	g.Patch("/user/:userId/read-all", r.h.Notification.MarkAllAsRead)
-	g.Delete("/:id", r.h.Notification.Delete)
+	g := api.Group("/notifications", r.audit)
+	g.Get("/:id", r.h.Notification.GetByID, r.can("notifications.read"))
+	g.Get("/user/:userId", r.h.Notification.GetByUserID, r.can("notifications.read"))
+	g.Get("/user/:userId/unread", r.h.Notification.GetUnreadByUserID, r.can("notifications.read"))
+	g.Get("/user/:userId/unread/count", r.h.Notification.CountUnreadByUserID, r.can("notifications.read"))
+	g.Post("/", r.h.Notification.Create, r.can("notifications.write"))
+	g.Patch("/:id/read", r.h.Notification.MarkAsRead, r.can("notifications.write"))
+	g.Patch("/user/:userId/read-all", r.h.Notification.MarkAllAsRead, r.can("notifications.write"))
+	g.Delete("/:id", r.h.Notification.Delete, r.can("notifications.write"))
 }
 
 // setupEvaluationRoutes configures evaluation routes
 func (r *DXRouter) setupEvaluationRoutes(api fiber.Router) {
-	g := api.Group("/evaluations")
-	g.Get("/", r.h.Evaluation.GetAll)
-	g.Get("/:id", r.h.Evaluation.GetByID)
-	g.Get("/user/:userId", r.h.Evaluation.GetByUserID)
-	g.Post("/", r.h.Evaluation.Create)
-	g.Put("/:id", r.h.Evaluation.Update)
-	g.Delete("/:id", r.h.Evaluation.Delete)
+	g := api.Group("/evaluations", r.audit)
+	g.Get("/", r.h.Evaluation.GetAll, r.can("evaluations.read"))
+	g.Get("/:id", r.h.Evaluation.GetByID, r.can("evaluations.read"))
+	g.Get("/user/:userId", r.h.Evaluation.GetByUserID, r.can("evaluations.read"))
+	g.Post("/", r.h.Evaluation.Create, r.can("evaluations.write"))
+	g.Put("/:id", r.h.Evaluation.Update, r.can("evaluations.write"))
+	g.Delete("/:id", r.h.Evaluation.Delete, r.can("evaluations.write"))
 }
 
 // setupStudyMaterialRoutes configures study material routes
 func (r *DXRouter) setupStudyMaterialRoutes(api fiber.Router) {
-	g := api.Group("/study-materials")
-	g.Get("/", r.h.StudyMaterial.GetAll)
-	g.Get("/:id", r.h.StudyMaterial.GetByID)
-	g.Post("/", r.h.StudyMaterial.Create)
-	g.Put("/:id", r.h.StudyMaterial.Update)
-	g.Delete("/:id", r.h.StudyMaterial.Delete)
+	g := api.Group("/study-materials", r.audit)
+	g.Get("/", r.h.StudyMaterial.GetAll, r.can("study_materials.read"))
+	g.Get("/:id", r.h.StudyMaterial.GetByID, r.can("study_materials.read"))
+	g.Post("/", r.h.StudyMaterial.Create, r.can("study_materials.write"))
+	g.Put("/:id", r.h.StudyMaterial.Update, r.can("study_materials.write"))
+	g.Delete("/:id", r.h.StudyMaterial.Delete, r.can("study_materials.write"))
 }
\ No newline at end of file
@@ -4,63 +4,224 @@ import (
 	"github.com/gofiber/fiber/v3"
 
 	"server/internal/handler"
+	"server/internal/middleware"
 )
 
 // FNHandlers groups all handlers for the FN (Functional) module
 type FNHandlers struct {
-	DocumentTemplate *handler.FNDocumentTemplateHandler
-	Event            *handler.FNEventHandler
-	DocumentAction   *handler.FNDocumentActionHandler
+	DocumentTemplate  *handler.FNDocumentTemplateHandler
+	Event             *handler.FNEventHandler
+	EventParticipant  *handler.FNEventParticipantHandler
+	DocumentAction    *handler.FNDocumentActionHandler
+	Export            *handler.FNExportHandler
+	DocumentArchive   *handler.FNDocumentArchiveHandler
+	DocumentDownload  *handler.FNDocumentDownloadHandler
+	Me                *handler.FNMeHandler
+	APIKey            *handler.FNAPIKeyHandler
+	Audit             *handler.FNAuditHandler
+	Revocation        *handler.FNRevocationHandler
+	Notification      *handler.FNNotificationHandler
+	NotificationInbox *handler.FNNotificationInboxHandler
+	Evaluation        *handler.FNEvaluationHandler
+	QuestionBank      *handler.FNQuestionBankHandler
+	StudyMaterial     *handler.FNStudyMaterialHandler
+}
+
+// documentActionPermissions maps each document action to the permission it requires
+var documentActionPermissions = map[string]string{
+	"reg_doc":     "documents.register",
+	"sync_doc":    "documents.register",
+	"gen_doc":     "documents.generate",
+	"doc_reject":  "documents.reject",
+	"doc_renew":   "documents.renew",
+	"doc_reissue": "documents.reissue",
 }
 
 // FNRouter handles FN (Functional) related routes
 type FNRouter struct {
-	h *FNHandlers
+	h     *FNHandlers
+	authz *middleware.Authorizer
+	audit fiber.Handler
 }
 
-// NewFNRouter creates a new FNRouter instance
-func NewFNRouter(handlers *FNHandlers) *FNRouter {
-	return &FNRouter{h: handlers}
+// NewFNRouter creates a new FNRouter instance; audit is mounted on the management groups
+func NewFNRouter(handlers *FNHandlers, authz *middleware.Authorizer, audit fiber.Handler) *FNRouter {
+	return &FNRouter{h: handlers, authz: authz, audit: audit}
+}
+
+// can returns the middleware enforcing a permission from the matrix
+func (r *FNRouter) can(permission string) fiber.Handler {
+	return r.authz.Require(permission)
+}
+
+// SetupPublicRoutes configures certificate status routes (public)
+func (r *FNRouter) SetupPublicRoutes(app *fiber.App) {
+	public := app.Group("/public")
+
+	public.Get("/revocation-list", r.h.Revocation.List)
+	public.Get("/revocation-list/keys", r.h.Revocation.Keys)
+	public.Get("/documents/verify/:verificationCode", r.h.Revocation.Verify)
 }
 
 // SetupRoutes configures all FN routes (protected)
 func (r *FNRouter) SetupRoutes(api fiber.Router) {
+	api.Get("/me", r.h.Me.Get, r.can("profile.read"))
+	api.Get("/me/documents", r.h.Me.ListDocuments, r.can("profile.read"))
+	api.Get("/me/events", r.h.Me.ListEvents, r.can("profile.read"))
+	api.Get("/me/notification-preferences", r.h.Notification.GetPreferences, r.can("profile.read"))
+	api.Put("/me/notification-preferences", r.h.Notification.UpdatePreferences, r.can("profile.write"))
+	api.Get("/audit", r.h.Audit.List, r.can("audit.read"))
+	api.Get("/audit/verify", r.h.Audit.Verify, r.can("audit.read"))
+
+	r.setupMyNotificationRoutes(api)
+
 	fn := api.Group("/fn")
 
 	r.setupDocumentTemplateRoutes(fn)
 	r.setupEventRoutes(fn)
 	r.setupDocumentRoutes(fn)
+	r.setupAPIKeyRoutes(fn)
+	r.setupNotificationRoutes(fn)
+	r.setupEvaluationRoutes(fn)
+	r.setupStudyMaterialRoutes(fn)
+}
+
+// setupMyNotificationRoutes configures the caller's own inbox; the user comes from the token
+func (r *FNRouter) setupMyNotificationRoutes(api fiber.Router) {
+	g := api.Group("/me/notifications")
+	g.Get("/", r.h.NotificationInbox.List, r.can("profile.read"))
+	g.Get("/unread-count", r.h.NotificationInbox.UnreadCount, r.can("profile.read"))
+	g.Get("/stream", r.h.NotificationInbox.Stream, r.can("profile.read"))
+	g.Post("/read", r.h.NotificationInbox.MarkRead, r.can("profile.write"))
+	g.Post("/read-all", r.h.NotificationInbox.MarkAllRead, r.can("profile.write"))
+	g.Delete("/", r.h.NotificationInbox.DeleteOlderThan, r.can("profile.write"))
 }
 
 func (r *FNRouter) setupDocumentTemplateRoutes(fn fiber.Router) {
-	g := fn.Group("/document-templates")
-
-	g.Get("/", r.h.DocumentTemplate.List)
-	g.Get("/:id", r.h.DocumentTemplate.GetByID)
-	g.Get("/code/:code", r.h.DocumentTemplate.GetByCode)
-	g.Post("/", r.h.DocumentTemplate.Create)
-	g.Put("/:id", r.h.DocumentTemplate.Update)
-	g.Patch("/:id/enable", r.h.DocumentTemplate.Enable)
-	g.Patch("/:id/disable", r.h.DocumentTemplate.Disable)
-	g.Delete("/:id", r.h.DocumentTemplate.Delete)
+	g := fn.Group("/document-templates", r.audit)
+
+	g.Get("/", r.h.DocumentTemplate.List, r.can("catalog.read"))
+	g.Get("/:id", r.h.DocumentTemplate.GetByID, r.can("catalog.read"))
+	g.Get("/code/:code", r.h.DocumentTemplate.GetByCode, r.can("catalog.read"))
+	g.Post("/", r.h.DocumentTemplate.Create, r.can("catalog.write"))
+	g.Put("/:id", r.h.DocumentTemplate.Update, r.can("catalog.write"))
+	g.Patch("/:id/enable", r.h.DocumentTemplate.Enable, r.can("catalog.write"))
+	g.Patch("/:id/disable", r.h.DocumentTemplate.Disable, r.can("catalog.write"))
+	g.Delete("/:id", r.h.DocumentTemplate.Delete, r.can("catalog.write"))
+	g.Patch("/:id/restore", r.h.DocumentTemplate.Restore, r.can("catalog.write"))
+	g.Delete("/:id/purge", r.h.DocumentTemplate.Purge, r.can("records.purge"))
 }
 
 func (r *FNRouter) setupEventRoutes(fn fiber.Router) {
-	g := fn.Group("/events")
-
-	g.Get("/", r.h.Event.List)
-	g.Get("/:id", r.h.Event.GetByID)
-	g.Get("/code/:code", r.h.Event.GetByCode)
-	g.Post("/", r.h.Event.Create)
-	g.Put("/:id", r.h.Event.Update)
-	g.Delete("/:id", r.h.Event.Delete)
+	g := fn.Group("/events", r.audit)
+
+	g.Get("/", r.h.Event.List, r.can("events.read"))
+	g.Get("/:id", r.h.Event.GetByID, r.can("events.read"))
+	g.Get("/code/:code", r.h.Event.GetByCode, r.can("events.read"))
+	g.Post("/", r.h.Event.Create, r.can("events.write"))
+	g.Put("/:id", r.h.Event.Update, r.can("events.write"))
+	g.Delete("/:id", r.h.Event.Delete, r.can("events.write"))
+	g.Patch("/:id/restore", r.h.Event.Restore, r.can("events.write"))
+	g.Delete("/:id/purge", r.h.Event.Purge, r.can("records.purge"))
+	g.Get("/:id/export", r.h.Export.ExportEventRegister, r.can("events.export"))
+
+	// participants sub-resource
+	p := g.Group("/:id/participants")
+	p.Get("/", r.h.EventParticipant.List, r.can("participants.read"))
+	p.Get("/progress", r.h.EventParticipant.Progress, r.can("participants.read"))
+	p.Post("/", r.h.EventParticipant.Add, r.can("participants.write"))
+	p.Patch("/status", r.h.EventParticipant.BulkUpdateStatus, r.can("participants.write"))
+	p.Patch("/:participantId", r.h.EventParticipant.Patch, r.can("participants.write"))
+	p.Delete("/:participantId", r.h.EventParticipant.Remove, r.can("participants.write"))
 }
 
 func (r *FNRouter) setupDocumentRoutes(fn fiber.Router) {
-	g := fn.Group("/documents")
+	g := fn.Group("/documents", r.audit)
+
+	g.Get("/", r.h.DocumentAction.List, r.can("documents.read"))
+
+	// bulk zip archives (registered before /:id)
+	g.Get("/archive", r.h.DocumentArchive.Download, r.can("documents.archive"))
+	g.Post("/archives", r.h.DocumentArchive.CreateJob, r.can("documents.archive"))
+	g.Get("/archives/:id", r.h.DocumentArchive.GetJob, r.can("documents.archive"))
+	g.Get("/archives/:id/download", r.h.DocumentArchive.DownloadJob, r.can("documents.archive"))
+
+	g.Get("/:id", r.h.DocumentAction.GetByID, r.can("documents.read"))
+	g.Get("/:id/pdf", r.h.DocumentDownload.DownloadPDF, r.can("documents.download"))
+	g.Get("/serial/:serial_code", r.h.DocumentAction.GetBySerialCode, r.can("documents.read"))
+	g.Post("/actions", r.h.DocumentAction.ExecuteAction, r.authz.RequireByBodyField("action", documentActionPermissions, "documents.write"))
+}
+
+func (r *FNRouter) setupAPIKeyRoutes(fn fiber.Router) {
+	g := fn.Group("/api-keys", r.audit)
+
+	g.Get("/", r.h.APIKey.List, r.can("api_keys.manage"))
+	g.Post("/", r.h.APIKey.Create, r.can("api_keys.manage"))
+	g.Delete("/:id", r.h.APIKey.Revoke, r.can("api_keys.manage"))
+}
+
+func (r *FNRouter) setupNotificationRoutes(fn fiber.Router) {
+	g := fn.Group("/notification-deliveries", r.audit)
+
+	g.Get("/", r.h.Notification.ListDeliveries, r.can("notifications.read"))
+	g.Post("/:id/retry", r.h.Notification.RetryDelivery, r.can("notifications.write"))
+}
+
+func (r *FNRouter) setupEvaluationRoutes(fn fiber.Router) {
+	// starting an attempt is the learner's own action, so only authoring is audited
+	g := fn.Group("/evaluations")
+
+	g.Post("/", r.h.Evaluation.Create, r.audit, r.can("evaluations.write"))
+	g.Get("/:id", r.h.Evaluation.GetByID, r.can("evaluations.read"))
+	g.Patch("/:id", r.h.Evaluation.Update, r.audit, r.can("evaluations.write"))
+	g.Put("/:id/questions", r.h.Evaluation.ReplaceQuestions, r.audit, r.can("evaluations.write"))
+	g.Put("/:id/draw-rules", r.h.Evaluation.ReplaceDrawRules, r.audit, r.can("evaluations.write"))
+	g.Get("/:id/attempts", r.h.Evaluation.ListAttempts, r.can("evaluations.read"))
+	g.Post("/:id/start", r.h.Evaluation.Start, r.can("evaluations.take"))
+
+	// attempts are checked against the caller; only regenerating a report is management
+	a := fn.Group("/evaluation-attempts")
+	a.Get("/:id", r.h.Evaluation.GetAttempt, r.can("evaluations.take"))
+	a.Post("/:id/submit", r.h.Evaluation.Submit, r.can("evaluations.take"))
+	a.Get("/:id/report", r.h.Evaluation.DownloadReport, r.can("evaluations.take"))
+	a.Post("/:id/report", r.h.Evaluation.RegenerateReport, r.audit, r.can("evaluations.review"))
+
+	rv := fn.Group("/evaluation-reviews", r.audit)
+	rv.Get("/", r.h.Evaluation.ListReviewQueue, r.can("evaluations.review"))
+	rv.Post("/:scoreId", r.h.Evaluation.Review, r.can("evaluations.review"))
+
+	b := fn.Group("/question-bank", r.audit)
+	b.Get("/", r.h.QuestionBank.List, r.can("evaluations.read"))
+	b.Get("/stats", r.h.QuestionBank.ItemStats, r.can("evaluations.read"))
+	b.Get("/:id", r.h.QuestionBank.GetByID, r.can("evaluations.read"))
+	b.Post("/", r.h.QuestionBank.Create, r.can("evaluations.write"))
+	b.Put("/:id", r.h.QuestionBank.Update, r.can("evaluations.write"))
+	b.Patch("/:id/enable", r.h.QuestionBank.Enable, r.can("evaluations.write"))
+	b.Patch("/:id/disable", r.h.QuestionBank.Disable, r.can("evaluations.write"))
+}
+
+// setupStudyMaterialRoutes configures the learning path; progress and annotations belong to the caller
+func (r *FNRouter) setupStudyMaterialRoutes(fn fiber.Router) {
+	m := fn.Group("/study-materials", r.audit)
+	m.Get("/:id/outline", r.h.StudyMaterial.GetOutline, r.can("study_materials.read"))
+	m.Get("/:id/progress", r.h.StudyMaterial.GetProgress, r.can("study_materials.read"))
+	m.Post("/:id/sections", r.h.StudyMaterial.CreateSection, r.can("study_materials.write"))
+	m.Put("/:id/sections/order", r.h.StudyMaterial.ReorderSections, r.can("study_materials.write"))
+
+	sec := fn.Group("/study-sections", r.audit)
+	sec.Put("/:id", r.h.StudyMaterial.UpdateSection, r.can("study_materials.write"))
+	sec.Delete("/:id", r.h.StudyMaterial.DeleteSection, r.can("study_materials.write"))
+	sec.Post("/:id/subsections", r.h.StudyMaterial.CreateSubsection, r.can("study_materials.write"))
+	sec.Put("/:id/subsections/order", r.h.StudyMaterial.ReorderSubsections, r.can("study_materials.write"))
+
+	sub := fn.Group("/study-subsections")
+	sub.Put("/:id", r.h.StudyMaterial.UpdateSubsection, r.audit, r.can("study_materials.write"))
+	sub.Delete("/:id", r.h.StudyMaterial.DeleteSubsection, r.audit, r.can("study_materials.write"))
+	sub.Put("/:id/progress", r.h.StudyMaterial.SetProgress, r.can("study_materials.learn"))
+	sub.Get("/:id/annotations", r.h.StudyMaterial.ListAnnotations, r.can("study_materials.learn"))
+	sub.Post("/:id/annotations", r.h.StudyMaterial.CreateAnnotation, r.can("study_materials.learn"))
 
-	g.Get("/", r.h.DocumentAction.List)
-	g.Get("/:id", r.h.DocumentAction.GetByID)
-	g.Get("/serial/:serial_code", r.h.DocumentAction.GetBySerialCode)
-	g.Post("/actions", r.h.DocumentAction.ExecuteAction)
+	an := fn.Group("/study-annotations")
+	an.Put("/:id", r.h.StudyMaterial.UpdateAnnotation, r.can("study_materials.learn"))
+	an.Delete("/:id", r.h.StudyMaterial.DeleteAnnotation, r.can("study_materials.learn"))
 }
\ No newline at end of file
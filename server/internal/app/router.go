@@ -14,19 +14,35 @@ type Router struct {
 	app      *fiber.App
 	dxRouter *DXRouter
 	fnRouter *FNRouter
+	authz    *middleware.Authorizer
+	users    middleware.UserProvisioner
+	apiKeys  middleware.APIKeyVerifier
+	keycloak *middleware.KeycloakMiddleware
 }
 
 // RouterConfig holds handler groups for each module
 type RouterConfig struct {
-	DX *DXHandlers
-	FN *FNHandlers
+	DX       *DXHandlers
+	FN       *FNHandlers
+	Authz    *middleware.Authorizer
+	Keycloak *middleware.KeycloakMiddleware
+	Users    middleware.UserProvisioner
+	APIKeys  middleware.APIKeyVerifier
+	Audit    middleware.AuditRecorder
 }
 
-// NewRouter creates a new Router instance
+// NewRouter creates a new Router instance. The audit trail is only mounted on the
+// admin and management route groups, not on the caller's own profile, inbox,
+// evaluation attempts or study progress.
 func NewRouter(cfg RouterConfig) *Router {
+	audit := middleware.Audit(cfg.Audit)
 	return &Router{
-		dxRouter: NewDXRouter(cfg.DX),
-		fnRouter: NewFNRouter(cfg.FN),
+		dxRouter: NewDXRouter(cfg.DX, cfg.Authz, audit),
+		fnRouter: NewFNRouter(cfg.FN, cfg.Authz, audit),
+		authz:    cfg.Authz,
+		users:    cfg.Users,
+		apiKeys:  cfg.APIKeys,
+		keycloak: cfg.Keycloak,
 	}
 }
 
@@ -44,10 +60,19 @@ func (r *Router) Setup() *fiber.App {
 
 	// Health routes (public - no auth required)
 	r.dxRouter.SetupHealthRoutes(app)
+	r.fnRouter.SetupPublicRoutes(app)
+
+	// The notification stream also accepts the token as ?access_token= (EventSource)
+	app.Use("/api/v1/me/notifications/stream", middleware.BearerFromQuery("access_token"))
 
 	// API v1 routes (protected)
 	api := app.Group("/api/v1")
-	api.Use(middleware.KeycloakAuth())
+	api.Use(middleware.Authenticate(
+		r.keycloak.Authenticator(),
+		middleware.APIKeyAuthenticator(r.apiKeys),
+	))
+	api.Use(middleware.UserProvisioning(r.users, r.authz))
+	api.Use(r.authz.OrgScope())
 
 	// Setup sub-routers
 	r.dxRouter.SetupRoutes(api) // DX: Basic CRUD operations
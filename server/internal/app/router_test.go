@@ -0,0 +1,82 @@
+package app
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"server/internal/middleware"
+)
+
+// permissionCalls are the helpers whose string literal arguments name a permission
+var permissionCalls = map[string]bool{"can": true, "Require": true, "RequireByBodyField": true, "Can": true}
+
+// routePermissions collects the permission literals passed to permissionCalls in the given files
+func routePermissions(t *testing.T, patterns ...string) map[string]string {
+	t.Helper()
+	found := map[string]string{}
+	fset := token.NewFileSet()
+
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil || len(files) == 0 {
+			t.Fatalf("no files match %s: %v", pattern, err)
+		}
+		for _, file := range files {
+			f, err := parser.ParseFile(fset, file, nil, 0)
+			if err != nil {
+				t.Fatalf("parse %s: %v", file, err)
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !permissionCalls[sel.Sel.Name] {
+					return true
+				}
+				for _, arg := range call.Args {
+					lit, ok := arg.(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					value, _ := strconv.Unquote(lit.Value)
+					// RequireByBodyField takes the body field name first
+					if sel.Sel.Name == "RequireByBodyField" && arg == call.Args[0] {
+						continue
+					}
+					found[value] = fset.Position(lit.Pos()).String()
+				}
+				return true
+			})
+		}
+	}
+	return found
+}
+
+func TestRoutePermissionsExistInMatrix(t *testing.T) {
+	authz, err := middleware.NewAuthorizer(middleware.AuthorizerConfig{})
+	if err != nil {
+		t.Fatalf("NewAuthorizer: %v", err)
+	}
+	matrix := authz.Permissions()
+
+	used := routePermissions(t, "dx_router.go", "fn_router.go", "../handler/*.go")
+	for action, perm := range documentActionPermissions {
+		used[perm] = "documentActionPermissions[" + action + "]"
+	}
+	if len(used) < 10 {
+		t.Fatalf("found only %d permissions, the route parser is probably broken", len(used))
+	}
+
+	for perm, at := range used {
+		if !slices.Contains(matrix, perm) {
+			t.Errorf("%s: permission %q is missing from permissions.yml", at, perm)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+// Package filesvc is a typed HTTP client for file-svc, the service that stores
+// certificate templates and generated PDFs.
+//
+// file-svc only exposes POST /upload and GET /download (plus /health). It has no
+// delete, metadata or presigned-URL endpoints: stored files are never removed from
+// here, existence is checked through the download route, and every download is
+// proxied through this server.
+package filesvc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when file-svc does not know the requested file
+	ErrNotFound = errors.New("file not found in file-svc")
+
+	// ErrUnsupported is returned when the running file-svc does not expose the endpoint
+	ErrUnsupported = errors.New("operation not supported by file-svc")
+
+	// ErrChecksumMismatch is returned by a verified download whose content does not match the expected SHA-256
+	ErrChecksumMismatch = errors.New("file checksum mismatch")
+)
+
+// Config holds the file-svc client configuration
+type Config struct {
+	BaseURL string
+	Timeout time.Duration
+
+	// MaxRetries is the number of extra attempts for transient failures (network errors, 429, 5xx)
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// Client talks to file-svc over HTTP
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// File represents a downloaded file. Body must be closed by the caller.
+type File struct {
+	Body          io.ReadCloser
+	ContentType   string
+	FileName      string
+	ContentLength int64
+}
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	ID           uuid.UUID `json:"id"`
+	OriginalName string    `json:"original_name"`
+	Size         int64     `json:"size"`
+	MimeType     string    `json:"mime_type"`
+	IsPublic     bool      `json:"is_public"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UploadRequest holds a file to be stored in file-svc
+type UploadRequest struct {
+	UserID      string
+	IsPublic    bool
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// apiResponse mirrors the file-svc response envelope
+type apiResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Details string `json:"details"`
+	} `json:"error"`
+}
+
+// New creates a new file-svc client
+func New(cfg Config) *Client {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+	retries := cfg.MaxRetries
+	if retries < 0 {
+		retries = 0
+	}
+
+	return &Client{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		httpClient:   &http.Client{Timeout: timeout},
+		maxRetries:   retries,
+		retryBackoff: backoff,
+	}
+}
+
+// Upload stores a new file and returns its metadata
+// POST /upload (multipart: user_id, is_public, file)
+func (c *Client) Upload(ctx context.Context, req UploadRequest) (*FileInfo, error) {
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("file content is required")
+	}
+	if req.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("user_id", req.UserID)
+	_ = mw.WriteField("is_public", strconv.FormatBool(req.IsPublic))
+
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(req.FileName)))
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("error building upload request: %w", err)
+	}
+	if _, err := part.Write(req.Data); err != nil {
+		return nil, fmt.Errorf("error building upload request: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("error building upload request: %w", err)
+	}
+
+	payload := body.Bytes()
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/upload", bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp, "upload")
+	}
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("error decoding upload response: %w", err)
+	}
+
+	var info FileInfo
+	if err := json.Unmarshal(envelope.Data, &info); err != nil {
+		return nil, fmt.Errorf("error decoding uploaded file info: %w", err)
+	}
+	return &info, nil
+}
+
+// Download fetches the content of a file by its ID
+// GET /download?file_id=uuid
+func (c *Client) Download(ctx context.Context, fileID uuid.UUID) (*File, error) {
+	endpoint := c.downloadURL(fileID)
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, decodeError(resp, "download")
+	}
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+
+	return &File{
+		Body:          resp.Body,
+		ContentType:   resp.Header.Get("Content-Type"),
+		FileName:      fileNameFromDisposition(resp.Header.Get("Content-Disposition")),
+		ContentLength: size,
+	}, nil
+}
+
+// DownloadVerified fetches a file and checks its content against the expected
+// SHA-256 hex digest (as stored in DocumentPDF.FileHash). The check runs while
+// the body is read: the final Read returns ErrChecksumMismatch instead of io.EOF
+// when the content does not match.
+func (c *Client) DownloadVerified(ctx context.Context, fileID uuid.UUID, expectedSHA256 string) (*File, error) {
+	expected := strings.ToLower(strings.TrimSpace(expectedSHA256))
+	if expected == "" {
+		return nil, fmt.Errorf("expected sha256 is required")
+	}
+
+	file, err := c.Download(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Body = &verifyingReader{
+		rc:       file.Body,
+		hash:     sha256.New(),
+		expected: expected,
+	}
+	return file, nil
+}
+
+// Exists reports whether file-svc knows the given file. file-svc has no metadata
+// endpoint, so this sends a HEAD to the download route: the status and headers come
+// back without the content. A file-svc that rejects HEAD is asked with a download
+// that is closed once the status line is in.
+// HEAD /download?file_id=uuid
+func (c *Client) Exists(ctx context.Context, fileID uuid.UUID) (bool, error) {
+	endpoint := c.downloadURL(fileID)
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"):
+		// the JSON error envelope (without its body) means file-svc looked the file up
+		return false, nil
+	case resp.StatusCode == http.StatusMethodNotAllowed, resp.StatusCode == http.StatusNotImplemented:
+		return c.existsByDownload(ctx, fileID)
+	default:
+		return false, decodeError(resp, "exists")
+	}
+}
+
+func (c *Client) existsByDownload(ctx context.Context, fileID uuid.UUID) (bool, error) {
+	file, err := c.Download(ctx, fileID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	file.Body.Close()
+	return true, nil
+}
+
+func (c *Client) downloadURL(fileID uuid.UUID) string {
+	return fmt.Sprintf("%s/download?file_id=%s", c.baseURL, url.QueryEscape(fileID.String()))
+}
+
+// do sends a request built by newReq, retrying transient failures with
+// exponential backoff. newReq is called once per attempt so bodies can be replayed.
+func (c *Client) do(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	backoff := c.retryBackoff
+
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, fmt.Errorf("error building file-svc request: %w", err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+
+		if !retryable || attempt >= c.maxRetries || ctx.Err() != nil {
+			if err != nil {
+				return nil, fmt.Errorf("error calling file-svc: %w", err)
+			}
+			return resp, nil
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error calling file-svc: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// decodeError maps a non-success file-svc response to an error
+func decodeError(resp *http.Response, op string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	var envelope apiResponse
+	isJSON := json.Unmarshal(body, &envelope) == nil
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && isJSON:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusMethodNotAllowed,
+		resp.StatusCode == http.StatusNotImplemented:
+		// a bare 404/405 comes from the router, not from a missing file
+		return fmt.Errorf("%s: %w", op, ErrUnsupported)
+	case isJSON && envelope.Message != "":
+		return fmt.Errorf("file-svc %s failed, status: %d: %s", op, resp.StatusCode, envelope.Message)
+	default:
+		return fmt.Errorf("file-svc %s failed, status: %d", op, resp.StatusCode)
+	}
+}
+
+// verifyingReader hashes everything read and checks the digest at EOF
+type verifyingReader struct {
+	rc       io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.rc.Read(p)
+	if n > 0 {
+		v.hash.Write(p[:n])
+	}
+	if errors.Is(err, io.EOF) {
+		if got := hex.EncodeToString(v.hash.Sum(nil)); got != v.expected {
+			return n, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, v.expected, got)
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.rc.Close()
+}
+
+func fileNameFromDisposition(disposition string) string {
+	for _, part := range strings.Split(disposition, ";") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "filename=") {
+			return strings.Trim(strings.TrimPrefix(part, "filename="), `"`)
+		}
+	}
+	return ""
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
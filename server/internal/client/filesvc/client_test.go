@@ -0,0 +1,176 @@
+package filesvc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/client/filesvc"
+)
+
+const pdfContent = "%PDF-1.4 certificate"
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func newClient(url string, retries int) *filesvc.Client {
+	return filesvc.New(filesvc.Config{BaseURL: url, MaxRetries: retries, RetryBackoff: time.Millisecond})
+}
+
+func notFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = io.WriteString(w, `{"status":"error","message":"file not found"}`)
+}
+
+func TestDownloadRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="cert.pdf"`)
+		_, _ = io.WriteString(w, pdfContent)
+	}))
+	defer srv.Close()
+
+	file, err := newClient(srv.URL, 2).Download(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	defer file.Body.Close()
+	if body, _ := io.ReadAll(file.Body); string(body) != pdfContent || file.FileName != "cert.pdf" {
+		t.Fatalf("got %q (%s)", body, file.FileName)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("calls = %d, want 3", calls.Load())
+	}
+
+	calls.Store(0)
+	if _, err := newClient(srv.URL, 1).Download(context.Background(), uuid.New()); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    error
+	}{
+		{"file-svc error envelope", func(w http.ResponseWriter, _ *http.Request) { notFound(w) }, filesvc.ErrNotFound},
+		{"bare router 404", http.NotFound, filesvc.ErrUnsupported},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			if _, err := newClient(srv.URL, 2).Download(context.Background(), uuid.New()); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestDownloadVerified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, pdfContent)
+	}))
+	defer srv.Close()
+	client := newClient(srv.URL, 0)
+
+	cases := []struct {
+		name     string
+		expected string
+		want     error
+	}{
+		{"matching digest", sha256Hex(pdfContent), nil},
+		{"matching digest in upper case", " " + strings.ToUpper(sha256Hex(pdfContent)) + " ", nil},
+		{"tampered content", sha256Hex("another file"), filesvc.ErrChecksumMismatch},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := client.DownloadVerified(context.Background(), uuid.New(), tc.expected)
+			if err != nil {
+				t.Fatalf("DownloadVerified: %v", err)
+			}
+			defer file.Body.Close()
+
+			body, err := io.ReadAll(file.Body)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("read error = %v, want %v", err, tc.want)
+			}
+			if string(body) != pdfContent {
+				t.Fatalf("body = %q", body)
+			}
+		})
+	}
+
+	if _, err := client.DownloadVerified(context.Background(), uuid.New(), " "); err == nil {
+		t.Fatal("expected an error without a digest")
+	}
+}
+
+func TestExists(t *testing.T) {
+	known := uuid.New()
+
+	cases := []struct {
+		name          string
+		rejectHead    bool
+		wantDownloads int32
+	}{
+		{"HEAD on the download route", false, 0},
+		{"file-svc without HEAD", true, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var bodies atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead && tc.rejectHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				if r.URL.Query().Get("file_id") != known.String() {
+					notFound(w)
+					return
+				}
+				if r.Method == http.MethodGet {
+					bodies.Add(1)
+				}
+				_, _ = io.WriteString(w, pdfContent)
+			}))
+			defer srv.Close()
+			client := newClient(srv.URL, 0)
+
+			if ok, err := client.Exists(context.Background(), known); err != nil || !ok {
+				t.Fatalf("Exists(known) = %v, %v", ok, err)
+			}
+			if ok, err := client.Exists(context.Background(), uuid.New()); err != nil || ok {
+				t.Fatalf("Exists(unknown) = %v, %v", ok, err)
+			}
+			if bodies.Load() != tc.wantDownloads {
+				t.Fatalf("full downloads = %d, want %d", bodies.Load(), tc.wantDownloads)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -12,6 +14,13 @@ type Config struct {
 	Redis    RedisConfig
 	NATS     NATSConfig
 	Keycloak KeycloakConfig
+	FileSvc  FileSvcConfig
+	Archive  ArchiveConfig
+	RevList  RevocationListConfig
+	SMTP     SMTPConfig
+	Notify   NotificationConfig
+	Cert     CertificateConfig
+	Report   EvaluationReportConfig
 }
 
 type ServerConfig struct {
@@ -44,8 +53,77 @@ type NATSConfig struct {
 }
 
 type KeycloakConfig struct {
-	SSOURL string
-	Realm  string
+	SSOURL          string
+	Realm           string
+	ClientID        string
+	PermissionsFile string
+
+	// Token validation
+	Issuer            string
+	JWKSFile          string
+	Audiences         []string
+	AuthorizedParties []string
+	ClockSkewSeconds  int
+
+	// User provisioning
+	RequireNationalID      bool
+	NationalIDAdminManaged bool
+}
+
+type FileSvcConfig struct {
+	URL            string
+	TimeoutSeconds int
+	MaxRetries     int
+	RetryBackoffMS int
+}
+
+type ArchiveConfig struct {
+	Dir       string
+	TTLHours  int
+	SyncLimit int
+}
+
+type SMTPConfig struct {
+	Host           string
+	Port           int
+	Username       string
+	Password       string
+	From           string
+	TLSMode        string
+	TimeoutSeconds int
+}
+
+type NotificationConfig struct {
+	TemplatesFile       string
+	DefaultLocale       string
+	VerifyBaseURL       string
+	MaxAttempts         int
+	RetryBackoffSeconds int
+	PollSeconds         int
+	DigestPollSeconds   int
+}
+
+// CertificateConfig holds the QR placement of certificates issued automatically
+// when a participant passes the event evaluation
+type CertificateConfig struct {
+	QRBaseURL   string
+	QRSizeCM    float64
+	QRMarginYCM float64
+	QRPage      int
+}
+
+// EvaluationReportConfig holds the PDF rendering of evaluation reports. The QR
+// placement is shared with certificates (CERTIFICATE_QR_*).
+type EvaluationReportConfig struct {
+	TemplateFileID string
+	QRBaseURL      string
+	AutoPDF        bool
+}
+
+type RevocationListConfig struct {
+	SigningKeyFile string
+	Issuer         string
+	RefreshMinutes int
 }
 
 func Load() (*Config, error) {
@@ -85,6 +163,62 @@ func Load() (*Config, error) {
 	viper.SetDefault("KEYCLOAK_SSO_URL", "")
 	viper.SetDefault("KEYCLOAK_REALM", "")
 
+	// Authorization (empty = embedded permission matrix, realm roles only)
+	viper.SetDefault("KEYCLOAK_CLIENT_ID", "")
+	viper.SetDefault("PERMISSIONS_FILE", "")
+
+	// Token validation (empty = derived from SSO URL / live certs endpoint, no aud/azp check)
+	viper.SetDefault("KEYCLOAK_ISSUER", "")
+	viper.SetDefault("KEYCLOAK_JWKS_FILE", "")
+	viper.SetDefault("KEYCLOAK_AUDIENCE", "")
+	viper.SetDefault("KEYCLOAK_AUTHORIZED_PARTIES", "")
+	viper.SetDefault("KEYCLOAK_CLOCK_SKEW_SECONDS", 30)
+
+	// User provisioning (national_id optional and user-editable unless the realm says otherwise)
+	viper.SetDefault("KEYCLOAK_REQUIRE_NATIONAL_ID", false)
+	viper.SetDefault("KEYCLOAK_NATIONAL_ID_ADMIN_MANAGED", false)
+
+	// file-svc defaults
+	viper.SetDefault("FILE_SVC_URL", "http://localhost:8080")
+	viper.SetDefault("FILE_SVC_TIMEOUT_SECONDS", 30)
+	viper.SetDefault("FILE_SVC_MAX_RETRIES", 2)
+	viper.SetDefault("FILE_SVC_RETRY_BACKOFF_MS", 200)
+
+	// Certificate archive defaults
+	viper.SetDefault("ARCHIVE_DIR", filepath.Join(os.TempDir(), "cert-archives"))
+	viper.SetDefault("ARCHIVE_TTL_HOURS", 24)
+	viper.SetDefault("ARCHIVE_SYNC_LIMIT", 200)
+
+	// SMTP defaults (empty host disables email delivery)
+	viper.SetDefault("SMTP_HOST", "")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("SMTP_FROM", "Certificados <no-reply@localhost>")
+	viper.SetDefault("SMTP_TLS", "starttls")
+	viper.SetDefault("SMTP_TIMEOUT_SECONDS", 15)
+
+	// Notification defaults
+	viper.SetDefault("NOTIFICATION_DEFAULT_LOCALE", "es")
+	viper.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 5)
+	viper.SetDefault("NOTIFICATION_RETRY_BACKOFF_SECONDS", 60)
+	viper.SetDefault("NOTIFICATION_POLL_SECONDS", 30)
+	viper.SetDefault("NOTIFICATION_DIGEST_POLL_SECONDS", 300)
+
+	// Certificate auto-issue defaults (no base URL = certificates are only registered)
+	viper.SetDefault("CERTIFICATE_QR_BASE_URL", "")
+	viper.SetDefault("CERTIFICATE_QR_SIZE_CM", 2.5)
+	viper.SetDefault("CERTIFICATE_QR_MARGIN_Y_CM", 1.0)
+	viper.SetDefault("CERTIFICATE_QR_PAGE", 0)
+
+	// Evaluation report defaults (no template = reports are only kept as Markdown)
+	viper.SetDefault("EVALUATION_REPORT_TEMPLATE_FILE_ID", "")
+	viper.SetDefault("EVALUATION_REPORT_QR_BASE_URL", "")
+	viper.SetDefault("EVALUATION_REPORT_AUTO_PDF", false)
+
+	// Revocation list defaults
+	viper.SetDefault("REVOCATION_SIGNING_KEY_FILE", "")
+	viper.SetDefault("REVOCATION_LIST_ISSUER", "cert-server")
+	viper.SetDefault("REVOCATION_LIST_REFRESH_MINUTES", 60)
+
 	_ = viper.ReadInConfig()
 
 	return &Config{
@@ -114,13 +248,84 @@ func Load() (*Config, error) {
 			Name: viper.GetString("NATS_NAME"),
 		},
 		Keycloak: KeycloakConfig{
-			SSOURL: viper.GetString("KEYCLOAK_SSO_URL"),
-			Realm:  viper.GetString("KEYCLOAK_REALM"),
+			SSOURL:          viper.GetString("KEYCLOAK_SSO_URL"),
+			Realm:           viper.GetString("KEYCLOAK_REALM"),
+			ClientID:        viper.GetString("KEYCLOAK_CLIENT_ID"),
+			PermissionsFile: viper.GetString("PERMISSIONS_FILE"),
+
+			Issuer:            viper.GetString("KEYCLOAK_ISSUER"),
+			JWKSFile:          viper.GetString("KEYCLOAK_JWKS_FILE"),
+			Audiences:         splitList(viper.GetString("KEYCLOAK_AUDIENCE")),
+			AuthorizedParties: splitList(viper.GetString("KEYCLOAK_AUTHORIZED_PARTIES")),
+			ClockSkewSeconds:  viper.GetInt("KEYCLOAK_CLOCK_SKEW_SECONDS"),
+
+			RequireNationalID:      viper.GetBool("KEYCLOAK_REQUIRE_NATIONAL_ID"),
+			NationalIDAdminManaged: viper.GetBool("KEYCLOAK_NATIONAL_ID_ADMIN_MANAGED"),
+		},
+		FileSvc: FileSvcConfig{
+			URL:            viper.GetString("FILE_SVC_URL"),
+			TimeoutSeconds: viper.GetInt("FILE_SVC_TIMEOUT_SECONDS"),
+			MaxRetries:     viper.GetInt("FILE_SVC_MAX_RETRIES"),
+			RetryBackoffMS: viper.GetInt("FILE_SVC_RETRY_BACKOFF_MS"),
+		},
+		Archive: ArchiveConfig{
+			Dir:       viper.GetString("ARCHIVE_DIR"),
+			TTLHours:  viper.GetInt("ARCHIVE_TTL_HOURS"),
+			SyncLimit: viper.GetInt("ARCHIVE_SYNC_LIMIT"),
+		},
+		SMTP: SMTPConfig{
+			Host:           viper.GetString("SMTP_HOST"),
+			Port:           viper.GetInt("SMTP_PORT"),
+			Username:       viper.GetString("SMTP_USERNAME"),
+			Password:       viper.GetString("SMTP_PASSWORD"),
+			From:           viper.GetString("SMTP_FROM"),
+			TLSMode:        viper.GetString("SMTP_TLS"),
+			TimeoutSeconds: viper.GetInt("SMTP_TIMEOUT_SECONDS"),
+		},
+		Notify: NotificationConfig{
+			TemplatesFile:       viper.GetString("NOTIFICATION_TEMPLATES_FILE"),
+			DefaultLocale:       viper.GetString("NOTIFICATION_DEFAULT_LOCALE"),
+			VerifyBaseURL:       viper.GetString("NOTIFICATION_VERIFY_BASE_URL"),
+			MaxAttempts:         viper.GetInt("NOTIFICATION_MAX_ATTEMPTS"),
+			RetryBackoffSeconds: viper.GetInt("NOTIFICATION_RETRY_BACKOFF_SECONDS"),
+			PollSeconds:         viper.GetInt("NOTIFICATION_POLL_SECONDS"),
+			DigestPollSeconds:   viper.GetInt("NOTIFICATION_DIGEST_POLL_SECONDS"),
+		},
+		Cert: CertificateConfig{
+			QRBaseURL:   viper.GetString("CERTIFICATE_QR_BASE_URL"),
+			QRSizeCM:    viper.GetFloat64("CERTIFICATE_QR_SIZE_CM"),
+			QRMarginYCM: viper.GetFloat64("CERTIFICATE_QR_MARGIN_Y_CM"),
+			QRPage:      viper.GetInt("CERTIFICATE_QR_PAGE"),
+		},
+		Report: EvaluationReportConfig{
+			TemplateFileID: viper.GetString("EVALUATION_REPORT_TEMPLATE_FILE_ID"),
+			QRBaseURL:      viper.GetString("EVALUATION_REPORT_QR_BASE_URL"),
+			AutoPDF:        viper.GetBool("EVALUATION_REPORT_AUTO_PDF"),
+		},
+		RevList: RevocationListConfig{
+			SigningKeyFile: viper.GetString("REVOCATION_SIGNING_KEY_FILE"),
+			Issuer:         viper.GetString("REVOCATION_LIST_ISSUER"),
+			RefreshMinutes: viper.GetInt("REVOCATION_LIST_REFRESH_MINUTES"),
 		},
 	}, nil
 }
 
 // IsKeycloakConfigured verifica si Keycloak está configurado
+// (SSO URL y realm, o emisor y JWKS estático para trabajar sin conexión)
 func (c *Config) IsKeycloakConfigured() bool {
+	if c.Keycloak.Issuer != "" && c.Keycloak.JWKSFile != "" {
+		return true
+	}
 	return c.Keycloak.SSOURL != "" && c.Keycloak.Realm != ""
+}
+
+// splitList separa una lista de valores por comas
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
\ No newline at end of file
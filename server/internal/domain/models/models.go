@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // CORE: USERS & USER DETAILS
@@ -11,10 +12,18 @@ import (
 type User struct {
 	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	Email      string    `gorm:"size:150;not null;uniqueIndex"`
-	NationalID string    `gorm:"size:20;not null;uniqueIndex" json:"national_id"` // DNI from SSO
+	NationalID string    `gorm:"size:20;not null;default:'';uniqueIndex:idx_users_national_id_set,where:national_id <> ''" json:"national_id"` // DNI from SSO, empty for staff without one
 	CreatedAt  time.Time `gorm:"not null"`
 	UpdatedAt  time.Time `gorm:"not null"`
 
+	// Provisioned just-in-time from the Keycloak token
+	KeycloakSub *string    `gorm:"size:64;uniqueIndex" json:"keycloak_sub"`
+	FirstName   string     `gorm:"size:100;not null;default:''" json:"first_name"`
+	LastName    string     `gorm:"size:100;not null;default:''" json:"last_name"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	// Roles of the token at the last sync (space separated); they bound the account's API keys
+	Roles string `gorm:"type:text;not null;default:''" json:"roles"`
+
 	Notifications     []Notification     `gorm:"foreignKey:UserID"`
 	DocumentTemplates []DocumentTemplate `gorm:"foreignKey:CreatedBy"`
 	Events            []Event            `gorm:"foreignKey:CreatedBy"`
@@ -31,7 +40,7 @@ func (User) TableName() string { return "users" }
 // UserDetail = certificate beneficiary (may or may not have an account)
 type UserDetail struct {
 	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	NationalID string    `gorm:"size:20;not null;uniqueIndex" json:"national_id"`
+	NationalID string    `gorm:"size:20;not null;uniqueIndex:idx_user_details_national_id_active,where:deleted_at IS NULL" json:"national_id"`
 	FirstName  string    `gorm:"size:100;not null" json:"first_name"`
 	LastName   string    `gorm:"size:100;not null" json:"last_name"`
 	Phone      *string   `gorm:"size:30"`
@@ -39,12 +48,79 @@ type UserDetail struct {
 	CreatedAt  time.Time `gorm:"not null"`
 	UpdatedAt  time.Time `gorm:"not null"`
 
+	// soft delete: restaurable desde la papelera
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
 	Documents         []Document         `gorm:"foreignKey:UserDetailID"`
 	EventParticipants []EventParticipant `gorm:"foreignKey:UserDetailID"`
 }
 
 func (UserDetail) TableName() string { return "user_details" }
 
+// API key for integrations (pdf-svc callbacks, HR imports, reporting jobs).
+// Only the SHA-256 of the key is stored; Prefix identifies it in lookups.
+type APIKey struct {
+	ID      uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name    string    `gorm:"size:150;not null" json:"name"`
+	Prefix  string    `gorm:"size:16;not null;uniqueIndex" json:"prefix"`
+	KeyHash string    `gorm:"size:64;not null" json:"-"`
+
+	// Permissions granted, space separated ("*" = every permission)
+	Scopes      string `gorm:"type:text;not null;default:''" json:"scopes"`
+	OrgUnitPath string `gorm:"size:255;not null;default:''" json:"org_unit_path"`
+
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	// Work done with the key is attributed to its creator
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null;index" json:"created_by"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	User User `gorm:"foreignKey:CreatedBy"`
+}
+
+func (APIKey) TableName() string { return "api_keys" }
+
+// AUDIT
+
+// Audit trail of mutating API calls. Each entry stores the hash of the previous
+// one (PrevHash) so removed or edited entries break the chain.
+type AuditLog struct {
+	ID  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Seq int64     `gorm:"not null;uniqueIndex" json:"seq"`
+
+	ActorID       *uuid.UUID `gorm:"type:uuid;index" json:"actor_id"`
+	ActorUsername string     `gorm:"size:150;not null;default:''" json:"actor_username"`
+	// user | service | api_key
+	ActorType string `gorm:"size:20;not null;default:''" json:"actor_type"`
+
+	Method     string `gorm:"size:10;not null" json:"method"`
+	Route      string `gorm:"size:255;not null" json:"route"`
+	Path       string `gorm:"size:500;not null" json:"path"`
+	EntityType string `gorm:"size:50;not null;default:'';index" json:"entity_type"`
+	EntityID   string `gorm:"size:64;not null;default:'';index" json:"entity_id"`
+	// CREATE | UPDATE | DELETE | ENABLE | DISABLE | <document action> ...
+	Action string `gorm:"size:50;not null" json:"action"`
+
+	Before *string `gorm:"type:text" json:"before"`
+	After  *string `gorm:"type:text" json:"after"`
+	Diff   *string `gorm:"type:text" json:"diff"`
+
+	RequestID  string `gorm:"size:64;not null;default:'';index" json:"request_id"`
+	IPAddress  string `gorm:"size:64;not null;default:''" json:"ip_address"`
+	StatusCode int    `gorm:"not null" json:"status_code"`
+	// SUCCESS | FAILURE | DENIED
+	Outcome string `gorm:"size:20;not null" json:"outcome"`
+
+	PrevHash  string    `gorm:"size:64;not null;default:''" json:"prev_hash"`
+	Hash      string    `gorm:"size:64;not null" json:"hash"`
+	CreatedAt time.Time `gorm:"not null;index"`
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
 // NOTIFICATIONS
 
 type Notification struct {
@@ -62,6 +138,63 @@ type Notification struct {
 
 func (Notification) TableName() string { return "notifications" }
 
+// Delivery of a notification through one channel (IN_APP, EMAIL). Pending email
+// deliveries form the retry queue: the dispatcher picks them once NextAttemptAt passes.
+type NotificationDelivery struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	NotificationID *uuid.UUID `gorm:"type:uuid;index" json:"notification_id"`
+	UserDetailID   *uuid.UUID `gorm:"type:uuid;index" json:"user_detail_id"`
+	UserID         *uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+
+	// IN_APP | EMAIL
+	Channel     string `gorm:"size:20;not null" json:"channel"`
+	Recipient   string `gorm:"size:150;not null;default:''" json:"recipient"`
+	TemplateKey string `gorm:"size:100;not null;index" json:"template_key"`
+	Locale      string `gorm:"size:10;not null" json:"locale"`
+	Subject     string `gorm:"size:255;not null" json:"subject"`
+	Body        string `gorm:"type:text;not null" json:"body"`
+
+	// PENDING | SENT | FAILED | SKIPPED | DIGEST_PENDING | DIGESTED
+	Status        string     `gorm:"size:20;not null;default:'PENDING';index:idx_notification_deliveries_queue,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts   int        `gorm:"not null;default:5" json:"max_attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_notification_deliveries_queue,priority:2" json:"next_attempt_at"`
+	LastError     *string    `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+
+	// HOURLY | DAILY while waiting for a digest; DigestID is the summary delivery it was folded into
+	DigestMode *string    `gorm:"size:20" json:"digest_mode"`
+	DigestID   *uuid.UUID `gorm:"type:uuid;index" json:"digest_id"`
+
+	// domain event id + channel + recipient: redelivered events are not sent twice
+	DedupKey string `gorm:"size:255;not null;uniqueIndex" json:"dedup_key"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	Notification *Notification `gorm:"foreignKey:NotificationID"`
+}
+
+func (NotificationDelivery) TableName() string { return "notification_deliveries" }
+
+// Per-user delivery mode of a notification type on a channel. Types and channels
+// without a row are delivered immediately.
+type NotificationPreference struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_user_type_channel,priority:1" json:"user_id"`
+	NotificationType string    `gorm:"size:50;not null;uniqueIndex:idx_notification_preferences_user_type_channel,priority:2" json:"notification_type"`
+	// IN_APP | EMAIL
+	Channel string `gorm:"size:20;not null;uniqueIndex:idx_notification_preferences_user_type_channel,priority:3" json:"channel"`
+	// IMMEDIATE | HOURLY | DAILY | NONE
+	Mode      string    `gorm:"size:20;not null;default:'IMMEDIATE'" json:"mode"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
+
+func (NotificationPreference) TableName() string { return "notification_preferences" }
+
 // DOCUMENT TYPES, CATEGORIES & TEMPLATES
 
 type DocumentType struct {
@@ -127,6 +260,12 @@ type DocumentTemplate struct {
 	CreatedAt      time.Time  `gorm:"not null"`
 	UpdatedAt      time.Time  `gorm:"not null"`
 
+	// '' = plantilla compartida por todas las unidades orgánicas
+	OrganizationalUnitsPath string `gorm:"size:255;not null;default:'';index" json:"organizational_units_path"`
+
+	// soft delete: restaurable desde la papelera
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
 	DocumentType DocumentType            `gorm:"foreignKey:DocumentTypeID"`
 	Fields       []DocumentTemplateField `gorm:"foreignKey:TemplateID"`
 	Category     *DocumentCategory       `gorm:"foreignKey:CategoryID"`
@@ -154,12 +293,28 @@ type Event struct {
 	RegistrationOpenAt  *time.Time `json:"registration_open_at"`
 	RegistrationCloseAt *time.Time `json:"registration_close_at"`
 
+	// Evaluación que los participantes deben aprobar para ser elegibles al certificado.
+	// Con AutoIssueCertificates, aprobarla registra y genera el certificado (reg_doc + gen_doc).
+	EvaluationID          *uuid.UUID `gorm:"type:uuid;index" json:"evaluation_id"`
+	AutoIssueCertificates bool       `gorm:"not null;default:false" json:"auto_issue_certificates"`
+
+	// Material de estudio que los participantes deben completar antes de iniciar la
+	// evaluación y para ser elegibles al certificado: al menos StudyMinPercentage % de
+	// sus subsecciones marcadas como completadas (100 = todas).
+	StudyMaterialID    *uuid.UUID `gorm:"type:uuid;index" json:"study_material_id"`
+	StudyMinPercentage float64    `gorm:"type:numeric(5,2);not null;default:100" json:"study_min_percentage"`
+
 	Status    string    `gorm:"size:50;not null;default:'SCHEDULED'"`
 	CreatedBy uuid.UUID `gorm:"type:uuid;not null;index" json:"created_by"` // User (organizer/admin)
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
 
+	// soft delete: restaurable desde la papelera
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
 	Template          *DocumentTemplate  `gorm:"foreignKey:TemplateID"`
+	Evaluation        *Evaluation        `gorm:"foreignKey:EvaluationID"`
+	StudyMaterial     *StudyMaterial     `gorm:"foreignKey:StudyMaterialID"`
 	User              User               `gorm:"foreignKey:CreatedBy"`
 	Schedules         []EventSchedule    `gorm:"foreignKey:EventID"`
 	EventParticipants []EventParticipant `gorm:"foreignKey:EventID"`
@@ -190,6 +345,18 @@ type EventParticipant struct {
 	CreatedAt          time.Time `gorm:"not null"`
 	UpdatedAt          time.Time `gorm:"not null"`
 
+	// Elegibilidad al certificado cuando el evento exige una evaluación (Event.EvaluationID):
+	// se marca al aprobarla, con la nota (porcentaje) y el intento aprobado
+	CertificateEligible bool       `gorm:"not null;default:false" json:"certificate_eligible"`
+	EligibleAt          *time.Time `json:"eligible_at"`
+	FinalScore          *float64   `gorm:"type:numeric(5,2)" json:"final_score"`
+	EvaluationAttemptID *uuid.UUID `gorm:"type:uuid" json:"evaluation_attempt_id"`
+
+	// Documento vigente del participante: solo lectura, lo rellena el LEFT JOIN del listado
+	DocumentID         *uuid.UUID `gorm:"->;-:migration" json:"-"`
+	DocumentStatus     *string    `gorm:"->;-:migration" json:"-"`
+	DocumentSerialCode *string    `gorm:"->;-:migration" json:"-"`
+
 	Event      Event      `gorm:"foreignKey:EventID"`
 	UserDetail UserDetail `gorm:"foreignKey:UserDetailID"`
 }
@@ -210,6 +377,12 @@ type Document struct {
 	IssueDate time.Time  `gorm:"not null" json:"issue_date"`
 	SignedAt  *time.Time `json:"signed_at"`
 
+	// Reemisión (doc_reissue): cada versión es un documento con su propio serial y
+	// código de verificación. La vigente tiene ReplacedByID = nil.
+	Version            int        `gorm:"not null;default:1" json:"version"`
+	PreviousDocumentID *uuid.UUID `gorm:"type:uuid;index" json:"previous_document_id,omitempty"`
+	ReplacedByID       *uuid.UUID `gorm:"type:uuid;index" json:"replaced_by_id,omitempty"`
+
 	// Estado del ciclo del documento / PDF
 	// CREATED | PDF_QUEUED | PDF_GENERATING | PDF_GENERATED | PDF_FAILED
 	Status string `gorm:"size:50;not null;default:'CREATED'"`
@@ -232,14 +405,21 @@ type Document struct {
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
 
+	// soft delete: restaurable desde la papelera
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
 	// Relaciones
 	UserDetail    UserDetail        `gorm:"foreignKey:UserDetailID"`
 	Event         *Event            `gorm:"foreignKey:EventID"`
 	Template      *DocumentTemplate `gorm:"foreignKey:TemplateID"`
 	CreatedByUser User              `gorm:"foreignKey:CreatedBy"`
 
-	PDFs        []DocumentPDF `gorm:"foreignKey:DocumentID"`
-	Evaluations []Evaluation  `gorm:"foreignKey:DocumentID"`
+	PreviousDocument *Document `gorm:"foreignKey:PreviousDocumentID"`
+	ReplacedBy       *Document `gorm:"foreignKey:ReplacedByID"`
+
+	PDFs        []DocumentPDF        `gorm:"foreignKey:DocumentID"`
+	Evaluations []Evaluation         `gorm:"foreignKey:DocumentID"`
+	Revocations []DocumentRevocation `gorm:"foreignKey:DocumentID"`
 }
 
 func (Document) TableName() string { return "documents" }
@@ -261,6 +441,77 @@ type DocumentPDF struct {
 
 func (DocumentPDF) TableName() string { return "document_pdfs" }
 
+// Revocation of a certificate (doc_reject, or doc_reissue for the superseded version).
+// The one in force has LiftedAt = nil; doc_renew lifts it so the document leaves the
+// revocation list.
+type DocumentRevocation struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;index" json:"document_id"`
+
+	// ISSUED_IN_ERROR | DATA_CORRECTION | SUPERSEDED | FRAUD | WITHDRAWN | UNSPECIFIED
+	ReasonCode     string     `gorm:"size:50;not null" json:"reason_code"`
+	Reason         *string    `gorm:"type:text" json:"reason"`
+	SupersededByID *uuid.UUID `gorm:"type:uuid;index" json:"superseded_by_id"`
+
+	RevokedBy uuid.UUID  `gorm:"type:uuid;not null;index" json:"revoked_by"`
+	RevokedAt time.Time  `gorm:"not null;index" json:"revoked_at"`
+	LiftedBy  *uuid.UUID `gorm:"type:uuid" json:"lifted_by"`
+	LiftedAt  *time.Time `json:"lifted_at"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index"`
+
+	Document      Document  `gorm:"foreignKey:DocumentID"`
+	SupersededBy  *Document `gorm:"foreignKey:SupersededByID"`
+	RevokedByUser User      `gorm:"foreignKey:RevokedBy"`
+}
+
+func (DocumentRevocation) TableName() string { return "document_revocations" }
+
+// Bulk ZIP download of generated certificates (by event or pdf job)
+type DocumentArchiveJob struct {
+	ID       uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EventID  *uuid.UUID `gorm:"type:uuid;index" json:"event_id"`
+	PdfJobID *uuid.UUID `gorm:"type:uuid;index" json:"pdf_job_id"`
+
+	// PENDING | PROCESSING | COMPLETED | FAILED
+	Status         string     `gorm:"size:50;not null;default:'PENDING'"`
+	TotalItems     int        `gorm:"not null;default:0" json:"total_items"`
+	ProcessedItems int        `gorm:"not null;default:0" json:"processed_items"`
+	FailedItems    int        `gorm:"not null;default:0" json:"failed_items"`
+	FileName       string     `gorm:"size:255;not null;default:''" json:"file_name"`
+	FilePath       string     `gorm:"size:500;not null;default:''" json:"-"`
+	FileSizeBytes  *int64     `json:"file_size_bytes"`
+	ErrorMessage   *string    `gorm:"type:text" json:"error_message"`
+	RequestedBy    uuid.UUID  `gorm:"type:uuid;not null;index" json:"requested_by"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	CreatedAt      time.Time  `gorm:"not null"`
+	UpdatedAt      time.Time  `gorm:"not null"`
+}
+
+func (DocumentArchiveJob) TableName() string { return "document_archive_jobs" }
+
+// Audit trail of certificate PDF downloads (granted and denied)
+type DocumentDownloadLog struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	DocumentID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"document_id"`
+	DocumentPDFID *uuid.UUID `gorm:"type:uuid;index" json:"document_pdf_id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+
+	// ADMIN | EVENT_OWNER | BENEFICIARY (empty when denied)
+	AccessReason string `gorm:"size:50;not null;default:''" json:"access_reason"`
+	// GRANTED | DENIED | FAILED
+	Outcome     string    `gorm:"size:50;not null" json:"outcome"`
+	Version     *int      `json:"version"`
+	RangeHeader *string   `gorm:"size:100" json:"range_header"`
+	IPAddress   string    `gorm:"size:64;not null;default:''" json:"ip_address"`
+	UserAgent   string    `gorm:"size:500;not null;default:''" json:"user_agent"`
+	CreatedAt   time.Time `gorm:"not null;index"`
+}
+
+func (DocumentDownloadLog) TableName() string { return "document_download_logs" }
+
 // EVALUATIONS
 
 type Evaluation struct {
@@ -268,11 +519,20 @@ type Evaluation struct {
 	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
 	DocumentID *uuid.UUID `gorm:"type:uuid;index" json:"document_id"`
 
-	Title       string    `gorm:"type:text;not null"`
-	Description *string   `gorm:"type:text"`
-	Status      string    `gorm:"size:20;not null;default:'pending'"`
-	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt   time.Time
+	Title       string  `gorm:"type:text;not null"`
+	Description *string `gorm:"type:text"`
+	// pending (draft) | active (open for attempts) | closed
+	Status string `gorm:"size:20;not null;default:'pending'"`
+
+	// minimum percentage (0-100) of the total score to pass
+	PassingScore float64 `gorm:"type:numeric(5,2);not null;default:60" json:"passing_score"`
+	// nil = no time limit
+	TimeLimitMinutes *int `json:"time_limit_minutes"`
+	// attempts allowed per user, 0 = unlimited
+	MaxAttempts int `gorm:"not null;default:1" json:"max_attempts"`
+
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time
 
 	User      User                 `gorm:"foreignKey:UserID"`
 	Document  *Document            `gorm:"foreignKey:DocumentID"`
@@ -280,6 +540,9 @@ type Evaluation struct {
 	Answers   []EvaluationAnswer   `gorm:"foreignKey:EvaluationID"`
 	Scores    []EvaluationScore    `gorm:"foreignKey:EvaluationID"`
 	Docs      []EvaluationDoc      `gorm:"foreignKey:EvaluationID"`
+	// set when the questions are drawn from the bank for each attempt instead of fixed
+	DrawRules []EvaluationDrawRule `gorm:"foreignKey:EvaluationID;constraint:OnDelete:CASCADE"`
+	Attempts  []EvaluationAttempt  `gorm:"foreignKey:EvaluationID"`
 }
 
 func (Evaluation) TableName() string { return "evaluations" }
@@ -287,24 +550,80 @@ func (Evaluation) TableName() string { return "evaluations" }
 type EvaluationQuestion struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	EvaluationID uuid.UUID `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+	// set on the questions drawn for one attempt: copies of bank questions numbered in
+	// shuffled order. The evaluation's fixed questions have no attempt.
+	AttemptID      *uuid.UUID `gorm:"type:uuid;index" json:"attempt_id"`
+	BankQuestionID *uuid.UUID `gorm:"type:uuid;index" json:"bank_question_id"`
+
+	QuestionNumber int    `gorm:"not null" json:"question_number"`
+	QuestionText   string `gorm:"type:text;not null" json:"question_text"`
+	// MULTIPLE_CHOICE | TRUE_FALSE | SHORT_ANSWER (auto-graded) | ESSAY (manual review)
+	QuestionType string    `gorm:"size:30;not null;default:'ESSAY'" json:"question_type"`
+	MaxScore     float64   `gorm:"type:numeric(5,2);default:1" json:"max_score"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Evaluation Evaluation                 `gorm:"foreignKey:EvaluationID"`
+	Options    []EvaluationQuestionOption `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	Answers    []EvaluationAnswer         `gorm:"foreignKey:QuestionID"`
+	Scores     []EvaluationScore          `gorm:"foreignKey:QuestionID"`
+}
+
+func (EvaluationQuestion) TableName() string { return "evaluation_questions" }
 
-	QuestionNumber int       `gorm:"not null" json:"question_number"`
-	QuestionText   string    `gorm:"type:text;not null" json:"question_text"`
-	MaxScore       float64   `gorm:"type:numeric(5,2);default:1" json:"max_score"`
-	CreatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+// Choice of a MULTIPLE_CHOICE / TRUE_FALSE question, or an accepted answer of a
+// SHORT_ANSWER question (all marked correct, compared case-insensitively)
+type EvaluationQuestionOption struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	QuestionID uuid.UUID `gorm:"type:uuid;not null;index" json:"question_id"`
+
+	OptionKey  string `gorm:"size:20;not null" json:"option_key"`
+	OptionText string `gorm:"type:text;not null" json:"option_text"`
+	IsCorrect  bool   `gorm:"not null;default:false" json:"is_correct"`
+	OrderIndex int    `gorm:"not null;default:0" json:"order_index"`
+}
+
+func (EvaluationQuestionOption) TableName() string { return "evaluation_question_options" }
+
+// One user's try at an evaluation. Answers and scores of the try point to it.
+type EvaluationAttempt struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EvaluationID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_evaluation_attempts_number,priority:1" json:"evaluation_id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_evaluation_attempts_number,priority:2;index" json:"user_id"`
+	AttemptNumber int       `gorm:"not null;uniqueIndex:idx_evaluation_attempts_number,priority:3" json:"attempt_number"`
+
+	// IN_PROGRESS | UNDER_REVIEW | GRADED | EXPIRED
+	Status      string     `gorm:"size:20;not null;default:'IN_PROGRESS';index" json:"status"`
+	StartedAt   time.Time  `gorm:"not null" json:"started_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	GradedAt    *time.Time `json:"graded_at"`
+
+	Score      float64 `gorm:"type:numeric(7,2);not null;default:0" json:"score"`
+	MaxScore   float64 `gorm:"type:numeric(7,2);not null;default:0" json:"max_score"`
+	Percentage float64 `gorm:"type:numeric(5,2);not null;default:0" json:"percentage"`
+	// nil until graded
+	Passed *bool `json:"passed"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
 
 	Evaluation Evaluation         `gorm:"foreignKey:EvaluationID"`
-	Answers    []EvaluationAnswer `gorm:"foreignKey:QuestionID"`
-	Scores     []EvaluationScore  `gorm:"foreignKey:QuestionID"`
+	User       User               `gorm:"foreignKey:UserID"`
+	Answers    []EvaluationAnswer `gorm:"foreignKey:AttemptID"`
+	Scores     []EvaluationScore  `gorm:"foreignKey:AttemptID"`
+	// questions drawn from the bank for this attempt (empty for fixed evaluations)
+	Questions []EvaluationQuestion `gorm:"foreignKey:AttemptID"`
 }
 
-func (EvaluationQuestion) TableName() string { return "evaluation_questions" }
+func (EvaluationAttempt) TableName() string { return "evaluation_attempts" }
 
 type EvaluationAnswer struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	EvaluationID uuid.UUID `gorm:"type:uuid;not null;index" json:"evaluation_id"`
-	QuestionID   uuid.UUID `gorm:"type:uuid;not null;index" json:"question_id"`
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EvaluationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+	QuestionID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"question_id"`
+	AttemptID    *uuid.UUID `gorm:"type:uuid;index" json:"attempt_id"`
 
+	// selected option keys (comma separated) for choice questions, free text otherwise
 	ResponseText string    `gorm:"type:text" json:"response_text"`
 	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
@@ -315,17 +634,21 @@ type EvaluationAnswer struct {
 func (EvaluationAnswer) TableName() string { return "evaluation_answers" }
 
 type EvaluationScore struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	EvaluationID uuid.UUID `gorm:"type:uuid;not null;index" json:"evaluation_id"`
-	QuestionID   uuid.UUID `gorm:"type:uuid;not null;index" json:"question_id"`
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EvaluationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+	QuestionID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"question_id"`
+	AttemptID    *uuid.UUID `gorm:"type:uuid;index" json:"attempt_id"`
 
-	AdminVerdict string    `gorm:"size:20" json:"admin_verdict"`
-	Score        float64   `gorm:"type:numeric(5,2)"`
-	Remarks      *string   `gorm:"type:text"`
-	ReviewedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"reviewed_at"`
+	// CORRECT | INCORRECT (auto-graded), PENDING (waiting for review), APPROVED | PARTIAL | REJECTED (reviewed)
+	AdminVerdict string     `gorm:"size:20;index" json:"admin_verdict"`
+	Score        float64    `gorm:"type:numeric(5,2)"`
+	Remarks      *string    `gorm:"type:text"`
+	ReviewedBy   *uuid.UUID `gorm:"type:uuid" json:"reviewed_by"`
+	ReviewedAt   time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"reviewed_at"`
 
 	Evaluation Evaluation         `gorm:"foreignKey:EvaluationID"`
 	Question   EvaluationQuestion `gorm:"foreignKey:QuestionID"`
+	Attempt    *EvaluationAttempt `gorm:"foreignKey:AttemptID"`
 }
 
 func (EvaluationScore) TableName() string { return "evaluation_scores" }
@@ -333,15 +656,77 @@ func (EvaluationScore) TableName() string { return "evaluation_scores" }
 type EvaluationDoc struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	EvaluationID uuid.UUID `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+	// graded attempt the report covers (one report per attempt)
+	AttemptID *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"attempt_id"`
 
 	MarkdownContent string    `gorm:"type:text" json:"markdown_content"`
 	GeneratedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"generated_at"`
 
-	Evaluation Evaluation `gorm:"foreignKey:EvaluationID"`
+	// PDF rendered by pdf-svc: nil until requested | PENDING | COMPLETED | FAILED
+	PdfStatus   *string    `gorm:"size:20" json:"pdf_status"`
+	PdfJobID    *uuid.UUID `gorm:"type:uuid;index" json:"pdf_job_id"`
+	PdfFileID   *uuid.UUID `gorm:"type:uuid" json:"pdf_file_id"`
+	PdfFileHash *string    `gorm:"size:64" json:"pdf_file_hash"`
+
+	Evaluation Evaluation         `gorm:"foreignKey:EvaluationID"`
+	Attempt    *EvaluationAttempt `gorm:"foreignKey:AttemptID"`
 }
 
 func (EvaluationDoc) TableName() string { return "evaluation_docs" }
 
+// Rule of an evaluation template: every attempt draws Count random active bank
+// questions of Topic (and Difficulty, when set)
+type EvaluationDrawRule struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	EvaluationID uuid.UUID `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+
+	Topic string `gorm:"size:100;not null" json:"topic"`
+	// nil = any difficulty
+	Difficulty *string `gorm:"size:10" json:"difficulty"`
+	Count      int     `gorm:"not null" json:"count"`
+	OrderIndex int     `gorm:"not null;default:0" json:"order_index"`
+}
+
+func (EvaluationDrawRule) TableName() string { return "evaluation_draw_rules" }
+
+// QUESTION BANK
+
+// Reusable question tagged by topic and difficulty. Attempts get copies of the drawn
+// questions, so editing or retiring a bank question never changes past results.
+type BankQuestion struct {
+	ID uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+
+	Topic string `gorm:"size:100;not null;index" json:"topic"`
+	// EASY | MEDIUM | HARD
+	Difficulty   string  `gorm:"size:10;not null;default:'MEDIUM';index" json:"difficulty"`
+	QuestionText string  `gorm:"type:text;not null" json:"question_text"`
+	QuestionType string  `gorm:"size:30;not null" json:"question_type"`
+	MaxScore     float64 `gorm:"type:numeric(5,2);not null;default:1" json:"max_score"`
+	// inactive questions are no longer drawn
+	IsActive bool `gorm:"not null;default:true;index" json:"is_active"`
+
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null;index" json:"created_by"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+
+	Options []BankQuestionOption `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+}
+
+func (BankQuestion) TableName() string { return "bank_questions" }
+
+// Same meaning as EvaluationQuestionOption
+type BankQuestionOption struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	QuestionID uuid.UUID `gorm:"type:uuid;not null;index" json:"question_id"`
+
+	OptionKey  string `gorm:"size:20;not null" json:"option_key"`
+	OptionText string `gorm:"type:text;not null" json:"option_text"`
+	IsCorrect  bool   `gorm:"not null;default:false" json:"is_correct"`
+	OrderIndex int    `gorm:"not null;default:0" json:"order_index"`
+}
+
+func (BankQuestionOption) TableName() string { return "bank_question_options" }
+
 // STUDY MATERIALS / REINFORCEMENT
 
 type StudyMaterial struct {
@@ -418,10 +803,11 @@ type StudyAnnotation struct {
 
 func (StudyAnnotation) TableName() string { return "study_annotations" }
 
+// One row per user and subsection, upserted when the subsection is marked (in)complete
 type StudyProgress struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	SubsectionID uuid.UUID `gorm:"type:uuid;not null;index" json:"subsection_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_study_progress_user_subsection,priority:1" json:"user_id"`
+	SubsectionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_study_progress_user_subsection,priority:2;index" json:"subsection_id"`
 	Completed    bool      `gorm:"not null;default:false"`
 	CompletedAt  *time.Time `json:"completed_at"`
 
@@ -0,0 +1,88 @@
+// Package orgunit carries the organizational-unit scope of a request so that
+// repositories can restrict rows to the caller's subtree.
+package orgunit
+
+import (
+	"context"
+	"strings"
+)
+
+type scopeKey struct{}
+
+// Scope lists the organizational-unit subtrees a caller may access
+type Scope struct {
+	// Bypass disables scoping (admins and internal jobs)
+	Bypass bool
+	// Paths are normalized unit paths, e.g. "/gra/ggr"; each grants its whole subtree
+	Paths []string
+}
+
+// Unrestricted is the scope of callers that see every unit
+var Unrestricted = Scope{Bypass: true}
+
+// NewScope builds a scope from raw unit paths
+func NewScope(paths ...string) Scope {
+	s := Scope{Paths: make([]string, 0, len(paths))}
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		p = Normalize(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		s.Paths = append(s.Paths, p)
+	}
+	return s
+}
+
+// WithScope stores the scope in the context
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// FromContext returns the scope of the context. Contexts without a scope
+// (workers, background jobs) are not restricted.
+func FromContext(ctx context.Context) Scope {
+	if s, ok := ctx.Value(scopeKey{}).(Scope); ok {
+		return s
+	}
+	return Unrestricted
+}
+
+// Allows reports whether path lies inside one of the scope subtrees
+func (s Scope) Allows(path string) bool {
+	if s.Bypass {
+		return true
+	}
+	path = Normalize(path)
+	if path == "" {
+		return false
+	}
+	for _, p := range s.Paths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Default returns the unit assigned to new rows when none is given
+func (s Scope) Default() string {
+	if s.Bypass || len(s.Paths) == 0 {
+		return ""
+	}
+	return s.Paths[0]
+}
+
+// Normalize trims spaces and trailing slashes and ensures a leading slash
+func Normalize(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		return ""
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+package orgunit_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"server/internal/domain/orgunit"
+)
+
+func TestNewScopeNormalizesPaths(t *testing.T) {
+	s := orgunit.NewScope(" gra/ggr/ ", "/gra/ggr", "", "/", "/gra/gdis")
+	if want := []string{"/gra/ggr", "/gra/gdis"}; s.Bypass || !slices.Equal(s.Paths, want) {
+		t.Fatalf("scope = %+v, want paths %v", s, want)
+	}
+	if s.Default() != "/gra/ggr" {
+		t.Fatalf("Default = %q", s.Default())
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	scoped := orgunit.NewScope("/gra/ggr")
+
+	cases := []struct {
+		name  string
+		scope orgunit.Scope
+		path  string
+		want  bool
+	}{
+		{"same unit", scoped, "/gra/ggr", true},
+		{"child unit", scoped, "/gra/ggr/sgti", true},
+		{"child without leading slash", scoped, "gra/ggr/sgti/", true},
+		{"sibling sharing a prefix", scoped, "/gra/ggrh", false},
+		{"parent unit", scoped, "/gra", false},
+		{"row without unit", scoped, "", false},
+		{"unrestricted", orgunit.Unrestricted, "/otro", true},
+		{"unrestricted row without unit", orgunit.Unrestricted, "", true},
+		{"no unit and no groups", orgunit.NewScope(), "/gra", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.scope.Allows(tc.path); got != tc.want {
+				t.Fatalf("Allows(%q) = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if s := orgunit.FromContext(context.Background()); !s.Bypass {
+		t.Fatal("a context without scope must be unrestricted")
+	}
+	ctx := orgunit.WithScope(context.Background(), orgunit.NewScope())
+	if s := orgunit.FromContext(ctx); s.Bypass || len(s.Paths) != 0 {
+		t.Fatalf("scope = %+v, want an empty restricted scope", s)
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- request dtos
+
+// APIKeyCreateRequest represents the request to create an API key
+type APIKeyCreateRequest struct {
+	Name        string     `json:"name" validate:"required,min=1,max=150"`
+	Scopes      []string   `json:"scopes" validate:"required,min=1"`
+	OrgUnitPath *string    `json:"org_unit_path,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// -- response dtos
+
+// APIKeyResponse represents an API key without its secret
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	OrgUnitPath string     `json:"org_unit_path"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse includes the plain key, returned only once at creation
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal is the identity resolved from a valid API key
+type APIKeyPrincipal struct {
+	ID      uuid.UUID
+	Name    string
+	OwnerID uuid.UUID
+	// OwnerRoles are the roles the owner had at their last login; a scope is only
+	// honored while these roles still grant it
+	OwnerRoles  []string
+	Scopes      []string
+	OrgUnitPath string
+}
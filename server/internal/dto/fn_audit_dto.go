@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- audit outcome constants
+
+const (
+	AuditOutcomeSuccess = "SUCCESS"
+	AuditOutcomeFailure = "FAILURE"
+	AuditOutcomeDenied  = "DENIED"
+)
+
+// -- request dtos
+
+// AuditEntry represents a mutating API call captured by the audit middleware
+type AuditEntry struct {
+	ActorID       *uuid.UUID
+	ActorUsername string
+	ActorType     string
+	Method        string
+	Route         string
+	Path          string
+	EntityType    string
+	EntityID      string
+	Action        string
+	Before        map[string]interface{}
+	After         map[string]interface{}
+	RequestID     string
+	IPAddress     string
+	StatusCode    int
+}
+
+// AuditListQuery represents query parameters for listing audit entries
+type AuditListQuery struct {
+	Page       int        `query:"page"`
+	PageSize   int        `query:"page_size"`
+	ActorID    *uuid.UUID `query:"actor_id"`
+	EntityType *string    `query:"entity_type"`
+	EntityID   *string    `query:"entity_id"`
+	Action     *string    `query:"action"`
+	Outcome    *string    `query:"outcome"`
+	RequestID  *string    `query:"request_id"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+}
+
+// -- response dtos
+
+// AuditLogResponse represents a single audit entry
+type AuditLogResponse struct {
+	ID            uuid.UUID   `json:"id"`
+	Seq           int64       `json:"seq"`
+	ActorID       *uuid.UUID  `json:"actor_id,omitempty"`
+	ActorUsername string      `json:"actor_username"`
+	ActorType     string      `json:"actor_type"`
+	Method        string      `json:"method"`
+	Route         string      `json:"route"`
+	Path          string      `json:"path"`
+	EntityType    string      `json:"entity_type"`
+	EntityID      string      `json:"entity_id"`
+	Action        string      `json:"action"`
+	Before        interface{} `json:"before,omitempty"`
+	After         interface{} `json:"after,omitempty"`
+	Diff          interface{} `json:"diff,omitempty"`
+	RequestID     string      `json:"request_id"`
+	IPAddress     string      `json:"ip_address"`
+	StatusCode    int         `json:"status_code"`
+	Outcome       string      `json:"outcome"`
+	PrevHash      string      `json:"prev_hash"`
+	Hash          string      `json:"hash"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// AuditVerifyResponse represents the result of checking the audit hash chain
+type AuditVerifyResponse struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	LastSeq  int64  `json:"last_seq"`
+	LastHash string `json:"last_hash"`
+	// set when the chain is broken
+	BrokenAtSeq *int64 `json:"broken_at_seq,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
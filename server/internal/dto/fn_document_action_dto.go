@@ -44,19 +44,27 @@ var AllowedStatusTransitions = map[string][]string{
 	DocStatusPDFInsertingQR:  {DocStatusPDFQRInserted, DocStatusPDFFailed},
 	DocStatusPDFQRInserted:   {DocStatusPDFUploading, DocStatusPDFFailed},
 	DocStatusPDFUploading:    {DocStatusPDFCompleted, DocStatusPDFFailed},
-	DocStatusPDFCompleted:    {DocStatusRejected, DocStatusRenew},
+	DocStatusPDFCompleted:    {DocStatusRejected},
 	DocStatusPDFFailed:       {DocStatusRenew, DocStatusRejected},
 	DocStatusRejected:        {DocStatusRenew},
 }
 
+// TemplateFieldFinalScore is the certificate field filled with the participant's
+// evaluation score when the event requires an evaluation
+const TemplateFieldFinalScore = "nota_final"
+
 // -- request dtos
 
 // DocumentActionRequest represents a request for document actions
 type DocumentActionRequest struct {
-	Action       string                       `json:"action" validate:"required,oneof=reg_doc sync_doc gen_doc doc_reject doc_renew"`
+	Action       string                       `json:"action" validate:"required,oneof=reg_doc sync_doc gen_doc doc_reject doc_renew doc_reissue"`
 	EventID      string                       `json:"event_id" validate:"required,uuid"`
 	Participants []DocumentActionParticipant  `json:"participants" validate:"required,min=1"`
 	QRConfig     *QRConfigRequest             `json:"qr_config,omitempty"`
+
+	// doc_reject / doc_reissue: reason code (defaults to UNSPECIFIED / SUPERSEDED) and free text
+	ReasonCode *string `json:"reason_code,omitempty"`
+	Reason     *string `json:"reason,omitempty"`
 }
 
 // DocumentActionParticipant represents a participant in document action
@@ -64,6 +72,9 @@ type DocumentActionParticipant struct {
 	UserDetailID string                 `json:"user_detail_id" validate:"required,uuid"`
 	DocumentID   *string                `json:"document_id,omitempty"`
 	TemplateData map[string]string      `json:"template_data,omitempty"`
+
+	// doc_reject: document that replaces the revoked one
+	SupersededByID *string `json:"superseded_by_id,omitempty"`
 }
 
 // QRConfigRequest represents QR configuration from frontend
@@ -235,6 +246,9 @@ type DocumentDetailResponse struct {
 	SignedSignatures       int                          `json:"signed_signatures"`
 	IssueDate              time.Time                    `json:"issue_date"`
 	SignedAt               *time.Time                   `json:"signed_at,omitempty"`
+	Version                int                          `json:"version"`
+	PreviousDocumentID     *uuid.UUID                   `json:"previous_document_id,omitempty"`
+	ReplacedByID           *uuid.UUID                   `json:"replaced_by_id,omitempty"`
 	PDFJobID               *uuid.UUID                   `json:"pdf_job_id,omitempty"`
 	CreatedBy              uuid.UUID                    `json:"created_by"`
 	CreatedAt              time.Time                    `json:"created_at"`
@@ -243,6 +257,7 @@ type DocumentDetailResponse struct {
 	Event                  *EventEmbedded               `json:"event,omitempty"`
 	Template               *DocumentTemplateEmbedded    `json:"template,omitempty"`
 	PDFs                   []DocumentPDFResponse        `json:"pdfs"`
+	Revocation             *DocumentRevocationResponse  `json:"revocation,omitempty"`
 }
 
 // EventEmbedded represents embedded event info
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- archive job status constants
+
+const (
+	ArchiveStatusPending    = "PENDING"
+	ArchiveStatusProcessing = "PROCESSING"
+	ArchiveStatusCompleted  = "COMPLETED"
+	ArchiveStatusFailed     = "FAILED"
+)
+
+// -- manifest item status constants
+
+const (
+	ArchiveItemOK             = "OK"
+	ArchiveItemHashMismatch   = "HASH_MISMATCH"
+	ArchiveItemNoPDF          = "NO_PDF"
+	ArchiveItemDownloadFailed = "DOWNLOAD_FAILED"
+)
+
+// -- request dtos
+
+// DocumentArchiveRequest selects the certificates to bundle, by event or by pdf job
+type DocumentArchiveRequest struct {
+	EventID  *string `json:"event_id,omitempty" query:"event_id" validate:"omitempty,uuid"`
+	PDFJobID *string `json:"pdf_job_id,omitempty" query:"pdf_job_id" validate:"omitempty,uuid"`
+}
+
+// -- response dtos
+
+// DocumentArchiveInfo describes a direct archive download before it is streamed
+type DocumentArchiveInfo struct {
+	FileName   string `json:"file_name"`
+	TotalItems int    `json:"total_items"`
+}
+
+// DocumentArchiveResult summarizes a written archive
+type DocumentArchiveResult struct {
+	TotalItems     int `json:"total_items"`
+	ProcessedItems int `json:"processed_items"`
+	FailedItems    int `json:"failed_items"`
+}
+
+// DocumentArchiveJobResponse represents an async archive job
+type DocumentArchiveJobResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	EventID        *uuid.UUID `json:"event_id,omitempty"`
+	PDFJobID       *uuid.UUID `json:"pdf_job_id,omitempty"`
+	Status         string     `json:"status"`
+	TotalItems     int        `json:"total_items"`
+	ProcessedItems int        `json:"processed_items"`
+	FailedItems    int        `json:"failed_items"`
+	FileName       string     `json:"file_name"`
+	FileSizeBytes  *int64     `json:"file_size_bytes,omitempty"`
+	ErrorMessage   *string    `json:"error_message,omitempty"`
+	DownloadURL    *string    `json:"download_url,omitempty"`
+	RequestedBy    uuid.UUID  `json:"requested_by"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
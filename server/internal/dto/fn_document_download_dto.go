@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// -- download access reasons
+
+const (
+	DownloadAccessAdmin       = "ADMIN"
+	DownloadAccessEventOwner  = "EVENT_OWNER"
+	DownloadAccessBeneficiary = "BENEFICIARY"
+)
+
+// -- download outcomes
+
+const (
+	DownloadOutcomeGranted = "GRANTED"
+	DownloadOutcomeDenied  = "DENIED"
+	DownloadOutcomeFailed  = "FAILED"
+)
+
+// DocumentAccessor identifies the caller requesting a certificate
+type DocumentAccessor struct {
+	UserID uuid.UUID
+	// IsAdmin holds the documents.download_any permission
+	IsAdmin bool
+}
+
+// DocumentPDFDownloadRequest holds a certificate PDF download request
+type DocumentPDFDownloadRequest struct {
+	DocumentID  uuid.UUID
+	Version     *int
+	Accessor    DocumentAccessor
+	RangeHeader *string
+	IPAddress   string
+	UserAgent   string
+}
+
+// DocumentPDFDownload is an open certificate PDF stream. Body must be closed by the caller.
+type DocumentPDFDownload struct {
+	Body        io.ReadCloser
+	FileName    string
+	ContentType string
+	Size        int64
+}
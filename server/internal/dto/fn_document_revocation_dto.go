@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- revocation reason codes
+
+const (
+	RevocationReasonIssuedInError  = "ISSUED_IN_ERROR"
+	RevocationReasonDataCorrection = "DATA_CORRECTION"
+	RevocationReasonSuperseded     = "SUPERSEDED"
+	RevocationReasonFraud          = "FRAUD"
+	RevocationReasonWithdrawn      = "WITHDRAWN"
+	RevocationReasonUnspecified    = "UNSPECIFIED"
+)
+
+// RevocationReasonCodes lists the accepted reason codes for doc_reject
+var RevocationReasonCodes = map[string]bool{
+	RevocationReasonIssuedInError:  true,
+	RevocationReasonDataCorrection: true,
+	RevocationReasonSuperseded:     true,
+	RevocationReasonFraud:          true,
+	RevocationReasonWithdrawn:      true,
+	RevocationReasonUnspecified:    true,
+}
+
+// -- public verification status
+
+const (
+	VerificationStatusValid     = "VALID"
+	VerificationStatusRevoked   = "REVOKED"
+	VerificationStatusNotIssued = "NOT_ISSUED"
+)
+
+// -- response dtos
+
+// DocumentRevocationResponse represents the revocation in force of a document
+type DocumentRevocationResponse struct {
+	ID           uuid.UUID            `json:"id"`
+	ReasonCode   string               `json:"reason_code"`
+	Reason       *string              `json:"reason,omitempty"`
+	RevokedBy    uuid.UUID            `json:"revoked_by"`
+	RevokedAt    time.Time            `json:"revoked_at"`
+	SupersededBy *DocumentRefEmbedded `json:"superseded_by,omitempty"`
+}
+
+// DocumentRefEmbedded represents a reference to another document
+type DocumentRefEmbedded struct {
+	ID         uuid.UUID `json:"id"`
+	SerialCode string    `json:"serial_code"`
+}
+
+// PublicVerificationResponse represents the public status of a certificate.
+// It omits the free-text revocation reason, which is internal.
+type PublicVerificationResponse struct {
+	SerialCode      string                    `json:"serial_code"`
+	Status          string                    `json:"status"`
+	IssueDate       time.Time                 `json:"issue_date"`
+	BeneficiaryName string                    `json:"beneficiary_name"`
+	EventTitle      string                    `json:"event_title,omitempty"`
+	Version         int                       `json:"version"`
+	Revocation      *PublicRevocationResponse `json:"revocation,omitempty"`
+	CurrentVersion  *PublicDocumentVersion    `json:"current_version,omitempty"`
+}
+
+// PublicDocumentVersion points a reissued certificate to the version in force
+type PublicDocumentVersion struct {
+	SerialCode       string `json:"serial_code"`
+	VerificationCode string `json:"verification_code"`
+	Version          int    `json:"version"`
+	Status           string `json:"status"`
+}
+
+// PublicRevocationResponse represents the public part of a revocation
+type PublicRevocationResponse struct {
+	ReasonCode   string    `json:"reason_code"`
+	RevokedAt    time.Time `json:"revoked_at"`
+	SupersededBy *string   `json:"superseded_by,omitempty"`
+}
+
+// -- revocation list
+
+// RevocationList is the payload of the signed revocation list
+type RevocationList struct {
+	Version     int                   `json:"version"`
+	Issuer      string                `json:"issuer"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	NextUpdate  time.Time             `json:"next_update"`
+	Count       int                   `json:"count"`
+	Entries     []RevocationListEntry `json:"entries"`
+}
+
+// RevocationListEntry represents one revoked certificate
+type RevocationListEntry struct {
+	SerialCode   string    `json:"serial_code"`
+	ReasonCode   string    `json:"reason_code"`
+	RevokedAt    time.Time `json:"revoked_at"`
+	SupersededBy *string   `json:"superseded_by,omitempty"`
+}
+
+// SignedRevocationList is the revocation list as a JWS in flattened JSON
+// serialization (RFC 7515): payload and protected header are base64url JSON and
+// signature is Ed25519 over "protected.payload"
+type SignedRevocationList struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// JSONWebKeySet publishes the keys that sign the revocation list
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey represents an Ed25519 public key (RFC 8037)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
@@ -0,0 +1,270 @@
+package dto
+
+import (
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Evaluation statuses
+const (
+	EvaluationStatusPending = "pending"
+	EvaluationStatusActive  = "active"
+	EvaluationStatusClosed  = "closed"
+)
+
+// Question types; all but ESSAY are graded automatically
+const (
+	QuestionTypeMultipleChoice = "MULTIPLE_CHOICE"
+	QuestionTypeTrueFalse      = "TRUE_FALSE"
+	QuestionTypeShortAnswer    = "SHORT_ANSWER"
+	QuestionTypeEssay          = "ESSAY"
+)
+
+// QuestionTypes lists the valid question types
+var QuestionTypes = []string{
+	QuestionTypeMultipleChoice,
+	QuestionTypeTrueFalse,
+	QuestionTypeShortAnswer,
+	QuestionTypeEssay,
+}
+
+// Attempt statuses
+const (
+	AttemptStatusInProgress  = "IN_PROGRESS"
+	AttemptStatusUnderReview = "UNDER_REVIEW"
+	AttemptStatusGraded      = "GRADED"
+	AttemptStatusExpired     = "EXPIRED"
+)
+
+// Score verdicts: CORRECT/INCORRECT are set by auto-grading, PENDING waits for a
+// reviewer, APPROVED/PARTIAL/REJECTED are set by the reviewer
+const (
+	VerdictCorrect   = "CORRECT"
+	VerdictIncorrect = "INCORRECT"
+	VerdictPending   = "PENDING"
+	VerdictApproved  = "APPROVED"
+	VerdictPartial   = "PARTIAL"
+	VerdictRejected  = "REJECTED"
+)
+
+// True/false questions get these fixed option keys
+const (
+	TrueFalseKeyTrue  = "true"
+	TrueFalseKeyFalse = "false"
+)
+
+// Report PDF statuses
+const (
+	ReportPDFStatusPending   = "PENDING"
+	ReportPDFStatusCompleted = "COMPLETED"
+	ReportPDFStatusFailed    = "FAILED"
+)
+
+// Report download formats
+const (
+	ReportFormatMarkdown = "md"
+	ReportFormatPDF      = "pdf"
+)
+
+// -- request dtos
+
+// EvaluationCreateRequest represents the request to create an evaluation with its questions
+type EvaluationCreateRequest struct {
+	Title            string                    `json:"title" validate:"required,min=1,max=200"`
+	Description      *string                   `json:"description,omitempty"`
+	DocumentID       *string                   `json:"document_id,omitempty" validate:"omitempty,uuid"`
+	Status           *string                   `json:"status,omitempty"`
+	PassingScore     *float64                  `json:"passing_score,omitempty"`
+	TimeLimitMinutes *int                      `json:"time_limit_minutes,omitempty"`
+	MaxAttempts      *int                      `json:"max_attempts,omitempty"`
+	Questions        []EvaluationQuestionInput `json:"questions,omitempty"`
+	// DrawRules replace Questions: each attempt draws its own questions from the bank
+	DrawRules []EvaluationDrawRuleInput `json:"draw_rules,omitempty"`
+}
+
+// EvaluationUpdateRequest represents the request to update evaluation settings
+type EvaluationUpdateRequest struct {
+	Title            *string  `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	Description      *string  `json:"description,omitempty"`
+	Status           *string  `json:"status,omitempty"`
+	PassingScore     *float64 `json:"passing_score,omitempty"`
+	TimeLimitMinutes *int     `json:"time_limit_minutes,omitempty"`
+	MaxAttempts      *int     `json:"max_attempts,omitempty"`
+}
+
+// EvaluationQuestionsRequest replaces all questions of an evaluation
+type EvaluationQuestionsRequest struct {
+	Questions []EvaluationQuestionInput `json:"questions" validate:"required,min=1"`
+}
+
+// EvaluationDrawRulesRequest replaces the draw rules of an evaluation; an empty list
+// turns it back into an evaluation with fixed questions
+type EvaluationDrawRulesRequest struct {
+	Rules []EvaluationDrawRuleInput `json:"rules"`
+}
+
+// EvaluationDrawRuleInput draws Count random active bank questions of a topic,
+// optionally of one difficulty
+type EvaluationDrawRuleInput struct {
+	Topic      string  `json:"topic" validate:"required"`
+	Difficulty *string `json:"difficulty,omitempty"`
+	Count      int     `json:"count" validate:"required,min=1"`
+}
+
+// EvaluationQuestionInput represents a question in evaluation authoring.
+// For SHORT_ANSWER the options are the accepted answers; for TRUE_FALSE only
+// CorrectAnswer is needed.
+type EvaluationQuestionInput struct {
+	QuestionText  string                  `json:"question_text" validate:"required"`
+	QuestionType  string                  `json:"question_type" validate:"required"`
+	MaxScore      *float64                `json:"max_score,omitempty"`
+	Options       []EvaluationOptionInput `json:"options,omitempty"`
+	CorrectAnswer *bool                   `json:"correct_answer,omitempty"`
+}
+
+// EvaluationOptionInput represents an option of a question
+type EvaluationOptionInput struct {
+	Key       string `json:"key,omitempty"`
+	Text      string `json:"text" validate:"required"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// AttemptSubmitRequest carries the answers of an attempt
+type AttemptSubmitRequest struct {
+	Answers []AttemptAnswerInput `json:"answers"`
+}
+
+// AttemptAnswerInput represents the answer to one question; choice questions use
+// SelectedOptions, short answer and essay use ResponseText
+type AttemptAnswerInput struct {
+	QuestionID      string   `json:"question_id" validate:"required,uuid"`
+	SelectedOptions []string `json:"selected_options,omitempty"`
+	ResponseText    string   `json:"response_text,omitempty"`
+}
+
+// ScoreReviewRequest represents a reviewer's verdict on a pending answer
+type ScoreReviewRequest struct {
+	Verdict string   `json:"verdict" validate:"required,oneof=APPROVED PARTIAL REJECTED"`
+	Score   *float64 `json:"score,omitempty"`
+	Remarks *string  `json:"remarks,omitempty"`
+}
+
+// ReviewQueueQuery holds the filters of the review queue
+type ReviewQueueQuery struct {
+	EvaluationID *uuid.UUID
+	Page         int
+	PageSize     int
+}
+
+// -- response dtos
+
+// EvaluationResponse represents an evaluation with its answer key (authoring view)
+type EvaluationResponse struct {
+	ID               uuid.UUID                    `json:"id"`
+	Title            string                       `json:"title"`
+	Description      *string                      `json:"description,omitempty"`
+	DocumentID       *uuid.UUID                   `json:"document_id,omitempty"`
+	Status           string                       `json:"status"`
+	PassingScore     float64                      `json:"passing_score"`
+	TimeLimitMinutes *int                         `json:"time_limit_minutes,omitempty"`
+	MaxAttempts      int                          `json:"max_attempts"`
+	MaxScore         float64                      `json:"max_score"`
+	CreatedBy        uuid.UUID                    `json:"created_by"`
+	CreatedAt        time.Time                    `json:"created_at"`
+	UpdatedAt        time.Time                    `json:"updated_at"`
+	Questions        []EvaluationQuestionResponse `json:"questions"`
+	DrawRules        []EvaluationDrawRuleResponse `json:"draw_rules,omitempty"`
+}
+
+// EvaluationDrawRuleResponse represents a draw rule of an evaluation
+type EvaluationDrawRuleResponse struct {
+	Topic      string  `json:"topic"`
+	Difficulty *string `json:"difficulty,omitempty"`
+	Count      int     `json:"count"`
+}
+
+// EvaluationQuestionResponse represents a question; IsCorrect is omitted for takers
+type EvaluationQuestionResponse struct {
+	ID             uuid.UUID                  `json:"id"`
+	QuestionNumber int                        `json:"question_number"`
+	QuestionText   string                     `json:"question_text"`
+	QuestionType   string                     `json:"question_type"`
+	MaxScore       float64                    `json:"max_score"`
+	Options        []EvaluationOptionResponse `json:"options,omitempty"`
+}
+
+// EvaluationOptionResponse represents an option of a question
+type EvaluationOptionResponse struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect *bool  `json:"is_correct,omitempty"`
+}
+
+// AttemptResponse represents an attempt. Questions are sent while it is in
+// progress (without the answer key); Results once it is submitted.
+type AttemptResponse struct {
+	ID            uuid.UUID                    `json:"id"`
+	EvaluationID  uuid.UUID                    `json:"evaluation_id"`
+	UserID        uuid.UUID                    `json:"user_id"`
+	AttemptNumber int                          `json:"attempt_number"`
+	Status        string                       `json:"status"`
+	StartedAt     time.Time                    `json:"started_at"`
+	ExpiresAt     *time.Time                   `json:"expires_at,omitempty"`
+	SubmittedAt   *time.Time                   `json:"submitted_at,omitempty"`
+	GradedAt      *time.Time                   `json:"graded_at,omitempty"`
+	Score         float64                      `json:"score"`
+	MaxScore      float64                      `json:"max_score"`
+	Percentage    float64                      `json:"percentage"`
+	PassingScore  float64                      `json:"passing_score"`
+	Passed        *bool                        `json:"passed"`
+	Questions     []EvaluationQuestionResponse `json:"questions,omitempty"`
+	Results       []AttemptAnswerResult        `json:"results,omitempty"`
+}
+
+// AttemptAnswerResult represents the grading of one answer
+type AttemptAnswerResult struct {
+	QuestionID     uuid.UUID `json:"question_id"`
+	QuestionNumber int       `json:"question_number"`
+	QuestionType   string    `json:"question_type"`
+	ResponseText   string    `json:"response_text"`
+	Verdict        string    `json:"verdict"`
+	Score          float64   `json:"score"`
+	MaxScore       float64   `json:"max_score"`
+	Remarks        *string   `json:"remarks,omitempty"`
+}
+
+// ReviewQueueItem represents an answer waiting for manual review
+type ReviewQueueItem struct {
+	ScoreID         uuid.UUID `json:"score_id"`
+	AttemptID       uuid.UUID `json:"attempt_id"`
+	EvaluationID    uuid.UUID `json:"evaluation_id"`
+	EvaluationTitle string    `json:"evaluation_title"`
+	UserID          uuid.UUID `json:"user_id"`
+	QuestionID      uuid.UUID `json:"question_id"`
+	QuestionNumber  int       `json:"question_number"`
+	QuestionText    string    `json:"question_text"`
+	MaxScore        float64   `json:"max_score"`
+	ResponseText    string    `json:"response_text"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+}
+
+// EvaluationReportResponse represents the Markdown report of a graded attempt
+type EvaluationReportResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	EvaluationID    uuid.UUID  `json:"evaluation_id"`
+	AttemptID       uuid.UUID  `json:"attempt_id"`
+	GeneratedAt     time.Time  `json:"generated_at"`
+	PDFStatus       *string    `json:"pdf_status,omitempty"`
+	PDFJobID        *uuid.UUID `json:"pdf_job_id,omitempty"`
+	MarkdownContent string     `json:"markdown_content"`
+}
+
+// EvaluationReportFile is an open report (Markdown or PDF). Body must be closed by the caller.
+type EvaluationReportFile struct {
+	Body        io.ReadCloser
+	FileName    string
+	ContentType string
+	Size        int64
+}
\ No newline at end of file
@@ -18,6 +18,10 @@ type EventCreateRequest struct {
 	CertificateSeries       *string                         `json:"certificate_series,omitempty"`
 	OrganizationalUnitsPath *string                         `json:"organizational_units_path,omitempty"`
 	TemplateID              *string                         `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	EvaluationID            *string                         `json:"evaluation_id,omitempty" validate:"omitempty,uuid"`
+	AutoIssueCertificates   *bool                           `json:"auto_issue_certificates,omitempty"`
+	StudyMaterialID         *string                         `json:"study_material_id,omitempty" validate:"omitempty,uuid"`
+	StudyMinPercentage      *float64                        `json:"study_min_percentage,omitempty"`
 	MaxParticipants         *int                            `json:"max_participants,omitempty"`
 	RegistrationOpenAt      *time.Time                      `json:"registration_open_at,omitempty"`
 	RegistrationCloseAt     *time.Time                      `json:"registration_close_at,omitempty"`
@@ -54,6 +58,10 @@ type EventUpdateRequest struct {
 	CertificateSeries       *string    `json:"certificate_series,omitempty"`
 	OrganizationalUnitsPath *string    `json:"organizational_units_path,omitempty"`
 	TemplateID              *string    `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	EvaluationID            *string    `json:"evaluation_id,omitempty" validate:"omitempty,uuid"`
+	AutoIssueCertificates   *bool      `json:"auto_issue_certificates,omitempty"`
+	StudyMaterialID         *string    `json:"study_material_id,omitempty" validate:"omitempty,uuid"`
+	StudyMinPercentage      *float64   `json:"study_min_percentage,omitempty"`
 	MaxParticipants         *int       `json:"max_participants,omitempty"`
 	RegistrationOpenAt      *time.Time `json:"registration_open_at,omitempty"`
 	RegistrationCloseAt     *time.Time `json:"registration_close_at,omitempty"`
@@ -85,6 +93,10 @@ type EventResponse struct {
 	MaxParticipants         *int                         `json:"max_participants,omitempty"`
 	RegistrationOpenAt      *time.Time                   `json:"registration_open_at,omitempty"`
 	RegistrationCloseAt     *time.Time                   `json:"registration_close_at,omitempty"`
+	EvaluationID            *uuid.UUID                   `json:"evaluation_id,omitempty"`
+	AutoIssueCertificates   bool                         `json:"auto_issue_certificates"`
+	StudyMaterialID         *uuid.UUID                   `json:"study_material_id,omitempty"`
+	StudyMinPercentage      float64                      `json:"study_min_percentage"`
 	Status                  string                       `json:"status"`
 	CreatedBy               uuid.UUID                    `json:"created_by"`
 	CreatedAt               time.Time                    `json:"created_at"`
@@ -111,12 +123,14 @@ type EventScheduleResponse struct {
 
 // EventParticipantResponse represents a participant in response
 type EventParticipantResponse struct {
-	ID                 uuid.UUID               `json:"id"`
-	RegistrationSource *string                 `json:"registration_source,omitempty"`
-	RegistrationStatus string                  `json:"registration_status"`
-	AttendanceStatus   string                  `json:"attendance_status"`
-	CreatedAt          time.Time               `json:"created_at"`
-	UserDetail         UserDetailEmbedded      `json:"user_detail"`
+	ID                  uuid.UUID               `json:"id"`
+	RegistrationSource  *string                 `json:"registration_source,omitempty"`
+	RegistrationStatus  string                  `json:"registration_status"`
+	AttendanceStatus    string                  `json:"attendance_status"`
+	CertificateEligible bool                    `json:"certificate_eligible"`
+	FinalScore          *float64                `json:"final_score,omitempty"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UserDetail          UserDetailEmbedded      `json:"user_detail"`
 }
 
 // UserDetailEmbedded represents embedded user detail info
@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- participant status constants
+
+const (
+	// registration status
+	ParticipantRegistrationRegistered = "REGISTERED"
+	ParticipantRegistrationConfirmed  = "CONFIRMED"
+	ParticipantRegistrationCancelled  = "CANCELLED"
+
+	// attendance status
+	ParticipantAttendancePending  = "PENDING"
+	ParticipantAttendanceAttended = "ATTENDED"
+	ParticipantAttendanceAbsent   = "ABSENT"
+)
+
+// ParticipantRegistrationStatuses lists the accepted registration statuses
+var ParticipantRegistrationStatuses = map[string]bool{
+	ParticipantRegistrationRegistered: true,
+	ParticipantRegistrationConfirmed:  true,
+	ParticipantRegistrationCancelled:  true,
+}
+
+// ParticipantAttendanceStatuses lists the accepted attendance statuses
+var ParticipantAttendanceStatuses = map[string]bool{
+	ParticipantAttendancePending:  true,
+	ParticipantAttendanceAttended: true,
+	ParticipantAttendanceAbsent:   true,
+}
+
+// Progress stages where a participant can be blocked, in the order they are checked
+const (
+	ProgressBlockedAccount    = "ACCOUNT"        // no user account shares the national ID
+	ProgressBlockedStudy      = "STUDY_MATERIAL" // study material below the event's minimum
+	ProgressBlockedEvaluation = "EVALUATION"     // evaluation not passed yet
+)
+
+// -- request dtos
+
+// EventParticipantAddRequest represents the request to add participants to an event
+type EventParticipantAddRequest struct {
+	Participants []EventParticipantCreateRequest `json:"participants" validate:"required,min=1"`
+}
+
+// EventParticipantPatchRequest represents the request to modify a single participant
+type EventParticipantPatchRequest struct {
+	FirstName          *string `json:"first_name,omitempty" validate:"omitempty,min=1,max=100"`
+	LastName           *string `json:"last_name,omitempty" validate:"omitempty,min=1,max=100"`
+	Email              *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone              *string `json:"phone,omitempty"`
+	RegistrationSource *string `json:"registration_source,omitempty"`
+	RegistrationStatus *string `json:"registration_status,omitempty"`
+	AttendanceStatus   *string `json:"attendance_status,omitempty"`
+}
+
+// EventParticipantBulkStatusRequest represents the request to change the status of several participants
+type EventParticipantBulkStatusRequest struct {
+	ParticipantIDs     []string `json:"participant_ids" validate:"required,min=1"`
+	RegistrationStatus *string  `json:"registration_status,omitempty"`
+	AttendanceStatus   *string  `json:"attendance_status,omitempty"`
+}
+
+// EventParticipantRemoveQuery represents query parameters for removing a participant.
+// ReasonCode and Reason are recorded on the revocation when force revokes a generated document.
+type EventParticipantRemoveQuery struct {
+	Force      bool    `query:"force"`
+	ReasonCode *string `query:"reason_code"`
+	Reason     *string `query:"reason"`
+}
+
+// EventParticipantListQuery represents query parameters for listing event participants
+type EventParticipantListQuery struct {
+	Page               int     `query:"page"`
+	PageSize           int     `query:"page_size"`
+	SearchQuery        *string `query:"q"`
+	RegistrationStatus *string `query:"registration_status"`
+	AttendanceStatus   *string `query:"attendance_status"`
+}
+
+// -- response dtos
+
+// EventParticipantListItem represents a participant item in list response
+type EventParticipantListItem struct {
+	ID                  uuid.UUID          `json:"id"`
+	EventID             uuid.UUID          `json:"event_id"`
+	RegistrationSource  *string            `json:"registration_source,omitempty"`
+	RegistrationStatus  string             `json:"registration_status"`
+	AttendanceStatus    string             `json:"attendance_status"`
+	CertificateEligible bool               `json:"certificate_eligible"`
+	EligibleAt          *time.Time         `json:"eligible_at,omitempty"`
+	FinalScore          *float64           `json:"final_score,omitempty"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+	UserDetail          UserDetailEmbedded `json:"user_detail"`
+	DocumentID          *uuid.UUID         `json:"document_id,omitempty"`
+	DocumentStatus      *string            `json:"document_status,omitempty"`
+	SerialCode          *string            `json:"serial_code,omitempty"`
+}
+
+// EventParticipantAddResponse represents the result of adding participants
+type EventParticipantAddResponse struct {
+	EventID      uuid.UUID                       `json:"event_id"`
+	AddedCount   int                             `json:"added_count"`
+	SkippedCount int                             `json:"skipped_count"`
+	Results      []EventParticipantAddResultItem `json:"results"`
+}
+
+// EventParticipantAddResultItem represents the result for each participant added
+type EventParticipantAddResultItem struct {
+	NationalID    string     `json:"national_id"`
+	ParticipantID *uuid.UUID `json:"participant_id,omitempty"`
+	UserDetailID  *uuid.UUID `json:"user_detail_id,omitempty"`
+	Error         *string    `json:"error,omitempty"`
+}
+
+// EventParticipantBulkStatusResponse represents the result of a bulk status change
+type EventParticipantBulkStatusResponse struct {
+	EventID      uuid.UUID `json:"event_id"`
+	UpdatedCount int64     `json:"updated_count"`
+}
+
+// EventParticipantProgressItem represents a participant's way towards the certificate:
+// study material, evaluation and the first stage still blocking them
+type EventParticipantProgressItem struct {
+	ParticipantID    uuid.UUID              `json:"participant_id"`
+	UserDetail       UserDetailEmbedded     `json:"user_detail"`
+	HasAccount       bool                   `json:"has_account"`
+	Study            *StudyProgressResponse `json:"study,omitempty"`
+	StudyCompleted   bool                   `json:"study_completed"`
+	EvaluationPassed bool                   `json:"evaluation_passed"`
+	FinalScore       *float64               `json:"final_score,omitempty"`
+	Eligible         bool                   `json:"eligible"`
+	BlockedAt        *string                `json:"blocked_at,omitempty"`
+}
+
+// -- register export dtos
+
+// Register export scopes
+const (
+	RegisterScopeRoster   = "roster"   // every participant, with or without document
+	RegisterScopeRegister = "register" // only participants with a document
+)
+
+// DocumentRegisterRow represents a participant joined with its document for exports
+type DocumentRegisterRow struct {
+	NationalID             string     `json:"national_id"`
+	FirstName              string     `json:"first_name"`
+	LastName               string     `json:"last_name"`
+	Email                  *string    `json:"email,omitempty"`
+	RegistrationStatus     string     `json:"registration_status"`
+	AttendanceStatus       string     `json:"attendance_status"`
+	DocumentID             *uuid.UUID `json:"document_id,omitempty"`
+	SerialCode             *string    `json:"serial_code,omitempty"`
+	VerificationCode       *string    `json:"verification_code,omitempty"`
+	Status                 *string    `json:"status,omitempty"`
+	DigitalSignatureStatus *string    `json:"digital_signature_status,omitempty"`
+	IssueDate              *time.Time `json:"issue_date,omitempty"`
+}
+
+// DocumentRegisterExportQuery represents query parameters for exporting an event register
+type DocumentRegisterExportQuery struct {
+	Format  string `query:"format"`
+	Scope   string `query:"scope"`
+	Filters DocumentListQuery
+}
+
+// DocumentRegisterExportInfo describes a register export before it is streamed
+type DocumentRegisterExportInfo struct {
+	FileName    string
+	ContentType string
+	EventTitle  string
+	EventCode   string
+}
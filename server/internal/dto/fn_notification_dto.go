@@ -0,0 +1,255 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- domain event types
+
+const (
+	DomainEventDocumentStatusChanged = "document.status_changed"
+	DomainEventEventStatusChanged    = "event.status_changed"
+	DomainEventPDFBatchFinished      = "pdf.batch_finished"
+)
+
+// -- notification channels and delivery status
+
+const (
+	NotificationChannelInApp = "IN_APP"
+	NotificationChannelEmail = "EMAIL"
+
+	DeliveryStatusPending = "PENDING"
+	DeliveryStatusSent    = "SENT"
+	DeliveryStatusFailed  = "FAILED"
+	DeliveryStatusSkipped = "SKIPPED"
+	// waiting for the user's hourly/daily digest
+	DeliveryStatusDigestPending = "DIGEST_PENDING"
+	// folded into a digest delivery (see DigestID)
+	DeliveryStatusDigested = "DIGESTED"
+)
+
+// -- notification types and preference modes
+
+// Notification types are the template keys a user can set preferences for
+const (
+	NotificationTypeDocumentGenerated  = "document.generated"
+	NotificationTypeDocumentRevoked    = "document.revoked"
+	NotificationTypeDocumentRenewed    = "document.renewed"
+	NotificationTypeEventStatusChanged = "event.status_changed"
+	NotificationTypePDFBatchFinished   = "pdf.batch_finished"
+
+	// NotificationTypeDigest is the summary message built from digested notifications
+	NotificationTypeDigest = "digest"
+)
+
+// NotificationTypes lists the notification types users can configure
+var NotificationTypes = []string{
+	NotificationTypeDocumentGenerated,
+	NotificationTypeDocumentRevoked,
+	NotificationTypeDocumentRenewed,
+	NotificationTypeEventStatusChanged,
+	NotificationTypePDFBatchFinished,
+}
+
+// NotificationChannels lists the channels users can configure
+var NotificationChannels = []string{
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+}
+
+const (
+	NotificationModeImmediate = "IMMEDIATE"
+	NotificationModeHourly    = "HOURLY"
+	NotificationModeDaily     = "DAILY"
+	NotificationModeNone      = "NONE"
+)
+
+// -- domain events (nats)
+
+// DomainEvent is the envelope published on the domain.* subjects
+type DomainEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	EventType  string          `json:"event_type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// DocumentStatusChangedPayload is published when a certificate changes status
+type DocumentStatusChangedPayload struct {
+	DocumentID     uuid.UUID  `json:"document_id"`
+	UserDetailID   uuid.UUID  `json:"user_detail_id"`
+	EventID        *uuid.UUID `json:"event_id,omitempty"`
+	PreviousStatus string     `json:"previous_status"`
+	Status         string     `json:"status"`
+	ActorID        *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// EventStatusChangedPayload is published when an event changes status
+type EventStatusChangedPayload struct {
+	EventID        uuid.UUID  `json:"event_id"`
+	PreviousStatus string     `json:"previous_status"`
+	Status         string     `json:"status"`
+	ActorID        *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// PDFBatchFinishedPayload is published once a PDF batch has been processed
+type PDFBatchFinishedPayload struct {
+	PDFJobID     uuid.UUID  `json:"pdf_job_id"`
+	EventID      *uuid.UUID `json:"event_id,omitempty"`
+	TotalItems   int        `json:"total_items"`
+	SuccessCount int        `json:"success_count"`
+	FailedCount  int        `json:"failed_count"`
+	Error        *string    `json:"error,omitempty"`
+}
+
+// -- notification stream (sse)
+
+// Stream event names sent to connected clients
+const (
+	NotificationStreamEventNotification = "notification"
+	NotificationStreamEventUnreadCount  = "unread_count"
+)
+
+// NotificationStreamMessage is fanned out over NATS to every replica; each one
+// forwards it to the streams the user has open there
+type NotificationStreamMessage struct {
+	UserID       uuid.UUID         `json:"user_id"`
+	Notification *NotificationItem `json:"notification,omitempty"`
+	UnreadCount  int64             `json:"unread_count"`
+}
+
+// -- recipients
+
+// NotificationRecipient is a person to notify: a beneficiary (user detail),
+// an account (user) or both when they share the national ID
+type NotificationRecipient struct {
+	UserDetailID *uuid.UUID
+	UserID       *uuid.UUID
+	FirstName    string
+	LastName     string
+	Email        *string
+}
+
+// -- request dtos
+
+// NotificationPreferenceItem is the delivery mode of one notification type on one channel
+type NotificationPreferenceItem struct {
+	NotificationType string `json:"notification_type" validate:"required"`
+	Channel          string `json:"channel" validate:"required,oneof=IN_APP EMAIL"`
+	Mode             string `json:"mode" validate:"required,oneof=IMMEDIATE HOURLY DAILY NONE"`
+}
+
+// MarkNotificationsReadRequest marks the caller's notifications with these IDs as read
+type MarkNotificationsReadRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=500"`
+}
+
+// UpdateNotificationPreferencesRequest upserts the given preferences; omitted ones are kept
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceItem `json:"preferences" validate:"required,min=1,dive"`
+}
+
+// UserNotificationFilter selects a page of a user's notifications, newest first.
+// Before is the (created_at, id) of the last item of the previous page.
+type UserNotificationFilter struct {
+	BeforeCreatedAt *time.Time
+	BeforeID        uuid.UUID
+	Types           []string
+	UnreadOnly      bool
+	Limit           int
+}
+
+// NotificationDigestGroup is one user's channel with notifications waiting for a digest
+type NotificationDigestGroup struct {
+	UserID  uuid.UUID
+	Channel string
+}
+
+// -- query params
+
+// MyNotificationListQuery represents query params for the caller's notifications
+type MyNotificationListQuery struct {
+	Cursor     string   `query:"cursor"`
+	Limit      int      `query:"limit"`
+	Types      []string `query:"type"`
+	UnreadOnly bool     `query:"unread_only"`
+}
+
+// NotificationDeliveryListQuery represents query params for listing deliveries
+type NotificationDeliveryListQuery struct {
+	Page           int        `query:"page"`
+	PageSize       int        `query:"page_size"`
+	Status         *string    `query:"status"`
+	Channel        *string    `query:"channel"`
+	UserDetailID   *uuid.UUID `query:"user_detail_id"`
+	UserID         *uuid.UUID `query:"user_id"`
+	TemplateKey    *string    `query:"template_key"`
+	NotificationID *uuid.UUID `query:"notification_id"`
+}
+
+// -- response dtos
+
+// NotificationItem is an in-app notification as shown to its owner
+type NotificationItem struct {
+	ID               uuid.UUID  `json:"id"`
+	Title            string     `json:"title"`
+	Body             string     `json:"body"`
+	NotificationType *string    `json:"notification_type,omitempty"`
+	IsRead           bool       `json:"is_read"`
+	ReadAt           *time.Time `json:"read_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// MyNotificationListResponse is a cursor page of the caller's notifications.
+// NextCursor is empty on the last page.
+type MyNotificationListResponse struct {
+	Items       []NotificationItem `json:"items"`
+	NextCursor  string             `json:"next_cursor,omitempty"`
+	UnreadCount int64              `json:"unread_count"`
+}
+
+// NotificationBulkResult reports how many notifications a bulk operation touched
+type NotificationBulkResult struct {
+	Affected    int64 `json:"affected"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// UnreadCountResponse is the payload of the unread_count stream event
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// NotificationPreferencesResponse is the caller's full preference matrix:
+// every notification type and channel, IMMEDIATE when not configured
+type NotificationPreferencesResponse struct {
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+	Types       []string                     `json:"types"`
+	Channels    []string                     `json:"channels"`
+	Modes       []string                     `json:"modes"`
+}
+
+// NotificationDeliveryResponse represents one delivery attempt record
+type NotificationDeliveryResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	NotificationID *uuid.UUID `json:"notification_id,omitempty"`
+	UserDetailID   *uuid.UUID `json:"user_detail_id,omitempty"`
+	UserID         *uuid.UUID `json:"user_id,omitempty"`
+	Channel        string     `json:"channel"`
+	Recipient      string     `json:"recipient"`
+	TemplateKey    string     `json:"template_key"`
+	Locale         string     `json:"locale"`
+	Subject        string     `json:"subject"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      *string    `json:"last_error,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	DigestMode     *string    `json:"digest_mode,omitempty"`
+	DigestID       *uuid.UUID `json:"digest_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
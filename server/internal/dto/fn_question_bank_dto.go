@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bank question difficulties
+const (
+	DifficultyEasy   = "EASY"
+	DifficultyMedium = "MEDIUM"
+	DifficultyHard   = "HARD"
+)
+
+// Difficulties lists the valid difficulties
+var Difficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// -- request dtos
+
+// BankQuestionRequest represents the request to create or replace a bank question
+type BankQuestionRequest struct {
+	Topic      string `json:"topic" validate:"required,max=100"`
+	Difficulty string `json:"difficulty,omitempty"`
+	EvaluationQuestionInput
+}
+
+// BankQuestionListQuery holds the filters of the question bank list
+type BankQuestionListQuery struct {
+	Topic        *string
+	Difficulty   *string
+	QuestionType *string
+	SearchQuery  *string
+	IsActive     *bool
+	Page         int
+	PageSize     int
+}
+
+// -- response dtos
+
+// BankQuestionResponse represents a bank question with its answer key
+type BankQuestionResponse struct {
+	ID           uuid.UUID                  `json:"id"`
+	Topic        string                     `json:"topic"`
+	Difficulty   string                     `json:"difficulty"`
+	QuestionText string                     `json:"question_text"`
+	QuestionType string                     `json:"question_type"`
+	MaxScore     float64                    `json:"max_score"`
+	IsActive     bool                       `json:"is_active"`
+	Options      []EvaluationOptionResponse `json:"options,omitempty"`
+	CreatedBy    uuid.UUID                  `json:"created_by"`
+	CreatedAt    time.Time                  `json:"created_at"`
+	UpdatedAt    time.Time                  `json:"updated_at"`
+}
+
+// BankItemStats represents the item analysis of a bank question over graded attempts.
+// SuccessRate is the mean share of the points obtained (0-1); Discrimination is the
+// correlation between the item and the rest of the attempt (-1 to 1). Both are nil
+// without enough responses.
+type BankItemStats struct {
+	QuestionID     uuid.UUID `json:"question_id"`
+	Topic          string    `json:"topic"`
+	Difficulty     string    `json:"difficulty"`
+	QuestionText   string    `json:"question_text"`
+	QuestionType   string    `json:"question_type"`
+	IsActive       bool      `json:"is_active"`
+	Responses      int       `json:"responses"`
+	FullCredit     int       `json:"full_credit"`
+	SuccessRate    *float64  `json:"success_rate"`
+	Discrimination *float64  `json:"discrimination"`
+}
+
+// BankItemScoreRow is one graded answer to a drawn copy of a bank question, with
+// the totals of its attempt
+type BankItemScoreRow struct {
+	BankQuestionID uuid.UUID
+	ItemScore      float64
+	ItemMax        float64
+	AttemptScore   float64
+	AttemptMax     float64
+}
\ No newline at end of file
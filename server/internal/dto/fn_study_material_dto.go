@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// -- request dtos
+
+// StudySectionRequest represents the request to create or update a section of a
+// study material. Without OrderIndex a new section goes last.
+type StudySectionRequest struct {
+	Title       string  `json:"title" validate:"required"`
+	Description *string `json:"description,omitempty"`
+	OrderIndex  *int    `json:"order_index,omitempty"`
+}
+
+// StudySubsectionRequest represents the request to create or update a subsection.
+// Without OrderIndex a new subsection goes last in its section.
+type StudySubsectionRequest struct {
+	Title       string  `json:"title" validate:"required"`
+	Description *string `json:"description,omitempty"`
+	VideoURL    *string `json:"video_url,omitempty"`
+	OrderIndex  *int    `json:"order_index,omitempty"`
+}
+
+// StudyReorderRequest lists every child ID in the new order
+type StudyReorderRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// StudyProgressRequest marks a subsection complete or incomplete for the caller
+type StudyProgressRequest struct {
+	Completed bool `json:"completed"`
+}
+
+// StudyAnnotationRequest represents the content of a personal annotation
+type StudyAnnotationRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+// -- response dtos
+
+// StudyOutlineResponse represents a study material with its sections and subsections
+// in order and the caller's completion state
+type StudyOutlineResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	Title       string                 `json:"title"`
+	Description *string                `json:"description,omitempty"`
+	Progress    StudyProgressResponse  `json:"progress"`
+	Sections    []StudySectionResponse `json:"sections"`
+}
+
+// StudySectionResponse represents a section of the outline
+type StudySectionResponse struct {
+	ID                   uuid.UUID                 `json:"id"`
+	Title                string                    `json:"title"`
+	Description          *string                   `json:"description,omitempty"`
+	OrderIndex           *int                      `json:"order_index"`
+	TotalSubsections     int                       `json:"total_subsections"`
+	CompletedSubsections int                       `json:"completed_subsections"`
+	Subsections          []StudySubsectionResponse `json:"subsections"`
+}
+
+// StudySubsectionResponse represents a subsection of the outline with the caller's state
+type StudySubsectionResponse struct {
+	ID              uuid.UUID               `json:"id"`
+	SectionID       uuid.UUID               `json:"section_id"`
+	Title           string                  `json:"title"`
+	Description     *string                 `json:"description,omitempty"`
+	VideoURL        *string                 `json:"video_url,omitempty"`
+	OrderIndex      *int                    `json:"order_index"`
+	Completed       bool                    `json:"completed"`
+	CompletedAt     *time.Time              `json:"completed_at,omitempty"`
+	AnnotationCount int64                   `json:"annotation_count"`
+	Resources       []StudyResourceResponse `json:"resources,omitempty"`
+}
+
+// StudyResourceResponse represents a downloadable resource of a subsection
+type StudyResourceResponse struct {
+	ID       uuid.UUID `json:"id"`
+	FileName string    `json:"file_name"`
+	FileURL  string    `json:"file_url"`
+	FileType string    `json:"file_type"`
+}
+
+// StudyProgressResponse represents a user's progress through a study material
+type StudyProgressResponse struct {
+	MaterialID           uuid.UUID `json:"material_id"`
+	TotalSubsections     int64     `json:"total_subsections"`
+	CompletedSubsections int64     `json:"completed_subsections"`
+	Percentage           float64   `json:"percentage"`
+}
+
+// StudyAnnotationResponse represents a personal annotation on a subsection
+type StudyAnnotationResponse struct {
+	ID           uuid.UUID `json:"id"`
+	SubsectionID uuid.UUID `json:"subsection_id"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
\ No newline at end of file
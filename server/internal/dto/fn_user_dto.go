@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserProvisionRequest carries the token claims used to upsert the local account
+type UserProvisionRequest struct {
+	Subject string
+	Email   string
+	// EmailVerified allows linking an existing account by email
+	EmailVerified bool
+	NationalID    string
+	FirstName     string
+	LastName      string
+	// Roles are stored on the account and bound the API keys it creates
+	Roles []string
+}
+
+// MeResponse represents the authenticated caller's local account
+type MeResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	KeycloakSub string              `json:"keycloak_sub"`
+	Username    string              `json:"username"`
+	Email       string              `json:"email"`
+	NationalID  string              `json:"national_id"`
+	FirstName   string              `json:"first_name"`
+	LastName    string              `json:"last_name"`
+	Roles       []string            `json:"roles"`
+	LastLoginAt *time.Time          `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UserDetail  *UserDetailEmbedded `json:"user_detail,omitempty"`
+}
+
+// MyDocumentListQuery represents query params for the caller's certificates
+type MyDocumentListQuery struct {
+	Page     int     `query:"page"`
+	PageSize int     `query:"page_size"`
+	EventID  *string `query:"event_id"`
+	// IncludeAll also lists rejected and not yet generated documents
+	IncludeAll bool `query:"include_all"`
+}
+
+// MyDocumentItem represents one of the caller's certificates
+type MyDocumentItem struct {
+	ID               uuid.UUID  `json:"id"`
+	SerialCode       string     `json:"serial_code"`
+	VerificationCode string     `json:"verification_code"`
+	Status           string     `json:"status"`
+	IssueDate        time.Time  `json:"issue_date"`
+	EventID          *uuid.UUID `json:"event_id,omitempty"`
+	EventCode        string     `json:"event_code"`
+	EventTitle       string     `json:"event_title"`
+	TemplateName     string     `json:"template_name"`
+	PDFAvailable     bool       `json:"pdf_available"`
+	PDFVersion       *int       `json:"pdf_version,omitempty"`
+	DownloadURL      *string    `json:"download_url,omitempty"`
+}
+
+// MyEventListQuery represents query params for the events the caller takes part in
+type MyEventListQuery struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+	// IncludeAll also shows rejected and not yet generated certificates
+	IncludeAll bool `query:"include_all"`
+}
+
+// MyEventItem represents an event the caller is registered in
+type MyEventItem struct {
+	EventID            uuid.UUID       `json:"event_id"`
+	Code               string          `json:"code"`
+	Title              string          `json:"title"`
+	Location           string          `json:"location"`
+	Status             string          `json:"status"`
+	RegistrationStatus string          `json:"registration_status"`
+	AttendanceStatus   string          `json:"attendance_status"`
+	RegisteredAt       time.Time       `json:"registered_at"`
+	Document           *MyDocumentItem `json:"document,omitempty"`
+}
@@ -177,5 +177,38 @@ func (h *DocumentHandler) Delete(c fiber.Ctx) error {
 		return InternalErrorResponse(c, "Failed to delete document")
 	}
 
+	return NoContentResponse(c)
+}
+
+func (h *DocumentHandler) Restore(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid document ID format")
+	}
+
+	doc, err := h.service.Restore(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Document restored", doc)
+}
+
+func (h *DocumentHandler) Purge(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid document ID format")
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
 	return NoContentResponse(c)
 }
\ No newline at end of file
@@ -48,14 +48,14 @@ func (h *NotificationHandler) GetByUserID(c fiber.Ctx) error {
 	limit := fiber.Query(c, "limit", 10)
 	offset := fiber.Query(c, "offset", 0)
 
-	notifications, unreadCount, err := h.service.GetByUserID(ctx, userID, limit, offset)
+	notifications, total, err := h.service.GetByUserID(ctx, userID, limit, offset)
 	if err != nil {
 		return InternalErrorResponse(c, "Failed to fetch notifications")
 	}
 
 	return SuccessWithMeta(c, notifications, &Meta{
 		Limit: limit,
-		Total: unreadCount,
+		Total: total,
 	})
 }
 
@@ -124,5 +124,38 @@ func (h *UserDetailHandler) Delete(c fiber.Ctx) error {
 		return InternalErrorResponse(c, "Failed to delete user detail")
 	}
 
+	return NoContentResponse(c)
+}
+
+func (h *UserDetailHandler) Restore(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid user detail ID format")
+	}
+
+	detail, err := h.service.Restore(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "User detail restored", detail)
+}
+
+func (h *UserDetailHandler) Purge(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid user detail ID format")
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
 	return NoContentResponse(c)
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// FNAPIKeyHandler handles admin endpoints for integration API keys
+type FNAPIKeyHandler struct {
+	service service.FNAPIKeyService
+}
+
+// NewFNAPIKeyHandler creates a new FN API key handler
+func NewFNAPIKeyHandler(svc service.FNAPIKeyService) *FNAPIKeyHandler {
+	return &FNAPIKeyHandler{service: svc}
+}
+
+// Create creates an API key; the plain key is only returned in this response
+// POST /api/v1/fn/api-keys
+func (h *FNAPIKeyHandler) Create(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.APIKeyCreateRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	if req.Name == "" {
+		return BadRequestResponse(c, "VALIDATION_ERROR", "Name is required")
+	}
+	if len(req.Scopes) == 0 {
+		return BadRequestResponse(c, "VALIDATION_ERROR", "At least one scope is required")
+	}
+
+	result, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "API key created successfully", result)
+}
+
+// List lists all API keys without their secrets
+// GET /api/v1/fn/api-keys
+func (h *FNAPIKeyHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	result, err := h.service.List(ctx)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to list API keys")
+	}
+
+	return SuccessResponse(c, "API keys retrieved successfully", result)
+}
+
+// Revoke revokes an API key
+// DELETE /api/v1/fn/api-keys/:id
+func (h *FNAPIKeyHandler) Revoke(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid API key ID format")
+	}
+
+	if err := h.service.Revoke(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
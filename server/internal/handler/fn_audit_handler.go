@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// FNAuditHandler handles read endpoints for the audit trail
+type FNAuditHandler struct {
+	service service.FNAuditService
+}
+
+// NewFNAuditHandler creates a new FN audit handler
+func NewFNAuditHandler(svc service.FNAuditService) *FNAuditHandler {
+	return &FNAuditHandler{service: svc}
+}
+
+// List lists audit entries, newest first
+// GET /api/v1/audit?actor_id=&entity_type=&entity_id=&action=&outcome=&request_id=&from=&to=
+func (h *FNAuditHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	params := dto.AuditListQuery{
+		Page:     fiber.Query(c, "page", 1),
+		PageSize: fiber.Query(c, "page_size", 10),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	others := []MetaFNFilter{}
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			return BadRequestResponse(c, "INVALID_UUID", "Invalid actor ID format")
+		}
+		params.ActorID = &id
+		others = append(others, MetaFNFilter{Key: "actor_id", Value: actorID})
+	}
+
+	textFilters := []struct {
+		key    string
+		target **string
+	}{
+		{"entity_type", &params.EntityType},
+		{"entity_id", &params.EntityID},
+		{"action", &params.Action},
+		{"outcome", &params.Outcome},
+		{"request_id", &params.RequestID},
+	}
+	for _, f := range textFilters {
+		if value := c.Query(f.key); value != "" {
+			*f.target = &value
+			others = append(others, MetaFNFilter{Key: f.key, Value: value})
+		}
+	}
+
+	dateFilters := []struct {
+		key    string
+		target **time.Time
+	}{
+		{"from", &params.From},
+		{"to", &params.To},
+	}
+	for _, f := range dateFilters {
+		if value := c.Query(f.key); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return BadRequestResponse(c, "INVALID_DATE", "Invalid "+f.key+" date, expected RFC3339")
+			}
+			*f.target = &t
+			others = append(others, MetaFNFilter{Key: f.key, Value: value})
+		}
+	}
+
+	items, total, err := h.service.List(ctx, params)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to list audit entries")
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// Verify checks the hash chain of the whole audit trail
+// GET /api/v1/audit/verify
+func (h *FNAuditHandler) Verify(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	result, err := h.service.Verify(ctx)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to verify audit trail")
+	}
+
+	return SuccessResponse(c, "Audit trail verified", result)
+}
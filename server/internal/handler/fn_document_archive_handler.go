@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+type FNDocumentArchiveHandler struct {
+	service service.FNDocumentArchiveService
+}
+
+// NewFNDocumentArchiveHandler creates a new FN document archive handler
+func NewFNDocumentArchiveHandler(svc service.FNDocumentArchiveService) *FNDocumentArchiveHandler {
+	return &FNDocumentArchiveHandler{service: svc}
+}
+
+// Download streams a ZIP of the latest certificate PDFs of an event or pdf job
+// GET /api/v1/fn/documents/archive?event_id=uuid|pdf_job_id=uuid
+func (h *FNDocumentArchiveHandler) Download(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req dto.DocumentArchiveRequest
+	if err := c.Bind().Query(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_QUERY", "Invalid query parameters")
+	}
+
+	info, err := h.service.PrepareArchive(ctx, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Attachment(info.FileName)
+
+	// the body is written after the handler returns, so it must not depend on the request context
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		streamCtx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		result, err := h.service.WriteArchive(streamCtx, req, w)
+		if err != nil {
+			log.Error().Err(err).Str("file_name", info.FileName).Msg("error streaming certificate archive")
+		} else if result.FailedItems > 0 {
+			log.Warn().Int("failed", result.FailedItems).Int("total", result.TotalItems).Str("file_name", info.FileName).Msg("certificate archive streamed with missing files")
+		}
+		if err := w.Flush(); err != nil {
+			log.Warn().Err(err).Str("file_name", info.FileName).Msg("error flushing certificate archive")
+		}
+	})
+}
+
+// CreateJob queues an async ZIP archive for large events
+// POST /api/v1/fn/documents/archives
+func (h *FNDocumentArchiveHandler) CreateJob(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.DocumentArchiveRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.CreateJob(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(Response{
+		Status:  "success",
+		Message: "Archive job queued",
+		Data:    result,
+	})
+}
+
+// GetJob returns the status of one of the caller's archive jobs, with a download link once completed
+// GET /api/v1/fn/documents/archives/:id
+func (h *FNDocumentArchiveHandler) GetJob(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid archive job ID format")
+	}
+
+	result, err := h.service.GetJob(ctx, userID, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Archive job retrieved successfully", result)
+}
+
+// DownloadJob sends the ZIP produced by one of the caller's completed archive jobs
+// GET /api/v1/fn/documents/archives/:id/download
+func (h *FNDocumentArchiveHandler) DownloadJob(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid archive job ID format")
+	}
+
+	f, fileName, err := h.service.OpenJobFile(ctx, userID, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Attachment(fileName)
+
+	// fiber closes the reader once the body has been sent
+	return c.SendStream(f)
+}
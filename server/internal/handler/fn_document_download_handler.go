@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/middleware"
+	"server/internal/service"
+)
+
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+type FNDocumentDownloadHandler struct {
+	service service.FNDocumentDownloadService
+	authz   *middleware.Authorizer
+}
+
+// NewFNDocumentDownloadHandler creates a new FN document download handler
+func NewFNDocumentDownloadHandler(svc service.FNDocumentDownloadService, authz *middleware.Authorizer) *FNDocumentDownloadHandler {
+	return &FNDocumentDownloadHandler{service: svc, authz: authz}
+}
+
+// DownloadPDF streams the certificate PDF from file-svc, honoring single byte ranges
+// GET /api/v1/fn/documents/:id/pdf?version=2
+func (h *FNDocumentDownloadHandler) DownloadPDF(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+	claims, _ := c.Locals("user").(*middleware.KeycloakClaims)
+
+	documentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid document ID format")
+	}
+
+	req := dto.DocumentPDFDownloadRequest{
+		DocumentID: documentID,
+		Accessor: dto.DocumentAccessor{
+			UserID:  userID,
+			IsAdmin: h.authz.Can(claims, "documents.download_any"),
+		},
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+
+	if v := c.Query("version"); v != "" {
+		version, err := strconv.Atoi(v)
+		if err != nil || version < 1 {
+			return BadRequestResponse(c, "INVALID_VERSION", "version must be a positive integer")
+		}
+		req.Version = &version
+	}
+
+	rangeHeader := c.Get(fiber.HeaderRange)
+	if rangeHeader != "" {
+		req.RangeHeader = &rangeHeader
+	}
+
+	file, err := h.service.OpenPDF(ctx, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	c.Attachment(file.FileName)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	if rangeHeader == "" || file.Size <= 0 {
+		return c.SendStream(file.Body, int(file.Size))
+	}
+
+	start, end, err := parseByteRange(rangeHeader, file.Size)
+	if errors.Is(err, errRangeNotSatisfiable) {
+		file.Body.Close()
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", file.Size))
+		return ErrorResponse(c, fiber.StatusRequestedRangeNotSatisfiable, "RANGE_NOT_SATISFIABLE", "Requested range not satisfiable")
+	}
+	if err != nil {
+		// unsupported range syntax (e.g. multiple ranges): send the whole file
+		return c.SendStream(file.Body, int(file.Size))
+	}
+
+	// file-svc serves whole files only, so skip up to the range start here
+	if _, err := io.CopyN(io.Discard, file.Body, start); err != nil {
+		file.Body.Close()
+		return InternalErrorResponse(c, "error reading pdf")
+	}
+
+	length := end - start + 1
+	c.Status(fiber.StatusPartialContent)
+	c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, file.Size))
+
+	return c.SendStream(struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file.Body, length), file.Body}, int(length))
+}
+
+// parseByteRange parses a single "bytes=start-end" range against the file size
+func parseByteRange(header string, size int64) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, fmt.Errorf("unsupported range: %s", header)
+	}
+
+	startStr, endStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid range: %s", header)
+	}
+	startStr, endStr = strings.TrimSpace(startStr), strings.TrimSpace(endStr)
+
+	var start, end int64
+	switch {
+	case startStr == "" && endStr == "":
+		return 0, 0, fmt.Errorf("invalid range: %s", header)
+	case startStr == "":
+		// suffix range: last N bytes
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, errRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		start, end = size-n, size-1
+	default:
+		var err error
+		start, err = strconv.ParseInt(startStr, 10, 64)
+		if err != nil || start < 0 {
+			return 0, 0, fmt.Errorf("invalid range: %s", header)
+		}
+		end = size - 1
+		if endStr != "" {
+			end, err = strconv.ParseInt(endStr, 10, 64)
+			if err != nil || end < start {
+				return 0, 0, fmt.Errorf("invalid range: %s", header)
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+	}
+
+	if start >= size {
+		return 0, 0, errRangeNotSatisfiable
+	}
+	return start, end, nil
+}
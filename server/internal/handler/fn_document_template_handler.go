@@ -251,6 +251,41 @@ func (h *FNDocumentTemplateHandler) Delete(c fiber.Ctx) error {
 	return NoContentResponse(c)
 }
 
+// Restore restores a deleted document template
+// PATCH /api/v1/fn/document-templates/:id/restore
+func (h *FNDocumentTemplateHandler) Restore(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid template ID format")
+	}
+
+	template, err := h.service.Restore(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Template restored successfully", template)
+}
+
+// Purge permanently removes a deleted document template; blocked once documents were issued
+// DELETE /api/v1/fn/document-templates/:id/purge
+func (h *FNDocumentTemplateHandler) Purge(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid template ID format")
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
+
 // Helper functions
 
 func getUserIDFromContext(c fiber.Ctx) (uuid.UUID, error) {
@@ -276,6 +311,10 @@ func handleServiceError(c fiber.Ctx, err error) error {
 		return NotFoundResponse(c, errMsg)
 	case contains(errMsg, "already exists"):
 		return ErrorResponse(c, fiber.StatusConflict, "CONFLICT", errMsg)
+	case contains(errMsg, "blocked"):
+		return ErrorResponse(c, fiber.StatusConflict, "CONFLICT", errMsg)
+	case contains(errMsg, "access denied"):
+		return ForbiddenResponse(c, errMsg)
 	case contains(errMsg, "invalid"), contains(errMsg, "required"):
 		return BadRequestResponse(c, "VALIDATION_ERROR", errMsg)
 	default:
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/middleware"
+	"server/internal/service"
+)
+
+// FNEvaluationHandler handles evaluation authoring, attempts, the review queue and reports
+type FNEvaluationHandler struct {
+	service service.FNEvaluationService
+	reports service.FNEvaluationReportService
+	authz   *middleware.Authorizer
+}
+
+// NewFNEvaluationHandler creates a new FN evaluation handler
+func NewFNEvaluationHandler(svc service.FNEvaluationService, reports service.FNEvaluationReportService, authz *middleware.Authorizer) *FNEvaluationHandler {
+	return &FNEvaluationHandler{service: svc, reports: reports, authz: authz}
+}
+
+// Create creates an evaluation with its questions; it starts as pending unless a status is given
+// POST /api/v1/fn/evaluations
+func (h *FNEvaluationHandler) Create(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.EvaluationCreateRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Evaluation created successfully", result)
+}
+
+// GetByID returns an evaluation with its questions and answer key
+// GET /api/v1/fn/evaluations/:id
+func (h *FNEvaluationHandler) GetByID(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	result, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Evaluation retrieved successfully", result)
+}
+
+// Update updates the settings of an evaluation (status, passing score, time limit, attempts)
+// PATCH /api/v1/fn/evaluations/:id
+func (h *FNEvaluationHandler) Update(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	var req dto.EvaluationUpdateRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Update(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Evaluation updated successfully", result)
+}
+
+// ReplaceQuestions replaces all questions of an evaluation that has no attempts yet
+// PUT /api/v1/fn/evaluations/:id/questions
+func (h *FNEvaluationHandler) ReplaceQuestions(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	var req dto.EvaluationQuestionsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.ReplaceQuestions(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Evaluation questions updated successfully", result)
+}
+
+// ReplaceDrawRules makes each attempt draw its own questions from the bank; an empty
+// list turns the evaluation back to fixed questions
+// PUT /api/v1/fn/evaluations/:id/draw-rules
+func (h *FNEvaluationHandler) ReplaceDrawRules(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	var req dto.EvaluationDrawRulesRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.ReplaceDrawRules(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Evaluation draw rules updated successfully", result)
+}
+
+// ListAttempts lists all attempts of an evaluation, newest first
+// GET /api/v1/fn/evaluations/:id/attempts
+func (h *FNEvaluationHandler) ListAttempts(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	result, err := h.service.ListAttempts(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Attempts retrieved successfully", result)
+}
+
+// Start starts an attempt for the caller, or resumes the one in progress.
+// The questions are returned without the answer key.
+// POST /api/v1/fn/evaluations/:id/start
+func (h *FNEvaluationHandler) Start(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation ID format")
+	}
+
+	result, err := h.service.Start(ctx, id, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Attempt started successfully", result)
+}
+
+// GetAttempt returns one of the caller's attempts: questions while in progress, results after
+// GET /api/v1/fn/evaluation-attempts/:id
+func (h *FNEvaluationHandler) GetAttempt(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid attempt ID format")
+	}
+
+	result, err := h.service.GetAttempt(ctx, id, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Attempt retrieved successfully", result)
+}
+
+// Submit submits the caller's answers; objective questions are graded right away
+// and essays are queued for review
+// POST /api/v1/fn/evaluation-attempts/:id/submit
+func (h *FNEvaluationHandler) Submit(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid attempt ID format")
+	}
+
+	var req dto.AttemptSubmitRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Submit(ctx, id, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Attempt submitted successfully", result)
+}
+
+// ListReviewQueue lists the answers waiting for manual review, oldest first
+// GET /api/v1/fn/evaluation-reviews?evaluation_id=&page=1&page_size=10
+func (h *FNEvaluationHandler) ListReviewQueue(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	params := dto.ReviewQueueQuery{
+		Page:     fiber.Query(c, "page", 1),
+		PageSize: fiber.Query(c, "page_size", 10),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	others := []MetaFNFilter{}
+	if value := c.Query("evaluation_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return BadRequestResponse(c, "INVALID_UUID", "Invalid evaluation_id format")
+		}
+		params.EvaluationID = &id
+		others = append(others, MetaFNFilter{Key: "evaluation_id", Value: value})
+	}
+
+	items, total, err := h.service.ListReviewQueue(ctx, params)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to list pending reviews")
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// Review grades a pending answer (APPROVED, PARTIAL or REJECTED)
+// POST /api/v1/fn/evaluation-reviews/:scoreId
+func (h *FNEvaluationHandler) Review(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	scoreID, err := uuid.Parse(c.Params("scoreId"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid score ID format")
+	}
+
+	var req dto.ScoreReviewRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+	if req.Verdict == "" {
+		return BadRequestResponse(c, "VALIDATION_ERROR", "Verdict is required")
+	}
+
+	result, err := h.service.Review(ctx, scoreID, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Review saved successfully", result)
+}
+
+// RegenerateReport rebuilds the Markdown report of a graded attempt; pdf=true also
+// renders it to PDF through pdf-svc
+// POST /api/v1/fn/evaluation-attempts/:id/report?pdf=true
+func (h *FNEvaluationHandler) RegenerateReport(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid attempt ID format")
+	}
+
+	result, err := h.reports.Regenerate(ctx, id, c.Query("pdf") == "true")
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Report generated successfully", result)
+}
+
+// DownloadReport downloads the report of an attempt as Markdown (default) or PDF.
+// Takers get their own reports; callers with evaluations.read get any.
+// GET /api/v1/fn/evaluation-attempts/:id/report?format=md|pdf
+func (h *FNEvaluationHandler) DownloadReport(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid attempt ID format")
+	}
+
+	claims, _ := c.Locals("user").(*middleware.KeycloakClaims)
+	staff := h.authz.Can(claims, "evaluations.read")
+
+	file, err := h.reports.Open(ctx, id, userID, staff, c.Query("format", dto.ReportFormatMarkdown))
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	c.Attachment(file.FileName)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	return c.SendStream(file.Body, int(file.Size))
+}
\ No newline at end of file
@@ -196,5 +196,40 @@ func (h *FNEventHandler) Delete(c fiber.Ctx) error {
 		return handleServiceError(c, err)
 	}
 
+	return NoContentResponse(c)
+}
+
+// Restore restores a deleted event
+// PATCH /api/v1/fn/events/:id/restore
+func (h *FNEventHandler) Restore(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	event, err := h.service.Restore(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Event restored successfully", event)
+}
+
+// Purge permanently removes a deleted event; blocked once documents were issued
+// DELETE /api/v1/fn/events/:id/purge
+func (h *FNEventHandler) Purge(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
 	return NoContentResponse(c)
 }
\ No newline at end of file
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+type FNEventParticipantHandler struct {
+	service service.FNEventParticipantService
+}
+
+// NewFNEventParticipantHandler creates a new FN event participant handler
+func NewFNEventParticipantHandler(svc service.FNEventParticipantService) *FNEventParticipantHandler {
+	return &FNEventParticipantHandler{service: svc}
+}
+
+// List retrieves the participants of an event with filters and pagination
+// GET /api/v1/fn/events/:id/participants?page=1&page_size=10&q=search&registration_status=REGISTERED&attendance_status=PENDING
+func (h *FNEventParticipantHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	params, others := participantListQuery(c)
+
+	items, total, err := h.service.List(ctx, eventID, params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	meta := pageMeta(total, params.Page, params.PageSize, others)
+	if params.SearchQuery != nil {
+		meta.SearchQuery = *params.SearchQuery
+	}
+
+	return SuccessWithMetaFN(c, items, meta)
+}
+
+// Progress reports each participant's study material completion, evaluation result and
+// the stage blocking their certificate
+// GET /api/v1/fn/events/:id/participants/progress?page=1&page_size=10&q=search&registration_status=REGISTERED&attendance_status=PENDING
+func (h *FNEventParticipantHandler) Progress(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	params, others := participantListQuery(c)
+
+	items, total, err := h.service.Progress(ctx, eventID, params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	meta := pageMeta(total, params.Page, params.PageSize, others)
+	if params.SearchQuery != nil {
+		meta.SearchQuery = *params.SearchQuery
+	}
+
+	return SuccessWithMetaFN(c, items, meta)
+}
+
+// Add adds one or more participants to an event
+// POST /api/v1/fn/events/:id/participants
+func (h *FNEventParticipantHandler) Add(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	var req dto.EventParticipantAddRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	if len(req.Participants) == 0 {
+		return BadRequestResponse(c, "VALIDATION_ERROR", "At least one participant is required")
+	}
+
+	result, err := h.service.Add(ctx, eventID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Participants processed successfully", result)
+}
+
+// Patch modifies a participant of an event
+// PATCH /api/v1/fn/events/:id/participants/:participantId
+func (h *FNEventParticipantHandler) Patch(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	participantID, err := uuid.Parse(c.Params("participantId"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid participant ID format")
+	}
+
+	var req dto.EventParticipantPatchRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Patch(ctx, eventID, participantID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Participant updated successfully", result)
+}
+
+// Remove removes a participant from an event
+// DELETE /api/v1/fn/events/:id/participants/:participantId?force=true&reason_code=WITHDRAWN
+func (h *FNEventParticipantHandler) Remove(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	participantID, err := uuid.Parse(c.Params("participantId"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid participant ID format")
+	}
+
+	var params dto.EventParticipantRemoveQuery
+	if err := c.Bind().Query(&params); err != nil {
+		return BadRequestResponse(c, "INVALID_QUERY", "Invalid query parameters")
+	}
+
+	if err := h.service.Remove(ctx, eventID, participantID, userID, params); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
+
+// BulkUpdateStatus changes the registration/attendance status of several participants
+// PATCH /api/v1/fn/events/:id/participants/status
+func (h *FNEventParticipantHandler) BulkUpdateStatus(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	var req dto.EventParticipantBulkStatusRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	if len(req.ParticipantIDs) == 0 {
+		return BadRequestResponse(c, "VALIDATION_ERROR", "At least one participant ID is required")
+	}
+
+	result, err := h.service.BulkUpdateStatus(ctx, eventID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Participants status updated successfully", result)
+}
+
+// participantListQuery reads the paging and filters shared by List and Progress,
+// with the filters echoed back in the response metadata
+func participantListQuery(c fiber.Ctx) (dto.EventParticipantListQuery, []MetaFNFilter) {
+	params := dto.EventParticipantListQuery{
+		Page:     fiber.Query(c, "page", 1),
+		PageSize: fiber.Query(c, "page_size", 10),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	if q := c.Query("q"); q != "" {
+		params.SearchQuery = &q
+	}
+
+	others := []MetaFNFilter{}
+	if registrationStatus := c.Query("registration_status"); registrationStatus != "" {
+		params.RegistrationStatus = &registrationStatus
+		others = append(others, MetaFNFilter{Key: "registration_status", Value: registrationStatus})
+	}
+	if attendanceStatus := c.Query("attendance_status"); attendanceStatus != "" {
+		params.AttendanceStatus = &attendanceStatus
+		others = append(others, MetaFNFilter{Key: "attendance_status", Value: attendanceStatus})
+	}
+
+	return params, others
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// exportTimeout bounds how long a single export may keep streaming
+const exportTimeout = 10 * time.Minute
+
+type FNExportHandler struct {
+	service service.FNExportService
+}
+
+// NewFNExportHandler creates a new FN export handler
+func NewFNExportHandler(svc service.FNExportService) *FNExportHandler {
+	return &FNExportHandler{service: svc}
+}
+
+// ExportEventRegister streams the roster or certificate register of an event
+// GET /api/v1/fn/events/:id/export?format=csv|xlsx|pdf&scope=register|roster&q=search&template_id=uuid&status=PDF.COMPLETED
+func (h *FNExportHandler) ExportEventRegister(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid event ID format")
+	}
+
+	query := dto.DocumentRegisterExportQuery{
+		Format: c.Query("format", "csv"),
+		Scope:  c.Query("scope", dto.RegisterScopeRegister),
+	}
+
+	if q := c.Query("q"); q != "" {
+		query.Filters.SearchQuery = &q
+	}
+	if templateID := c.Query("template_id"); templateID != "" {
+		query.Filters.TemplateID = &templateID
+	}
+	if status := c.Query("status"); status != "" {
+		query.Filters.Status = &status
+	}
+
+	info, err := h.service.PrepareEventRegister(ctx, eventID, query)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, info.ContentType)
+	c.Attachment(info.FileName)
+
+	// the body is written after the handler returns, so it must not depend on the request context
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		streamCtx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		if err := h.service.WriteEventRegister(streamCtx, eventID, query, info, w); err != nil {
+			log.Error().Err(err).Str("event_id", eventID.String()).Str("format", query.Format).Msg("error streaming event register export")
+		}
+		if err := w.Flush(); err != nil {
+			log.Warn().Err(err).Str("event_id", eventID.String()).Msg("error flushing event register export")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"server/internal/dto"
+	"server/internal/middleware"
+	"server/internal/service"
+)
+
+type FNMeHandler struct {
+	service   service.FNUserService
+	meService service.FNMeService
+	authz     *middleware.Authorizer
+}
+
+// NewFNMeHandler creates a new FN me handler
+func NewFNMeHandler(svc service.FNUserService, meSvc service.FNMeService, authz *middleware.Authorizer) *FNMeHandler {
+	return &FNMeHandler{service: svc, meService: meSvc, authz: authz}
+}
+
+// Get returns the caller's local account with roles and linked beneficiary record
+// GET /api/v1/me
+func (h *FNMeHandler) Get(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	result, err := h.service.GetMe(ctx, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	if claims := middleware.GetUserClaims(c); claims != nil {
+		result.Username = claims.PreferredUsername
+		result.Roles = h.authz.Roles(claims)
+	}
+	if result.Roles == nil {
+		result.Roles = []string{}
+	}
+
+	return SuccessResponse(c, "Current user retrieved successfully", result)
+}
+
+// ListDocuments lists the caller's certificates across all events. The beneficiary is
+// matched by the national_id claim only, never by the self-chosen username.
+// GET /api/v1/me/documents?include_all=true&event_id=uuid
+func (h *FNMeHandler) ListDocuments(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	claims := middleware.GetUserClaims(c)
+	if claims == nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	params := dto.MyDocumentListQuery{
+		Page:       fiber.Query(c, "page", 1),
+		PageSize:   fiber.Query(c, "page_size", 10),
+		IncludeAll: fiber.Query(c, "include_all", false),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	if eventID := c.Query("event_id"); eventID != "" {
+		params.EventID = &eventID
+	}
+
+	items, total, err := h.meService.ListDocuments(ctx, claims.NationalID, params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	others := []MetaFNFilter{{Key: "include_all", Value: params.IncludeAll}}
+	if params.EventID != nil {
+		others = append(others, MetaFNFilter{Key: "event_id", Value: *params.EventID})
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// ListEvents lists the events the caller is registered in, with their certificate
+// GET /api/v1/me/events?include_all=true
+func (h *FNMeHandler) ListEvents(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	claims := middleware.GetUserClaims(c)
+	if claims == nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	params := dto.MyEventListQuery{
+		Page:       fiber.Query(c, "page", 1),
+		PageSize:   fiber.Query(c, "page_size", 10),
+		IncludeAll: fiber.Query(c, "include_all", false),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	items, total, err := h.meService.ListEvents(ctx, claims.NationalID, params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	others := []MetaFNFilter{{Key: "include_all", Value: params.IncludeAll}}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// normalizePage clamps pagination params to the limits used by the repositories
+func normalizePage(page, pageSize *int) {
+	if *page < 1 {
+		*page = 1
+	}
+	if *pageSize <= 0 {
+		*pageSize = 10
+	}
+	if *pageSize > 100 {
+		*pageSize = 100
+	}
+}
+
+// pageMeta builds the FN pagination metadata
+func pageMeta(total int64, page, pageSize int, others []MetaFNFilter) *MetaFN {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &MetaFN{
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+		HasPrevPage: page > 1,
+		HasNextPage: page < totalPages,
+		Others:      others,
+	}
+}
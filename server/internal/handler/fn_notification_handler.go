@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// FNNotificationHandler handles notification delivery tracking and preference endpoints
+type FNNotificationHandler struct {
+	service service.FNNotificationService
+}
+
+// NewFNNotificationHandler creates a new FN notification handler
+func NewFNNotificationHandler(svc service.FNNotificationService) *FNNotificationHandler {
+	return &FNNotificationHandler{service: svc}
+}
+
+// ListDeliveries lists notification deliveries, newest first
+// GET /api/v1/fn/notification-deliveries?status=&channel=&template_key=&user_detail_id=&user_id=&notification_id=
+func (h *FNNotificationHandler) ListDeliveries(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	params := dto.NotificationDeliveryListQuery{
+		Page:     fiber.Query(c, "page", 1),
+		PageSize: fiber.Query(c, "page_size", 10),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	others := []MetaFNFilter{}
+
+	textFilters := []struct {
+		key    string
+		target **string
+	}{
+		{"status", &params.Status},
+		{"channel", &params.Channel},
+		{"template_key", &params.TemplateKey},
+	}
+	for _, f := range textFilters {
+		if value := c.Query(f.key); value != "" {
+			*f.target = &value
+			others = append(others, MetaFNFilter{Key: f.key, Value: value})
+		}
+	}
+
+	idFilters := []struct {
+		key    string
+		target **uuid.UUID
+	}{
+		{"user_detail_id", &params.UserDetailID},
+		{"user_id", &params.UserID},
+		{"notification_id", &params.NotificationID},
+	}
+	for _, f := range idFilters {
+		if value := c.Query(f.key); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return BadRequestResponse(c, "INVALID_UUID", "Invalid "+f.key+" format")
+			}
+			*f.target = &id
+			others = append(others, MetaFNFilter{Key: f.key, Value: value})
+		}
+	}
+
+	items, total, err := h.service.ListDeliveries(ctx, params)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to list notification deliveries")
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// RetryDelivery puts a failed or skipped email delivery back in the queue
+// POST /api/v1/fn/notification-deliveries/:id/retry
+func (h *FNNotificationHandler) RetryDelivery(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid delivery ID format")
+	}
+
+	delivery, err := h.service.RetryDelivery(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Delivery queued for retry", delivery)
+}
+
+// GetPreferences returns the caller's notification preferences for every type and channel
+// GET /api/v1/me/notification-preferences
+func (h *FNNotificationHandler) GetPreferences(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	prefs, err := h.service.GetPreferences(ctx, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Notification preferences retrieved successfully", prefs)
+}
+
+// UpdatePreferences sets the delivery mode (IMMEDIATE, HOURLY, DAILY, NONE) per type and channel
+// PUT /api/v1/me/notification-preferences
+func (h *FNNotificationHandler) UpdatePreferences(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	prefs, err := h.service.UpdatePreferences(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Notification preferences updated successfully", prefs)
+}
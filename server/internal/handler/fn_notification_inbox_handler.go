@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+const (
+	// streamHeartbeat keeps proxies from closing an idle stream and detects gone clients
+	streamHeartbeat = 25 * time.Second
+	// streamRetryMillis is the reconnect delay suggested to EventSource clients
+	streamRetryMillis = 3000
+)
+
+// FNNotificationInboxHandler handles the caller's own in-app notifications (/me/notifications)
+type FNNotificationInboxHandler struct {
+	service service.FNNotificationInboxService
+}
+
+// NewFNNotificationInboxHandler creates a new FN notification inbox handler
+func NewFNNotificationInboxHandler(svc service.FNNotificationInboxService) *FNNotificationInboxHandler {
+	return &FNNotificationInboxHandler{service: svc}
+}
+
+// List lists the caller's notifications, newest first, with cursor pagination
+// GET /api/v1/me/notifications?cursor=&limit=20&type=document.generated,digest&unread_only=true
+func (h *FNNotificationInboxHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	params := dto.MyNotificationListQuery{
+		Cursor:     c.Query("cursor"),
+		Limit:      fiber.Query(c, "limit", 20),
+		UnreadOnly: fiber.Query(c, "unread_only", false),
+	}
+	if types := c.Query("type"); types != "" {
+		params.Types = []string{types}
+	}
+
+	result, err := h.service.List(ctx, userID, params)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Notifications retrieved successfully", result)
+}
+
+// UnreadCount returns the caller's unread notification count
+// GET /api/v1/me/notifications/unread-count
+func (h *FNNotificationInboxHandler) UnreadCount(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	count, err := h.service.UnreadCount(ctx, userID)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to count unread notifications")
+	}
+
+	return SuccessResponse(c, "Unread count retrieved", dto.UnreadCountResponse{UnreadCount: count})
+}
+
+// MarkRead marks the given notifications of the caller as read; foreign IDs are ignored
+// POST /api/v1/me/notifications/read
+func (h *FNNotificationInboxHandler) MarkRead(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.MarkNotificationsReadRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.MarkRead(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Notifications marked as read", result)
+}
+
+// MarkAllRead marks every notification of the caller as read
+// POST /api/v1/me/notifications/read-all
+func (h *FNNotificationInboxHandler) MarkAllRead(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	result, err := h.service.MarkAllRead(ctx, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "All notifications marked as read", result)
+}
+
+// DeleteOlderThan deletes the caller's notifications created before a point in time,
+// given as an RFC3339 timestamp (older_than) or a number of days (older_than_days)
+// DELETE /api/v1/me/notifications?older_than=2025-01-01T00:00:00Z
+// DELETE /api/v1/me/notifications?older_than_days=30
+func (h *FNNotificationInboxHandler) DeleteOlderThan(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var before time.Time
+	switch {
+	case c.Query("older_than") != "":
+		before, err = time.Parse(time.RFC3339, c.Query("older_than"))
+		if err != nil {
+			return BadRequestResponse(c, "INVALID_DATE", "older_than must be an RFC3339 timestamp")
+		}
+	case c.Query("older_than_days") != "":
+		days := fiber.Query(c, "older_than_days", 0)
+		if days < 1 {
+			return BadRequestResponse(c, "VALIDATION_ERROR", "older_than_days must be a positive number")
+		}
+		before = time.Now().AddDate(0, 0, -days)
+	default:
+		return BadRequestResponse(c, "VALIDATION_ERROR", "older_than or older_than_days is required")
+	}
+
+	result, err := h.service.DeleteOlderThan(ctx, userID, before)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Notifications deleted", result)
+}
+
+// Stream pushes the caller's new notifications and unread count as Server-Sent Events.
+// Each notification event carries its ID as the SSE id; on reconnect the client sends it
+// back (Last-Event-ID header or ?since=) and the notifications it missed are replayed first.
+// Browsers' EventSource cannot set headers, so the token may also go in ?access_token=.
+// GET /api/v1/me/notifications/stream?since=<notification_id|RFC3339>
+func (h *FNNotificationInboxHandler) Stream(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	cursor := c.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("since")
+	}
+
+	// subscribe before replaying so nothing created in between is lost
+	events, cancel := h.service.Subscribe(userID)
+
+	missed, err := h.service.Replay(ctx, userID, cursor)
+	if err != nil {
+		cancel()
+		return handleServiceError(c, err)
+	}
+	unread, err := h.service.UnreadCount(ctx, userID)
+	if err != nil {
+		cancel()
+		return InternalErrorResponse(c, "Failed to count unread notifications")
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		replayed := make(map[uuid.UUID]bool, len(missed))
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+		for i := range missed {
+			writeStreamEvent(w, missed[i].ID.String(), dto.NotificationStreamEventNotification, missed[i])
+			replayed[missed[i].ID] = true
+		}
+		writeStreamEvent(w, "", dto.NotificationStreamEventUnreadCount, dto.UnreadCountResponse{UnreadCount: unread})
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case m, ok := <-events:
+				// hub stopped or the client fell behind: it reconnects and replays
+				if !ok {
+					return
+				}
+				if m.Notification != nil && !replayed[m.Notification.ID] {
+					writeStreamEvent(w, m.Notification.ID.String(), dto.NotificationStreamEventNotification, m.Notification)
+				}
+				writeStreamEvent(w, "", dto.NotificationStreamEventUnreadCount, dto.UnreadCountResponse{UnreadCount: m.UnreadCount})
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			if err := w.Flush(); err != nil {
+				log.Debug().Err(err).Str("user_id", userID.String()).Msg("notification stream closed")
+				return
+			}
+		}
+	})
+}
+
+// writeStreamEvent writes one SSE event; events without id keep the client's last cursor
+func writeStreamEvent(w *bufio.Writer, id, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("event", event).Msg("error marshaling stream event")
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
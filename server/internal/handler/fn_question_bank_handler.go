@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// FNQuestionBankHandler handles the reusable question bank and its item statistics
+type FNQuestionBankHandler struct {
+	service service.FNQuestionBankService
+}
+
+// NewFNQuestionBankHandler creates a new FN question bank handler
+func NewFNQuestionBankHandler(svc service.FNQuestionBankService) *FNQuestionBankHandler {
+	return &FNQuestionBankHandler{service: svc}
+}
+
+// Create adds a question to the bank; difficulty defaults to MEDIUM
+// POST /api/v1/fn/question-bank
+func (h *FNQuestionBankHandler) Create(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.BankQuestionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Bank question created successfully", result)
+}
+
+// GetByID returns a bank question with its answer key
+// GET /api/v1/fn/question-bank/:id
+func (h *FNQuestionBankHandler) GetByID(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid question ID format")
+	}
+
+	result, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Bank question retrieved successfully", result)
+}
+
+// List lists bank questions with filters and pagination
+// GET /api/v1/fn/question-bank?page=1&page_size=10&q=search&topic=&difficulty=&question_type=&is_active=true
+func (h *FNQuestionBankHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	params, others := bankQuestionListQuery(c)
+
+	items, total, err := h.service.List(ctx, params)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to list bank questions")
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// ItemStats returns the success rate and discrimination of bank questions, computed
+// from graded attempts; it takes the same filters as List
+// GET /api/v1/fn/question-bank/stats?page=1&page_size=10&topic=&difficulty=
+func (h *FNQuestionBankHandler) ItemStats(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	params, others := bankQuestionListQuery(c)
+
+	items, total, err := h.service.ItemStats(ctx, params)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to compute item statistics")
+	}
+
+	return SuccessWithMetaFN(c, items, pageMeta(total, params.Page, params.PageSize, others))
+}
+
+// Update replaces a bank question and its options
+// PUT /api/v1/fn/question-bank/:id
+func (h *FNQuestionBankHandler) Update(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid question ID format")
+	}
+
+	var req dto.BankQuestionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.Update(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Bank question updated successfully", result)
+}
+
+// Enable makes a bank question available to draws again
+// PATCH /api/v1/fn/question-bank/:id/enable
+func (h *FNQuestionBankHandler) Enable(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid question ID format")
+	}
+
+	if err := h.service.Enable(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Bank question enabled successfully", nil)
+}
+
+// Disable withdraws a bank question from future draws
+// PATCH /api/v1/fn/question-bank/:id/disable
+func (h *FNQuestionBankHandler) Disable(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid question ID format")
+	}
+
+	if err := h.service.Disable(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Bank question disabled successfully", nil)
+}
+
+// bankQuestionListQuery reads the list filters shared by List and ItemStats
+func bankQuestionListQuery(c fiber.Ctx) (dto.BankQuestionListQuery, []MetaFNFilter) {
+	params := dto.BankQuestionListQuery{
+		Page:     fiber.Query(c, "page", 1),
+		PageSize: fiber.Query(c, "page_size", 10),
+	}
+	normalizePage(&params.Page, &params.PageSize)
+
+	others := []MetaFNFilter{}
+	if q := c.Query("q"); q != "" {
+		params.SearchQuery = &q
+		others = append(others, MetaFNFilter{Key: "q", Value: q})
+	}
+	if topic := c.Query("topic"); topic != "" {
+		params.Topic = &topic
+		others = append(others, MetaFNFilter{Key: "topic", Value: topic})
+	}
+	if difficulty := c.Query("difficulty"); difficulty != "" {
+		difficulty = strings.ToUpper(difficulty)
+		params.Difficulty = &difficulty
+		others = append(others, MetaFNFilter{Key: "difficulty", Value: difficulty})
+	}
+	if questionType := c.Query("question_type"); questionType != "" {
+		questionType = strings.ToUpper(questionType)
+		params.QuestionType = &questionType
+		others = append(others, MetaFNFilter{Key: "question_type", Value: questionType})
+	}
+	if value := c.Query("is_active"); value != "" {
+		isActive := value == "true"
+		params.IsActive = &isActive
+		others = append(others, MetaFNFilter{Key: "is_active", Value: isActive})
+	}
+	return params, others
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"server/internal/service"
+)
+
+// FNRevocationHandler handles public certificate status endpoints
+type FNRevocationHandler struct {
+	service service.FNRevocationService
+}
+
+// NewFNRevocationHandler creates a new FN revocation handler
+func NewFNRevocationHandler(svc service.FNRevocationService) *FNRevocationHandler {
+	return &FNRevocationHandler{service: svc}
+}
+
+// List downloads the signed revocation list for offline checks
+// GET /public/revocation-list
+func (h *FNRevocationHandler) List(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	list, nextUpdate, err := h.service.SignedList(ctx)
+	if err != nil {
+		return InternalErrorResponse(c, "Failed to build revocation list")
+	}
+
+	maxAge := int(time.Until(nextUpdate).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", maxAge))
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="revocation-list.json"`)
+
+	return c.JSON(list)
+}
+
+// Keys publishes the public keys that sign the revocation list
+// GET /public/revocation-list/keys
+func (h *FNRevocationHandler) Keys(c fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.JSON(h.service.Keys())
+}
+
+// Verify returns the public status of a certificate
+// GET /public/documents/verify/:verificationCode
+func (h *FNRevocationHandler) Verify(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	verificationCode := c.Params("verificationCode")
+	if verificationCode == "" {
+		return BadRequestResponse(c, "MISSING_VERIFICATION_CODE", "Verification code is required")
+	}
+
+	result, err := h.service.Verify(ctx, verificationCode)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Document status retrieved", result)
+}
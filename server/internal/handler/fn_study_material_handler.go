@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// FNStudyMaterialHandler handles the learning path of study materials: outline and
+// progress for learners, personal annotations, and section management for authors
+type FNStudyMaterialHandler struct {
+	service service.FNStudyMaterialService
+}
+
+// NewFNStudyMaterialHandler creates a new FN study material handler
+func NewFNStudyMaterialHandler(svc service.FNStudyMaterialService) *FNStudyMaterialHandler {
+	return &FNStudyMaterialHandler{service: svc}
+}
+
+// GetOutline returns the sections and subsections in order with the caller's completion state
+// GET /api/v1/fn/study-materials/:id/outline
+func (h *FNStudyMaterialHandler) GetOutline(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid study material ID format")
+	}
+
+	result, err := h.service.GetOutline(ctx, id, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Study material outline retrieved successfully", result)
+}
+
+// GetProgress returns the caller's completion percentage of a study material
+// GET /api/v1/fn/study-materials/:id/progress
+func (h *FNStudyMaterialHandler) GetProgress(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid study material ID format")
+	}
+
+	result, err := h.service.GetProgress(ctx, id, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Study progress retrieved successfully", result)
+}
+
+// SetProgress marks a subsection complete or incomplete for the caller
+// PUT /api/v1/fn/study-subsections/:id/progress
+func (h *FNStudyMaterialHandler) SetProgress(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid subsection ID format")
+	}
+
+	var req dto.StudyProgressRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.SetSubsectionProgress(ctx, id, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Study progress updated successfully", result)
+}
+
+// ListAnnotations lists the caller's annotations on a subsection, oldest first
+// GET /api/v1/fn/study-subsections/:id/annotations
+func (h *FNStudyMaterialHandler) ListAnnotations(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid subsection ID format")
+	}
+
+	result, err := h.service.ListAnnotations(ctx, id, userID)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Annotations retrieved successfully", result)
+}
+
+// CreateAnnotation adds a personal annotation to a subsection
+// POST /api/v1/fn/study-subsections/:id/annotations
+func (h *FNStudyMaterialHandler) CreateAnnotation(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid subsection ID format")
+	}
+
+	var req dto.StudyAnnotationRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.CreateAnnotation(ctx, id, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Annotation created successfully", result)
+}
+
+// UpdateAnnotation replaces the content of one of the caller's annotations
+// PUT /api/v1/fn/study-annotations/:id
+func (h *FNStudyMaterialHandler) UpdateAnnotation(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid annotation ID format")
+	}
+
+	var req dto.StudyAnnotationRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.UpdateAnnotation(ctx, id, userID, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Annotation updated successfully", result)
+}
+
+// DeleteAnnotation deletes one of the caller's annotations
+// DELETE /api/v1/fn/study-annotations/:id
+func (h *FNStudyMaterialHandler) DeleteAnnotation(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid annotation ID format")
+	}
+
+	if err := h.service.DeleteAnnotation(ctx, id, userID); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
+
+// CreateSection adds a section to a study material; without order_index it goes last
+// POST /api/v1/fn/study-materials/:id/sections
+func (h *FNStudyMaterialHandler) CreateSection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid study material ID format")
+	}
+
+	var req dto.StudySectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.CreateSection(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Section created successfully", result)
+}
+
+// ReorderSections sets the order of all sections of a study material
+// PUT /api/v1/fn/study-materials/:id/sections/order
+func (h *FNStudyMaterialHandler) ReorderSections(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid study material ID format")
+	}
+
+	var req dto.StudyReorderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	if err := h.service.ReorderSections(ctx, id, req); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Sections reordered successfully", nil)
+}
+
+// UpdateSection updates the title, description and order of a section
+// PUT /api/v1/fn/study-sections/:id
+func (h *FNStudyMaterialHandler) UpdateSection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid section ID format")
+	}
+
+	var req dto.StudySectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.UpdateSection(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Section updated successfully", result)
+}
+
+// DeleteSection deletes a section with its subsections, progress and annotations
+// DELETE /api/v1/fn/study-sections/:id
+func (h *FNStudyMaterialHandler) DeleteSection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid section ID format")
+	}
+
+	if err := h.service.DeleteSection(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
+
+// CreateSubsection adds a subsection to a section; without order_index it goes last
+// POST /api/v1/fn/study-sections/:id/subsections
+func (h *FNStudyMaterialHandler) CreateSubsection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid section ID format")
+	}
+
+	var req dto.StudySubsectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.CreateSubsection(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return CreatedResponse(c, "Subsection created successfully", result)
+}
+
+// ReorderSubsections sets the order of all subsections of a section
+// PUT /api/v1/fn/study-sections/:id/subsections/order
+func (h *FNStudyMaterialHandler) ReorderSubsections(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid section ID format")
+	}
+
+	var req dto.StudyReorderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	if err := h.service.ReorderSubsections(ctx, id, req); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Subsections reordered successfully", nil)
+}
+
+// UpdateSubsection updates the content and order of a subsection
+// PUT /api/v1/fn/study-subsections/:id
+func (h *FNStudyMaterialHandler) UpdateSubsection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid subsection ID format")
+	}
+
+	var req dto.StudySubsectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return BadRequestResponse(c, "INVALID_BODY", "Invalid request body")
+	}
+
+	result, err := h.service.UpdateSubsection(ctx, id, req)
+	if err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return SuccessResponse(c, "Subsection updated successfully", result)
+}
+
+// DeleteSubsection deletes a subsection with its resources, progress and annotations
+// DELETE /api/v1/fn/study-subsections/:id
+func (h *FNStudyMaterialHandler) DeleteSubsection(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, "INVALID_UUID", "Invalid subsection ID format")
+	}
+
+	if err := h.service.DeleteSubsection(ctx, id); err != nil {
+		return handleServiceError(c, err)
+	}
+
+	return NoContentResponse(c)
+}
\ No newline at end of file
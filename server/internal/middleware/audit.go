@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/requestid"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+)
+
+// AuditRecorder guarda las entradas de auditoría y lee el estado de las entidades
+type AuditRecorder interface {
+	Snapshot(ctx context.Context, entityType, entityID string) (map[string]interface{}, error)
+	Record(ctx context.Context, entry dto.AuditEntry) error
+}
+
+// auditResources segmento de ruta -> tipo de entidad auditada
+var auditResources = map[string]string{
+	"users":               "user",
+	"user-details":        "user_detail",
+	"api-keys":            "api_key",
+	"document-types":      "document_type",
+	"document-categories": "document_category",
+	"document-templates":  "document_template",
+	"documents":           "document",
+	"actions":             "document",
+	"archives":            "document_archive",
+	"events":              "event",
+	"participants":        "event_participant",
+	"event-participants":  "event_participant",
+	"notifications":       "notification",
+	"evaluations":         "evaluation",
+	"study-materials":     "study_material",
+	"study-sections":      "study_section",
+	"study-subsections":   "study_subsection",
+}
+
+// auditActions acción por defecto según el método HTTP
+var auditActions = map[string]string{
+	fiber.MethodPost:   "CREATE",
+	fiber.MethodPut:    "UPDATE",
+	fiber.MethodPatch:  "UPDATE",
+	fiber.MethodDelete: "DELETE",
+}
+
+// Audit middleware que registra cada petición que modifica datos: actor, ruta,
+// entidad, estado antes/después, request ID, IP y resultado. Los errores al
+// registrar solo se loguean; nunca cambian la respuesta.
+func Audit(recorder AuditRecorder) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		action, ok := auditActions[c.Method()]
+		if !ok {
+			return c.Next()
+		}
+
+		target := resolveAuditTarget(c.Path())
+		if target.verb != "" {
+			action = target.verb
+		}
+		if bodyAction := auditBodyAction(c.Body()); bodyAction != "" {
+			action = bodyAction
+		}
+
+		ctx := c.Context()
+
+		var before map[string]interface{}
+		if target.entityID != "" && c.Method() != fiber.MethodPost {
+			snapshot, err := recorder.Snapshot(ctx, target.entityType, target.entityID)
+			if err != nil {
+				log.Warn().Err(err).Str("entity_type", target.entityType).Str("entity_id", target.entityID).Msg("Audit snapshot failed")
+			}
+			before = snapshot
+		}
+
+		handlerErr := c.Next()
+
+		status := c.Response().StatusCode()
+		if handlerErr != nil {
+			// el ErrorHandler todavía no escribió la respuesta
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(handlerErr, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		entityID := target.entityID
+		if entityID == "" && status < 400 && c.Method() == fiber.MethodPost {
+			entityID = auditResponseID(c.Response().Body())
+		}
+
+		var after map[string]interface{}
+		if entityID != "" && status < 400 {
+			snapshot, err := recorder.Snapshot(ctx, target.entityType, entityID)
+			if err != nil {
+				log.Warn().Err(err).Str("entity_type", target.entityType).Str("entity_id", entityID).Msg("Audit snapshot failed")
+			}
+			after = snapshot
+		}
+
+		entry := dto.AuditEntry{
+			Method:     c.Method(),
+			Route:      c.Route().Path,
+			Path:       c.Path(),
+			EntityType: target.entityType,
+			EntityID:   entityID,
+			Action:     action,
+			Before:     before,
+			After:      after,
+			RequestID:  requestid.FromContext(c),
+			IPAddress:  c.IP(),
+			StatusCode: status,
+		}
+		if claims, ok := c.Locals("user").(*KeycloakClaims); ok {
+			entry.ActorUsername = claims.PreferredUsername
+			entry.ActorType = claims.Principal
+		}
+		if userID, ok := c.Locals("user_id").(string); ok {
+			if id, err := uuid.Parse(userID); err == nil {
+				entry.ActorID = &id
+			}
+		}
+
+		if err := recorder.Record(ctx, entry); err != nil {
+			log.Error().Err(err).Str("request_id", entry.RequestID).Msg("Audit record failed")
+		}
+
+		return handlerErr
+	}
+}
+
+type auditTarget struct {
+	entityType string
+	entityID   string
+	verb       string
+}
+
+// resolveAuditTarget obtiene la entidad desde la ruta: el último recurso conocido,
+// el ID que lo sigue y, si la ruta termina en un verbo después del ID
+// (/:id/enable, /:id/read), ese verbo como acción.
+func resolveAuditTarget(path string) auditTarget {
+	var target auditTarget
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		entityType, ok := auditResources[segment]
+		if !ok {
+			continue
+		}
+
+		target = auditTarget{entityType: entityType}
+		if i+1 < len(segments) && isAuditID(segments[i+1]) {
+			target.entityID = segments[i+1]
+			if i+2 == len(segments)-1 {
+				target.verb = strings.ToUpper(strings.ReplaceAll(segments[i+2], "-", "_"))
+			}
+		}
+	}
+	return target
+}
+
+func isAuditID(segment string) bool {
+	if _, err := uuid.Parse(segment); err == nil {
+		return true
+	}
+	_, err := strconv.ParseUint(segment, 10, 64)
+	return err == nil
+}
+
+// auditBodyAction lee el campo "action" de los endpoints que despachan por cuerpo
+// (POST /fn/documents/actions)
+func auditBodyAction(body []byte) string {
+	if len(body) == 0 || body[0] != '{' {
+		return ""
+	}
+
+	var payload struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(payload.Action))
+}
+
+// auditResponseID toma data.id de la respuesta de una creación
+func auditResponseID(body []byte) string {
+	var payload struct {
+		Data struct {
+			ID interface{} `json:"id"`
+		} `json:"data"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || payload.Data.ID == nil {
+		return ""
+	}
+	return fmt.Sprint(payload.Data.ID)
+}
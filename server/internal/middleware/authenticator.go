@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+)
+
+// Tipos de principal autenticado
+const (
+	PrincipalUser    = "user"
+	PrincipalService = "service"
+	PrincipalAPIKey  = "api_key"
+)
+
+// serviceAccountPrefix prefijo de preferred_username en tokens client-credentials de Keycloak
+const serviceAccountPrefix = "service-account-"
+
+// APIKeyHeader cabecera con la API key (también se acepta "Authorization: ApiKey <key>")
+const APIKeyHeader = "X-API-Key"
+
+// ErrNoCredentials indica que la petición no trae credenciales para ese autenticador
+var ErrNoCredentials = errors.New("no credentials")
+
+// AuthError error de autenticación con el código y estado HTTP a responder
+type AuthError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *AuthError) Error() string { return e.Message }
+
+// Authenticator resuelve las credenciales de la petición a claims. Devuelve
+// ErrNoCredentials cuando la petición no trae credenciales de su tipo.
+type Authenticator interface {
+	Authenticate(c fiber.Ctx) (*KeycloakClaims, error)
+}
+
+// AuthenticatorFunc adapta una función a Authenticator
+type AuthenticatorFunc func(c fiber.Ctx) (*KeycloakClaims, error)
+
+func (f AuthenticatorFunc) Authenticate(c fiber.Ctx) (*KeycloakClaims, error) { return f(c) }
+
+// Authenticate middleware que prueba los autenticadores en orden. El primero que
+// encuentra credenciales decide; todos dejan los claims en c.Locals("user").
+func Authenticate(authenticators ...Authenticator) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		for _, auth := range authenticators {
+			claims, err := auth.Authenticate(c)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				var authErr *AuthError
+				if !errors.As(err, &authErr) {
+					authErr = &AuthError{Status: fiber.StatusUnauthorized, Code: "INVALID_CREDENTIALS", Message: err.Error()}
+				}
+				return c.Status(authErr.Status).JSON(fiber.Map{
+					"status": "error",
+					"error": fiber.Map{
+						"code":    authErr.Code,
+						"message": authErr.Message,
+					},
+				})
+			}
+
+			// Guardar claims en el contexto
+			c.Locals("user", claims)
+			c.Locals("user_id", claims.Subject)
+			c.Locals("email", claims.Email)
+			c.Locals("username", claims.PreferredUsername)
+
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"error": fiber.Map{
+				"code":    "MISSING_TOKEN",
+				"message": "Authorization token or API key required",
+			},
+		})
+	}
+}
+
+// Authenticator valida tokens Bearer de Keycloak, tanto de usuarios como
+// de cuentas de servicio (client credentials)
+func (km *KeycloakMiddleware) Authenticator() Authenticator {
+	return AuthenticatorFunc(func(c fiber.Ctx) (*KeycloakClaims, error) {
+		scheme, token, ok := strings.Cut(c.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "bearer") {
+			return nil, ErrNoCredentials
+		}
+
+		if km == nil {
+			log.Error().Msg("Keycloak middleware not initialized")
+			return nil, &AuthError{Status: fiber.StatusInternalServerError, Code: "AUTH_NOT_CONFIGURED", Message: "Authentication service not configured"}
+		}
+
+		claims, err := km.ValidateToken(strings.TrimSpace(token))
+		if err != nil {
+			log.Debug().Err(err).Msg("Token validation failed")
+			return nil, &AuthError{Status: fiber.StatusUnauthorized, Code: "INVALID_TOKEN", Message: "Invalid token: " + err.Error()}
+		}
+
+		claims.Principal = PrincipalUser
+		if strings.HasPrefix(claims.PreferredUsername, serviceAccountPrefix) {
+			claims.Principal = PrincipalService
+		}
+		return claims, nil
+	})
+}
+
+// APIKeyVerifier valida una API key en claro
+type APIKeyVerifier interface {
+	Authenticate(ctx context.Context, rawKey string) (*dto.APIKeyPrincipal, error)
+}
+
+// APIKeyAuthenticator valida API keys de integraciones. El sujeto es el usuario que
+// creó la key, de modo que el trabajo se le atribuye.
+func APIKeyAuthenticator(verifier APIKeyVerifier) Authenticator {
+	return AuthenticatorFunc(func(c fiber.Ctx) (*KeycloakClaims, error) {
+		rawKey := strings.TrimSpace(c.Get(APIKeyHeader))
+		if rawKey == "" {
+			scheme, value, ok := strings.Cut(c.Get("Authorization"), " ")
+			if !ok || !strings.EqualFold(scheme, "apikey") {
+				return nil, ErrNoCredentials
+			}
+			rawKey = strings.TrimSpace(value)
+		}
+
+		principal, err := verifier.Authenticate(c.Context(), rawKey)
+		if err != nil {
+			log.Debug().Err(err).Msg("API key validation failed")
+			return nil, &AuthError{Status: fiber.StatusUnauthorized, Code: "INVALID_API_KEY", Message: "Invalid or expired API key"}
+		}
+
+		c.Locals("api_key_id", principal.ID.String())
+
+		claims := &KeycloakClaims{
+			PreferredUsername: "api-key:" + principal.Name,
+			Name:              principal.Name,
+			OrgUnitPath:       principal.OrgUnitPath,
+			Principal:         PrincipalAPIKey,
+			Scopes:            principal.Scopes,
+			OwnerRoles:        principal.OwnerRoles,
+		}
+		claims.Subject = principal.OwnerID.String()
+		return claims, nil
+	})
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// RoleAuthenticated matches any caller with a valid token
+const RoleAuthenticated = "authenticated"
+
+// PermissionAPIKeysManage gestiona las API keys; nunca se otorga a una API key
+const PermissionAPIKeysManage = "api_keys.manage"
+
+//go:embed permissions.yml
+var defaultPermissions []byte
+
+// AuthorizerConfig configuración de la matriz de permisos
+type AuthorizerConfig struct {
+	// PermissionsFile reemplaza la matriz embebida cuando no está vacío
+	PermissionsFile string
+	// ClientID agrega los roles de cliente (resource_access) de este cliente
+	ClientID string
+}
+
+// PermissionMatrix asigna a cada permiso los roles que lo tienen
+type PermissionMatrix struct {
+	SuperRoles  []string            `yaml:"super_roles"`
+	Permissions map[string][]string `yaml:"permissions"`
+}
+
+// Authorizer aplica la matriz de permisos a las rutas
+type Authorizer struct {
+	clientID   string
+	superRoles map[string]bool
+	grants     map[string]map[string]bool
+}
+
+// NewAuthorizer carga la matriz de permisos (embebida o desde archivo)
+func NewAuthorizer(cfg AuthorizerConfig) (*Authorizer, error) {
+	data := defaultPermissions
+	source := "embedded"
+
+	if cfg.PermissionsFile != "" {
+		fileData, err := os.ReadFile(cfg.PermissionsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading permissions file: %w", err)
+		}
+		data = fileData
+		source = cfg.PermissionsFile
+	}
+
+	var matrix PermissionMatrix
+	if err := yaml.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("error parsing permissions file: %w", err)
+	}
+	if len(matrix.Permissions) == 0 {
+		return nil, fmt.Errorf("permissions file defines no permissions")
+	}
+
+	a := &Authorizer{
+		clientID:   cfg.ClientID,
+		superRoles: toSet(matrix.SuperRoles),
+		grants:     make(map[string]map[string]bool, len(matrix.Permissions)),
+	}
+	for perm, roles := range matrix.Permissions {
+		a.grants[perm] = toSet(roles)
+	}
+
+	log.Info().Str("source", source).Int("permissions", len(a.grants)).Msg("Permission matrix loaded")
+	return a, nil
+}
+
+// Require middleware que exige un permiso de la matriz
+func (a *Authorizer) Require(permission string) fiber.Handler {
+	if _, ok := a.grants[permission]; !ok {
+		log.Error().Str("permission", permission).Msg("route uses a permission missing from the matrix, access will be denied")
+	}
+
+	return func(c fiber.Ctx) error {
+		return a.check(c, permission)
+	}
+}
+
+// RequireByBodyField middleware que elige el permiso según un campo del cuerpo JSON,
+// p. ej. la acción de /fn/documents/actions. Usa fallback para valores no mapeados.
+func (a *Authorizer) RequireByBodyField(field string, permissions map[string]string, fallback string) fiber.Handler {
+	for _, perm := range permissions {
+		if _, ok := a.grants[perm]; !ok {
+			log.Error().Str("permission", perm).Msg("route uses a permission missing from the matrix, access will be denied")
+		}
+	}
+
+	return func(c fiber.Ctx) error {
+		var body map[string]interface{}
+		_ = json.Unmarshal(c.Body(), &body)
+
+		permission := fallback
+		if value, ok := body[field].(string); ok {
+			if perm, found := permissions[value]; found {
+				permission = perm
+			}
+		}
+
+		return a.check(c, permission)
+	}
+}
+
+// Can indica si los roles del llamante otorgan el permiso
+func (a *Authorizer) Can(claims *KeycloakClaims, permission string) bool {
+	allowed, ok := a.grants[permission]
+	if !ok || claims == nil {
+		return false
+	}
+
+	// las API keys solo tienen los permisos de sus scopes que su creador aún tiene
+	if claims.Principal == PrincipalAPIKey {
+		if permission == PermissionAPIKeysManage || !contains(claims.Scopes, permission) {
+			return false
+		}
+		return a.rolesGrant(claims.OwnerRoles, allowed)
+	}
+
+	return a.rolesGrant(a.Roles(claims), allowed)
+}
+
+// APIKeyScopes devuelve los permisos que se pueden otorgar a una API key
+func (a *Authorizer) APIKeyScopes() []string {
+	perms := a.Permissions()
+	scopes := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		if perm != PermissionAPIKeysManage {
+			scopes = append(scopes, perm)
+		}
+	}
+	return scopes
+}
+
+// IsSuper indica si alguno de los roles es un super rol
+func (a *Authorizer) IsSuper(roles []string) bool {
+	for _, role := range roles {
+		if a.superRoles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Authorizer) rolesGrant(roles []string, allowed map[string]bool) bool {
+	if allowed[RoleAuthenticated] {
+		return true
+	}
+	for _, role := range roles {
+		if a.superRoles[role] || allowed[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions devuelve los permisos definidos en la matriz
+func (a *Authorizer) Permissions() []string {
+	perms := make([]string, 0, len(a.grants))
+	for perm := range a.grants {
+		perms = append(perms, perm)
+	}
+	sort.Strings(perms)
+	return perms
+}
+
+// Roles devuelve los roles del realm y, si hay ClientID, los del cliente
+func (a *Authorizer) Roles(claims *KeycloakClaims) []string {
+	roles := claims.RealmRoles()
+
+	if a.clientID != "" && claims.ResourceAccess != nil {
+		if access, ok := claims.ResourceAccess[a.clientID]; ok {
+			if raw, ok := access["roles"].([]interface{}); ok {
+				for _, role := range raw {
+					if roleStr, ok := role.(string); ok {
+						roles = append(roles, roleStr)
+					}
+				}
+			}
+		}
+	}
+
+	return roles
+}
+
+func (a *Authorizer) check(c fiber.Ctx, permission string) error {
+	claims, ok := c.Locals("user").(*KeycloakClaims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status": "error",
+			"error": fiber.Map{
+				"code":    "UNAUTHENTICATED",
+				"message": "User not authenticated",
+			},
+		})
+	}
+
+	if a.Can(claims, permission) {
+		return c.Next()
+	}
+
+	log.Warn().
+		Str("user_id", claims.Subject).
+		Str("username", claims.PreferredUsername).
+		Strs("roles", a.Roles(claims)).
+		Str("permission", permission).
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("Permission denied")
+
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status": "error",
+		"error": fiber.Map{
+			"code":    "FORBIDDEN",
+			"message": fmt.Sprintf("Required permission: %s", permission),
+		},
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
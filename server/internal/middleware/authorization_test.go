@@ -0,0 +1,117 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"server/internal/middleware"
+)
+
+func newAuthorizer(t *testing.T) *middleware.Authorizer {
+	t.Helper()
+	authz, err := middleware.NewAuthorizer(middleware.AuthorizerConfig{})
+	if err != nil {
+		t.Fatalf("NewAuthorizer: %v", err)
+	}
+	return authz
+}
+
+func apiKeyClaims(ownerRoles []string, scopes ...string) *middleware.KeycloakClaims {
+	return &middleware.KeycloakClaims{
+		Principal:  middleware.PrincipalAPIKey,
+		Scopes:     scopes,
+		OwnerRoles: ownerRoles,
+	}
+}
+
+func TestAPIKeyPermissions(t *testing.T) {
+	authz := newAuthorizer(t)
+
+	cases := []struct {
+		name       string
+		claims     *middleware.KeycloakClaims
+		permission string
+		want       bool
+	}{
+		{"scope held by the owner", apiKeyClaims([]string{"issuer"}, "catalog.write"), "catalog.write", true},
+		{"scope outside the key", apiKeyClaims([]string{"admin"}, "catalog.read"), "catalog.write", false},
+		{"owner demoted after creating the key", apiKeyClaims([]string{"beneficiary"}, "catalog.write", "records.purge"), "records.purge", false},
+		{"admin owner keeps a scoped purge", apiKeyClaims([]string{"admin"}, "records.purge"), "records.purge", true},
+		{"owner without roles on an authenticated permission", apiKeyClaims(nil, "documents.verify"), "documents.verify", true},
+		{"wildcard is not a scope", apiKeyClaims([]string{"admin"}, "*"), "catalog.write", false},
+		{"keys never manage keys", apiKeyClaims([]string{"admin"}, middleware.PermissionAPIKeysManage), middleware.PermissionAPIKeysManage, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := authz.Can(tc.claims, tc.permission); got != tc.want {
+				t.Fatalf("Can(%s) = %v, want %v", tc.permission, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyScopesExcludeKeyManagement(t *testing.T) {
+	authz := newAuthorizer(t)
+
+	scopes := authz.APIKeyScopes()
+	if len(scopes) != len(authz.Permissions())-1 {
+		t.Fatalf("got %d scopes for %d permissions", len(scopes), len(authz.Permissions()))
+	}
+	for _, scope := range scopes {
+		if scope == middleware.PermissionAPIKeysManage || scope == "*" {
+			t.Fatalf("%s offered as an API key scope", scope)
+		}
+	}
+}
+
+func TestAPIKeyOrgScope(t *testing.T) {
+	authz := newAuthorizer(t)
+
+	if scope := authz.OrgScopeFor(apiKeyClaims([]string{"admin"})); !scope.Bypass {
+		t.Fatalf("unit-less key of an admin owner: got %+v, want unrestricted", scope)
+	}
+
+	scope := authz.OrgScopeFor(apiKeyClaims([]string{"issuer"}))
+	if scope.Bypass || len(scope.Paths) != 0 {
+		t.Fatalf("unit-less key of a demoted owner: got %+v, want no units", scope)
+	}
+
+	unit := apiKeyClaims([]string{"issuer"})
+	unit.OrgUnitPath = "/gra/ggr"
+	if scope := authz.OrgScopeFor(unit); scope.Bypass || !scope.Allows("/gra/ggr/sgdi") {
+		t.Fatalf("key bound to a unit: got %+v", scope)
+	}
+}
+
+func TestUserOrgScope(t *testing.T) {
+	authz := newAuthorizer(t)
+	user := func(unit string, groups []string, roles ...string) *middleware.KeycloakClaims {
+		realmRoles := make([]interface{}, 0, len(roles))
+		for _, r := range roles {
+			realmRoles = append(realmRoles, r)
+		}
+		return &middleware.KeycloakClaims{
+			RealmAccess: map[string]interface{}{"roles": realmRoles},
+			OrgUnitPath: unit,
+			Groups:      groups,
+		}
+	}
+
+	if scope := authz.OrgScopeFor(user("/gra/ggr", nil, "admin")); !scope.Bypass {
+		t.Fatalf("admin: got %+v, want unrestricted", scope)
+	}
+
+	scope := authz.OrgScopeFor(user("/gra/ggr", []string{"/gra/gdis"}, "issuer"))
+	if scope.Bypass || !scope.Allows("/gra/ggr/sgti") || scope.Allows("/gra/gdis") {
+		t.Fatalf("unit claim must win over groups: got %+v", scope)
+	}
+
+	scope = authz.OrgScopeFor(user("", []string{"/gra/gdis", "/gra/ggr"}, "issuer"))
+	if scope.Bypass || !scope.Allows("/gra/gdis/x") || !scope.Allows("/gra/ggr") {
+		t.Fatalf("groups as units: got %+v", scope)
+	}
+
+	if scope := authz.OrgScopeFor(user("", nil, "issuer")); scope.Bypass || len(scope.Paths) != 0 {
+		t.Fatalf("no unit and no groups: got %+v, want no units", scope)
+	}
+}
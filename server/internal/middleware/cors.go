@@ -20,7 +20,7 @@ type CORSConfig struct {
 var DefaultCORSConfig = CORSConfig{
 	AllowOrigins:     "*",
 	AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-	AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID",
+	AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID,Last-Event-ID",
 	AllowCredentials: false,
 	ExposeHeaders:    "Content-Length,Content-Type",
 	MaxAge:           86400, // 24 hours
@@ -0,0 +1,155 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+
+	"server/internal/middleware"
+	"server/internal/middleware/keycloaktest"
+)
+
+const testIssuer = "https://sso.test/realms/test"
+
+func newValidator(t *testing.T, iss *keycloaktest.Issuer) *middleware.KeycloakMiddleware {
+	t.Helper()
+	km, err := middleware.NewKeycloakMiddleware(iss.Config())
+	if err != nil {
+		t.Fatalf("NewKeycloakMiddleware: %v", err)
+	}
+	return km
+}
+
+func issue(t *testing.T, iss *keycloaktest.Issuer, claims *middleware.KeycloakClaims) string {
+	t.Helper()
+	token, err := iss.Token(claims)
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	return token
+}
+
+func TestValidateTokenAcrossKeyRotation(t *testing.T) {
+	iss, err := keycloaktest.NewIssuer(testIssuer)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	km := newValidator(t, iss)
+
+	before := issue(t, iss, keycloaktest.Claims("user-1", "issuer"))
+	claims, err := km.ValidateToken(before)
+	if err != nil {
+		t.Fatalf("token from the initial key rejected: %v", err)
+	}
+	if claims.Subject != "user-1" || !claims.HasRealmRole("issuer") {
+		t.Fatalf("unexpected claims: sub=%q roles=%v", claims.Subject, claims.RealmRoles())
+	}
+
+	if err := iss.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// the new kid is unknown to the cached key set and must trigger a reload
+	after := issue(t, iss, keycloaktest.Claims("user-2"))
+	if _, err := km.ValidateToken(after); err != nil {
+		t.Fatalf("token from the rotated key rejected: %v", err)
+	}
+
+	// the previous key is still published, so its tokens stay valid
+	if _, err := km.ValidateToken(before); err != nil {
+		t.Fatalf("token from the previous key rejected after rotation: %v", err)
+	}
+}
+
+func TestValidateTokenRejectsForeignKeys(t *testing.T) {
+	trusted, err := keycloaktest.NewIssuer(testIssuer)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	km := newValidator(t, trusted)
+
+	// same issuer URL and kid, different key: the signature must not verify
+	impostor, err := keycloaktest.NewIssuer(testIssuer)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	if _, err := km.ValidateToken(issue(t, impostor, keycloaktest.Claims("user-1"))); err == nil {
+		t.Fatal("token signed by an unpublished key with a known kid was accepted")
+	}
+
+	// a kid the trusted issuer never published
+	if err := impostor.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	_, err = km.ValidateToken(issue(t, impostor, keycloaktest.Claims("user-1")))
+	if err == nil || !strings.Contains(err.Error(), "public key not found") {
+		t.Fatalf("unknown kid: got %v, want a missing public key error", err)
+	}
+}
+
+func TestValidateTokenChecksIssuerAndExpiry(t *testing.T) {
+	iss, err := keycloaktest.NewIssuer(testIssuer)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	km := newValidator(t, iss)
+
+	wrongIssuer := keycloaktest.Claims("user-1")
+	wrongIssuer.Issuer = "https://sso.test/realms/other"
+	if _, err := km.ValidateToken(issue(t, iss, wrongIssuer)); err == nil {
+		t.Fatal("token from another realm was accepted")
+	}
+
+	expired := keycloaktest.Claims("user-1")
+	expired.IssuedAt = jwt.NewNumericDate(time.Now().Add(-2 * time.Hour))
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	if _, err := km.ValidateToken(issue(t, iss, expired)); err == nil {
+		t.Fatal("expired token was accepted")
+	}
+}
+
+func TestAuthMiddlewareWithTestIssuer(t *testing.T) {
+	iss, err := keycloaktest.NewIssuer(testIssuer)
+	if err != nil {
+		t.Fatalf("NewIssuer: %v", err)
+	}
+	km := newValidator(t, iss)
+
+	app := fiber.New()
+	// fiber v3 runs the route middleware before the handler, as in the routers
+	app.Get("/whoami", func(c fiber.Ctx) error {
+		return c.SendString(middleware.GetUserID(c))
+	}, km.Auth())
+
+	cases := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"valid token", "Bearer " + issue(t, iss, keycloaktest.Claims("user-1")), fiber.StatusOK},
+		{"missing token", "", fiber.StatusUnauthorized},
+		{"garbage token", "Bearer not-a-jwt", fiber.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"server/internal/domain/orgunit"
+)
+
+// OrgScope middleware que limita las consultas a las unidades orgánicas del usuario.
+// La unidad se toma del claim org_unit_path o, si falta, de las rutas de grupo del token.
+// Los super roles (admin) no tienen restricción.
+func (a *Authorizer) OrgScope() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		claims, ok := c.Locals("user").(*KeycloakClaims)
+		if !ok {
+			return c.Next()
+		}
+
+		c.SetContext(orgunit.WithScope(c.Context(), a.OrgScopeFor(claims)))
+		return c.Next()
+	}
+}
+
+// OrgScopeFor calcula el alcance organizacional de los claims
+func (a *Authorizer) OrgScopeFor(claims *KeycloakClaims) orgunit.Scope {
+	// una API key sin unidad es de alcance global mientras su creador sea super rol
+	if claims.Principal == PrincipalAPIKey {
+		if claims.OrgUnitPath != "" {
+			return orgunit.NewScope(claims.OrgUnitPath)
+		}
+		if a.IsSuper(claims.OwnerRoles) {
+			return orgunit.Unrestricted
+		}
+		return orgunit.NewScope()
+	}
+
+	if a.IsSuper(a.Roles(claims)) {
+		return orgunit.Unrestricted
+	}
+
+	if claims.OrgUnitPath != "" {
+		return orgunit.NewScope(claims.OrgUnitPath)
+	}
+	return orgunit.NewScope(claims.Groups...)
+}
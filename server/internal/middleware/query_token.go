@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// BearerFromQuery copia el token del parámetro de query al header Authorization
+// cuando la petición no trae uno. Solo para rutas de streaming (EventSource no
+// permite headers); el token queda en la URL, así que no usarlo en el resto de la API.
+func BearerFromQuery(param string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			if token := strings.TrimSpace(c.Query(param)); token != "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		return c.Next()
+	}
+}
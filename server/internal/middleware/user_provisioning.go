@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+
+	"server/internal/dto"
+)
+
+// userCacheTTL tiempo que se reutiliza el usuario local antes de sincronizar de nuevo los claims
+const userCacheTTL = 10 * time.Minute
+
+// UserProvisioner crea o actualiza el usuario local de un sujeto de Keycloak
+type UserProvisioner interface {
+	Provision(ctx context.Context, req dto.UserProvisionRequest) (uuid.UUID, error)
+}
+
+type provisionedUser struct {
+	id      uuid.UUID
+	expires time.Time
+}
+
+// provisionCache cache de usuarios aprovisionados; las entradas vencidas se barren
+// como mucho una vez por TTL, así el mapa no crece con cada sujeto que pasó alguna vez
+type provisionCache struct {
+	entries   sync.Map
+	mu        sync.Mutex
+	nextSweep time.Time
+}
+
+func (pc *provisionCache) load(subject string, now time.Time) (uuid.UUID, bool) {
+	entry, ok := pc.entries.Load(subject)
+	if !ok || !now.Before(entry.(provisionedUser).expires) {
+		return uuid.Nil, false
+	}
+	return entry.(provisionedUser).id, true
+}
+
+func (pc *provisionCache) store(subject string, id uuid.UUID, now time.Time) {
+	pc.entries.Store(subject, provisionedUser{id: id, expires: now.Add(userCacheTTL)})
+
+	pc.mu.Lock()
+	sweep := !now.Before(pc.nextSweep)
+	if sweep {
+		pc.nextSweep = now.Add(userCacheTTL)
+	}
+	pc.mu.Unlock()
+	if !sweep {
+		return
+	}
+
+	pc.entries.Range(func(key, value interface{}) bool {
+		if !now.Before(value.(provisionedUser).expires) {
+			pc.entries.Delete(key)
+		}
+		return true
+	})
+}
+
+// UserProvisioning middleware que asegura un registro en users para el token (JIT).
+// Reemplaza user_id (sub) por el ID local; el sub queda en keycloak_sub. Guarda también
+// los roles del token, que acotan las API keys del usuario.
+func UserProvisioning(p UserProvisioner, authz *Authorizer) fiber.Handler {
+	var (
+		cache provisionCache
+		group singleflight.Group
+	)
+
+	return func(c fiber.Ctx) error {
+		claims, ok := c.Locals("user").(*KeycloakClaims)
+		if !ok || claims.Principal == PrincipalAPIKey {
+			// las API keys ya traen como sujeto el ID local de su creador
+			return c.Next()
+		}
+
+		if id, ok := cache.load(claims.Subject, time.Now()); ok {
+			setLocalUser(c, claims, id)
+			return c.Next()
+		}
+
+		// sólo el claim national_id identifica a la persona: el nombre de usuario lo elige ella
+		req := dto.UserProvisionRequest{
+			Subject:       claims.Subject,
+			Email:         claims.Email,
+			EmailVerified: claims.EmailVerified,
+			NationalID:    claims.NationalID,
+			FirstName:     claims.GivenName,
+			LastName:      claims.FamilyName,
+		}
+		if claims.Principal == PrincipalService {
+			req = serviceAccountIdentity(claims)
+		}
+		req.Roles = authz.Roles(claims)
+
+		// el resultado se comparte con las peticiones concurrentes del mismo sujeto:
+		// que la primera se cancele no debe hacer fallar a las demás
+		ctx := context.WithoutCancel(c.Context())
+		result, err, _ := group.Do(claims.Subject, func() (interface{}, error) {
+			return p.Provision(ctx, req)
+		})
+		if err != nil {
+			log.Error().Err(err).Str("keycloak_sub", claims.Subject).Msg("User provisioning failed")
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status": "error",
+				"error": fiber.Map{
+					"code":    "USER_PROVISIONING_FAILED",
+					"message": "Could not link the token to a local user",
+				},
+			})
+		}
+
+		id := result.(uuid.UUID)
+		cache.store(claims.Subject, id, time.Now())
+		setLocalUser(c, claims, id)
+		return c.Next()
+	}
+}
+
+func setLocalUser(c fiber.Ctx, claims *KeycloakClaims, id uuid.UUID) {
+	c.Locals("user_id", id.String())
+	c.Locals("keycloak_sub", claims.Subject)
+}
+
+// serviceAccountIdentity arma una identidad local estable para una cuenta de servicio,
+// que no trae email ni DNI en el token
+func serviceAccountIdentity(claims *KeycloakClaims) dto.UserProvisionRequest {
+	sum := sha256.Sum256([]byte(claims.Subject))
+	return dto.UserProvisionRequest{
+		Subject:    claims.Subject,
+		Email:      claims.PreferredUsername + "@service-accounts.local",
+		NationalID: "SA" + hex.EncodeToString(sum[:])[:18],
+		FirstName:  strings.TrimPrefix(claims.PreferredUsername, serviceAccountPrefix),
+		LastName:   "service account",
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+)
+
+type detachedProvisioner struct {
+	id        uuid.UUID
+	cancelled bool
+}
+
+func (p *detachedProvisioner) Provision(ctx context.Context, _ dto.UserProvisionRequest) (uuid.UUID, error) {
+	// un contexto que no puede cancelarse no tiene canal Done
+	p.cancelled = ctx.Done() != nil
+	return p.id, nil
+}
+
+func TestUserProvisioningDetachesSharedContext(t *testing.T) {
+	authz, err := NewAuthorizer(AuthorizerConfig{})
+	if err != nil {
+		t.Fatalf("NewAuthorizer: %v", err)
+	}
+	p := &detachedProvisioner{id: uuid.New()}
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		claims := &KeycloakClaims{Email: "ana@example.com"}
+		claims.Subject = "sub-1"
+		c.Locals("user", claims)
+		return c.Next()
+	})
+	app.Use(UserProvisioning(p, authz))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(c.Locals("user_id").(string))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if p.cancelled {
+		t.Fatal("provisioning shared by concurrent requests ran on the first caller's cancellable context")
+	}
+}
+
+func TestProvisionCacheSweepsExpiredEntries(t *testing.T) {
+	var cache provisionCache
+	start := time.Now()
+
+	cache.store("sub-1", uuid.New(), start)
+	cache.store("sub-2", uuid.New(), start.Add(time.Minute))
+	if _, ok := cache.load("sub-1", start.Add(userCacheTTL)); ok {
+		t.Fatal("expired entry served")
+	}
+
+	// el siguiente alta pasado un TTL barre lo vencido
+	cache.store("sub-3", uuid.New(), start.Add(userCacheTTL+2*time.Minute))
+	var left []string
+	cache.entries.Range(func(key, _ interface{}) bool {
+		left = append(left, key.(string))
+		return true
+	})
+	if len(left) != 1 || left[0] != "sub-3" {
+		t.Fatalf("entries after sweep = %v, want [sub-3]", left)
+	}
+	if _, ok := cache.load("sub-3", start.Add(userCacheTTL+3*time.Minute)); !ok {
+		t.Fatal("fresh entry missing")
+	}
+}
@@ -57,6 +57,7 @@ func (r *documentRepository) GetByVerificationCode(ctx context.Context, verifica
 		Preload("UserDetail").
 		Preload("Event").
 		Preload("Template").
+		Preload("Revocations", activeRevocation).
 		First(&doc, "verification_code = ?", verificationCode).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -107,6 +108,39 @@ func (r *documentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Document{}, "id = ?", id).Error
 }
 
+func (r *documentRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Document, error) {
+	var doc models.Document
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		First(&doc, "id = ? AND deleted_at IS NOT NULL", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &doc, err
+}
+
+func (r *documentRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return restoreDeleted(ctx, r.db, &models.Document{}, id)
+}
+
+func (r *documentRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("document_id = ?", id).Delete(&models.DocumentPDF{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("document_id = ?", id).Delete(&models.DocumentRevocation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.DocumentRevocation{}).Where("superseded_by_id = ?", id).Update("superseded_by_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Document{}).Unscoped().Where("replaced_by_id = ?", id).Update("replaced_by_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Document{}, "id = ?", id).Error
+	})
+}
+
 func (r *documentRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Document{}).Count(&count).Error
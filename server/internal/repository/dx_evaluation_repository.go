@@ -28,7 +28,7 @@ func (r *evaluationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mode
 	err := r.db.WithContext(ctx).
 		Preload("User").
 		Preload("Document").
-		Preload("Questions").
+		Preload("Questions", "attempt_id IS NULL").
 		Preload("Answers").
 		Preload("Scores").
 		Preload("Docs").
@@ -43,7 +43,7 @@ func (r *evaluationRepository) GetByUserID(ctx context.Context, userID uuid.UUID
 	var evaluations []models.Evaluation
 	err := r.db.WithContext(ctx).
 		Preload("Document").
-		Preload("Questions").
+		Preload("Questions", "attempt_id IS NULL").
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&evaluations).Error
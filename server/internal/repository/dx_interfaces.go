@@ -29,6 +29,12 @@ type UserDetailRepository interface {
 	Update(ctx context.Context, detail *models.UserDetail) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
+
+	// soft delete
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.UserDetail, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	CountIssuedDocuments(ctx context.Context, id uuid.UUID) (int64, error)
 }
 
 // DocumentTypeRepository defines the interface for document type data access
@@ -103,6 +109,11 @@ type DocumentRepository interface {
 	Update(ctx context.Context, doc *models.Document) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
+
+	// soft delete
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Document, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
 }
 
 // NotificationRepository defines the interface for notification data access
@@ -116,6 +127,7 @@ type NotificationRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
 	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 // EvaluationRepository defines the interface for evaluation data access
@@ -78,7 +78,15 @@ func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.
 }
 
 func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Notification{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// deliveries reference the notification; keep them unlinked
+		if err := tx.Model(&models.NotificationDelivery{}).
+			Where("notification_id = ?", id).
+			Update("notification_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Notification{}, "id = ?", id).Error
+	})
 }
 
 func (r *notificationRepository) Count(ctx context.Context) (int64, error) {
@@ -95,3 +103,12 @@ func (r *notificationRepository) CountUnreadByUserID(ctx context.Context, userID
 		Count(&count).Error
 	return count, err
 }
+
+func (r *notificationRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
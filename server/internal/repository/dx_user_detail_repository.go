@@ -59,6 +59,37 @@ func (r *userDetailRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.UserDetail{}, "id = ?", id).Error
 }
 
+func (r *userDetailRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.UserDetail, error) {
+	var detail models.UserDetail
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		First(&detail, "id = ? AND deleted_at IS NOT NULL", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &detail, err
+}
+
+func (r *userDetailRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return restoreDeleted(ctx, r.db, &models.UserDetail{}, id)
+}
+
+func (r *userDetailRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := purgeDraftDocuments(tx, "user_detail_id", id); err != nil {
+			return err
+		}
+		if err := tx.Where("user_detail_id = ?", id).Delete(&models.EventParticipant{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.UserDetail{}, "id = ?", id).Error
+	})
+}
+
+func (r *userDetailRepository) CountIssuedDocuments(ctx context.Context, id uuid.UUID) (int64, error) {
+	return countIssuedDocuments(ctx, r.db, "user_detail_id", id)
+}
+
 func (r *userDetailRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.UserDetail{}).Count(&count).Error
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+// auditChainLockKey serializes appends so sequence numbers and previous hashes never fork
+const auditChainLockKey = 7305541
+
+type fnAuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewFNAuditLogRepository creates a new FN audit log repository
+func NewFNAuditLogRepository(db *gorm.DB) FNAuditLogRepository {
+	return &fnAuditLogRepository{db: db}
+}
+
+func (r *fnAuditLogRepository) Append(ctx context.Context, entry *models.AuditLog, hash func(*models.AuditLog) string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+			return err
+		}
+
+		var last models.AuditLog
+		err := tx.Select("seq", "hash").Order("seq DESC").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+		entry.Hash = hash(entry)
+
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *fnAuditLogRepository) List(ctx context.Context, params dto.AuditListQuery) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := params.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	filters := func(db *gorm.DB) *gorm.DB {
+		if params.ActorID != nil {
+			db = db.Where("actor_id = ?", *params.ActorID)
+		}
+		if params.EntityType != nil && strings.TrimSpace(*params.EntityType) != "" {
+			db = db.Where("entity_type = ?", strings.TrimSpace(*params.EntityType))
+		}
+		if params.EntityID != nil && strings.TrimSpace(*params.EntityID) != "" {
+			db = db.Where("entity_id = ?", strings.TrimSpace(*params.EntityID))
+		}
+		if params.Action != nil && strings.TrimSpace(*params.Action) != "" {
+			db = db.Where("action = ?", strings.ToUpper(strings.TrimSpace(*params.Action)))
+		}
+		if params.Outcome != nil && strings.TrimSpace(*params.Outcome) != "" {
+			db = db.Where("outcome = ?", strings.ToUpper(strings.TrimSpace(*params.Outcome)))
+		}
+		if params.RequestID != nil && strings.TrimSpace(*params.RequestID) != "" {
+			db = db.Where("request_id = ?", strings.TrimSpace(*params.RequestID))
+		}
+		if params.From != nil {
+			db = db.Where("created_at >= ?", *params.From)
+		}
+		if params.To != nil {
+			db = db.Where("created_at <= ?", *params.To)
+		}
+		return db
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.AuditLog{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.AuditLog{}, 0, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Scopes(filters).
+		Order("seq DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *fnAuditLogRepository) ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := r.db.WithContext(ctx).
+		Where("seq > ?", seq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *fnAuditLogRepository) Snapshot(ctx context.Context, table, id string) (map[string]interface{}, error) {
+	row := map[string]interface{}{}
+	err := r.db.WithContext(ctx).Table(table).Where("id = ?", id).Take(&row).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
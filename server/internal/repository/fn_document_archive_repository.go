@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnDocumentArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewFNDocumentArchiveRepository creates a new FN document archive repository
+func NewFNDocumentArchiveRepository(db *gorm.DB) FNDocumentArchiveRepository {
+	return &fnDocumentArchiveRepository{db: db}
+}
+
+func (r *fnDocumentArchiveRepository) Create(ctx context.Context, job *models.DocumentArchiveJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *fnDocumentArchiveRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.DocumentArchiveJob, error) {
+	var job models.DocumentArchiveJob
+	err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *fnDocumentArchiveRepository) Update(ctx context.Context, job *models.DocumentArchiveJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *fnDocumentArchiveRepository) UpdateProgress(ctx context.Context, id uuid.UUID, processed, failed int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.DocumentArchiveJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"processed_items": processed,
+			"failed_items":    failed,
+			"updated_at":      time.Now().UTC(),
+		}).Error
+}
+
+func (r *fnDocumentArchiveRepository) FailUnfinished(ctx context.Context, message string, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.DocumentArchiveJob{}).
+		Where("status IN ?", []string{dto.ArchiveStatusPending, dto.ArchiveStatusProcessing}).
+		Updates(map[string]interface{}{
+			"status":        dto.ArchiveStatusFailed,
+			"error_message": message,
+			"updated_at":    at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *fnDocumentArchiveRepository) GetExpired(ctx context.Context, now time.Time) ([]models.DocumentArchiveJob, error) {
+	var jobs []models.DocumentArchiveJob
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at < ?", now).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *fnDocumentArchiveRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.DocumentArchiveJob{}, "id = ?", id).Error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+)
+
+type fnDocumentDownloadLogRepository struct {
+	db *gorm.DB
+}
+
+// NewFNDocumentDownloadLogRepository creates a new FN document download log repository
+func NewFNDocumentDownloadLogRepository(db *gorm.DB) FNDocumentDownloadLogRepository {
+	return &fnDocumentDownloadLogRepository{db: db}
+}
+
+func (r *fnDocumentDownloadLogRepository) Create(ctx context.Context, entry *models.DocumentDownloadLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
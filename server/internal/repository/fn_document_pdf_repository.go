@@ -39,6 +39,22 @@ func (r *fnDocumentPDFRepository) GetLatestByDocumentID(ctx context.Context, doc
 		Order("created_at DESC").
 		First(&pdf).Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pdf, nil
+}
+
+func (r *fnDocumentPDFRepository) GetLatestByDocumentIDAndVersion(ctx context.Context, documentID uuid.UUID, version int) (*models.DocumentPDF, error) {
+	var pdf models.DocumentPDF
+	err := r.db.WithContext(ctx).
+		Where("document_id = ? AND version = ?", documentID, version).
+		Order("created_at DESC").
+		First(&pdf).Error
+
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -26,6 +26,39 @@ func (r *fnDocumentRepository) Create(ctx context.Context, doc *models.Document)
 	return r.db.WithContext(ctx).Create(doc).Error
 }
 
+func (r *fnDocumentRepository) Reissue(ctx context.Context, previous, next *models.Document, revocation *models.DocumentRevocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		// guard against a concurrent reissue of the same version
+		result := tx.Model(&models.Document{}).
+			Where("id = ? AND replaced_by_id IS NULL", previous.ID).
+			Updates(map[string]interface{}{
+				"replaced_by_id": next.ID,
+				"status":         dto.DocStatusRejected,
+				"updated_at":     next.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("document already reissued")
+		}
+
+		if revocation != nil {
+			return tx.Create(revocation).Error
+		}
+		return tx.Model(&models.DocumentRevocation{}).
+			Where("document_id = ? AND lifted_at IS NULL AND superseded_by_id IS NULL", previous.ID).
+			Updates(map[string]interface{}{
+				"superseded_by_id": next.ID,
+				"updated_at":       next.CreatedAt,
+			}).Error
+	})
+}
+
 func (r *fnDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Document, error) {
 	var doc models.Document
 	err := r.db.WithContext(ctx).
@@ -35,7 +68,9 @@ func (r *fnDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*mode
 		Preload("PDFs", func(db *gorm.DB) *gorm.DB {
 			return db.Order("document_pdfs.created_at DESC")
 		}).
-		First(&doc, "id = ?", id).Error
+		Preload("Revocations", activeRevocation).
+		Scopes(scopeDocuments(ctx)).
+		First(&doc, "documents.id = ?", id).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -53,7 +88,27 @@ func (r *fnDocumentRepository) GetBySerialCode(ctx context.Context, serialCode s
 		Preload("Event").
 		Preload("Template").
 		Preload("PDFs").
-		First(&doc, "serial_code = ?", serialCode).Error
+		Preload("Revocations", activeRevocation).
+		Scopes(scopeDocuments(ctx)).
+		First(&doc, "documents.serial_code = ?", serialCode).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *fnDocumentRepository) GetByVerificationCode(ctx context.Context, verificationCode string) (*models.Document, error) {
+	var doc models.Document
+	err := r.db.WithContext(ctx).
+		Preload("UserDetail").
+		Preload("Event").
+		Preload("Revocations", activeRevocation).
+		Scopes(scopeDocuments(ctx)).
+		First(&doc, "documents.verification_code = ?", verificationCode).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -67,7 +122,7 @@ func (r *fnDocumentRepository) GetBySerialCode(ctx context.Context, serialCode s
 func (r *fnDocumentRepository) GetByEventAndUserDetail(ctx context.Context, eventID, userDetailID uuid.UUID) (*models.Document, error) {
 	var doc models.Document
 	err := r.db.WithContext(ctx).
-		Where("event_id = ? AND user_detail_id = ?", eventID, userDetailID).
+		Where("event_id = ? AND user_detail_id = ? AND replaced_by_id IS NULL", eventID, userDetailID).
 		First(&doc).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -95,7 +150,7 @@ func (r *fnDocumentRepository) List(ctx context.Context, params dto.DocumentList
 		pageSize = 100
 	}
 
-	query := r.db.WithContext(ctx).Model(&models.Document{})
+	query := scopeDocuments(ctx)(r.db.WithContext(ctx).Model(&models.Document{}))
 
 	if params.EventID != nil && *params.EventID != "" {
 		eventID, err := uuid.Parse(*params.EventID)
@@ -182,7 +237,9 @@ func (r *fnDocumentRepository) GetNextSerialNumber(ctx context.Context, prefix s
 	
 	pattern := prefix + "%"
 	
+	// deleted documents keep their serial codes, which stay unique
 	err := r.db.WithContext(ctx).
+		Unscoped().
 		Model(&models.Document{}).
 		Where("serial_code LIKE ?", pattern).
 		Select("COALESCE(MAX(CAST(SUBSTRING(serial_code FROM '[0-9]+$') AS BIGINT)), 0)").
@@ -230,4 +287,119 @@ func (r *fnDocumentRepository) BulkUpdateStatus(ctx context.Context, ids []uuid.
 			"status":     status,
 			"updated_at": time.Now().UTC(),
 		}).Error
-}
\ No newline at end of file
+}
+
+func (r *fnDocumentRepository) GetDocumentsWithPDFsByEventID(ctx context.Context, eventID uuid.UUID) ([]models.Document, error) {
+	var docs []models.Document
+	err := r.db.WithContext(ctx).
+		Preload("UserDetail").
+		Preload("PDFs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("document_pdfs.created_at DESC")
+		}).
+		Scopes(scopeDocuments(ctx), archivableDocuments).
+		Where("documents.event_id = ?", eventID).
+		Order("documents.serial_code ASC").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *fnDocumentRepository) GetDocumentsWithPDFsByPDFJobID(ctx context.Context, pdfJobID uuid.UUID) ([]models.Document, error) {
+	var docs []models.Document
+	err := r.db.WithContext(ctx).
+		Preload("UserDetail").
+		Preload("PDFs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("document_pdfs.created_at DESC")
+		}).
+		Scopes(scopeDocuments(ctx), archivableDocuments).
+		Where("documents.pdf_job_id = ?", pdfJobID).
+		Order("documents.serial_code ASC").
+		Find(&docs).Error
+	return docs, err
+}
+
+// archivableDocuments keeps the documents in force: not replaced by a reissue,
+// not rejected and without a revocation that has not been lifted
+func archivableDocuments(db *gorm.DB) *gorm.DB {
+	return db.
+		Where("documents.replaced_by_id IS NULL AND documents.status <> ?", dto.DocStatusRejected).
+		Where("NOT EXISTS (SELECT 1 FROM document_revocations r WHERE r.document_id = documents.id AND r.lifted_at IS NULL)")
+}
+
+// ListByUserDetailID lists a beneficiary's documents across all events. It is not
+// restricted by organizational unit: callers only ever see their own documents.
+func (r *fnDocumentRepository) ListByUserDetailID(ctx context.Context, userDetailID uuid.UUID, params dto.MyDocumentListQuery) ([]models.Document, int64, error) {
+	var docs []models.Document
+	var total int64
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := params.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	filters := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("documents.user_detail_id = ?", userDetailID)
+
+		if params.EventID != nil && *params.EventID != "" {
+			if eventID, err := uuid.Parse(*params.EventID); err == nil {
+				db = db.Where("documents.event_id = ?", eventID)
+			}
+		}
+
+		if !params.IncludeAll {
+			db = db.Where("documents.status = ?", dto.DocStatusPDFCompleted)
+		}
+
+		return db
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.Document{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.Document{}, 0, nil
+	}
+
+	offset := (page - 1) * pageSize
+
+	err := r.db.WithContext(ctx).
+		Scopes(filters).
+		Preload("Event").
+		Preload("Template").
+		Preload("PDFs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("document_pdfs.created_at DESC")
+		}).
+		Order("documents.issue_date DESC, documents.id ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&docs).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return docs, total, nil
+}
+
+func (r *fnDocumentRepository) GetByUserDetailAndEventIDs(ctx context.Context, userDetailID uuid.UUID, eventIDs []uuid.UUID) ([]models.Document, error) {
+	var docs []models.Document
+	if len(eventIDs) == 0 {
+		return docs, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Template").
+		Preload("PDFs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("document_pdfs.created_at DESC")
+		}).
+		Where("documents.user_detail_id = ? AND documents.event_id IN ? AND documents.replaced_by_id IS NULL", userDetailID, eventIDs).
+		Find(&docs).Error
+	return docs, err
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnDocumentRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewFNDocumentRevocationRepository creates a new FN document revocation repository
+func NewFNDocumentRevocationRepository(db *gorm.DB) FNDocumentRevocationRepository {
+	return &fnDocumentRevocationRepository{db: db}
+}
+
+func (r *fnDocumentRevocationRepository) Revoke(ctx context.Context, revocation *models.DocumentRevocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revocation).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Document{}).
+			Where("id = ?", revocation.DocumentID).
+			Updates(map[string]interface{}{
+				"status":     dto.DocStatusRejected,
+				"updated_at": revocation.RevokedAt,
+			}).Error
+	})
+}
+
+func (r *fnDocumentRevocationRepository) Lift(ctx context.Context, documentID, liftedBy uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.DocumentRevocation{}).
+		Where("document_id = ? AND lifted_at IS NULL", documentID).
+		Updates(map[string]interface{}{
+			"lifted_at":  at,
+			"lifted_by":  liftedBy,
+			"updated_at": at,
+		}).Error
+}
+
+func (r *fnDocumentRevocationRepository) ListActive(ctx context.Context) ([]models.DocumentRevocation, error) {
+	var revocations []models.DocumentRevocation
+	err := r.db.WithContext(ctx).
+		Preload("Document", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("SupersededBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("lifted_at IS NULL").
+		Order("revoked_at ASC, id ASC").
+		Find(&revocations).Error
+	return revocations, err
+}
+
+func (r *fnDocumentRevocationRepository) LastChangedAt(ctx context.Context) (time.Time, error) {
+	var last *time.Time
+	err := r.db.WithContext(ctx).
+		Model(&models.DocumentRevocation{}).
+		Select("MAX(updated_at)").
+		Scan(&last).Error
+	if err != nil || last == nil {
+		return time.Time{}, err
+	}
+	return *last, nil
+}
+
+// activeRevocation preloads the revocation in force with its superseding document
+func activeRevocation(db *gorm.DB) *gorm.DB {
+	return db.Where("lifted_at IS NULL").Preload("SupersededBy")
+}
@@ -49,7 +49,8 @@ func (r *fnDocumentTemplateRepository) GetByID(ctx context.Context, id uuid.UUID
 			return db.Order("document_template_fields.created_at ASC")
 		}).
 		Preload("User").
-		First(&template, "id = ?", id).Error
+		Scopes(scopeTemplates(ctx)).
+		First(&template, "document_templates.id = ?", id).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -68,7 +69,8 @@ func (r *fnDocumentTemplateRepository) GetByCode(ctx context.Context, code strin
 		Preload("Fields", func(db *gorm.DB) *gorm.DB {
 			return db.Order("document_template_fields.created_at ASC")
 		}).
-		First(&template, "code = ?", code).Error
+		Scopes(scopeTemplates(ctx)).
+		First(&template, "document_templates.code = ?", code).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -95,7 +97,7 @@ func (r *fnDocumentTemplateRepository) List(ctx context.Context, params dto.Docu
 		pageSize = 100
 	}
 
-	query := r.db.WithContext(ctx).Model(&models.DocumentTemplate{})
+	query := scopeTemplates(ctx)(r.db.WithContext(ctx).Model(&models.DocumentTemplate{}))
 
 	if params.IsActive != nil {
 		query = query.Where("document_templates.is_active = ?", *params.IsActive)
@@ -146,15 +148,57 @@ func (r *fnDocumentTemplateRepository) Update(ctx context.Context, template *mod
 	return r.db.WithContext(ctx).Save(template).Error
 }
 
+// Delete soft-deletes the template; its fields are kept so it can be restored
 func (r *fnDocumentTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.DocumentTemplate{}, "id = ?", id).Error
+}
+
+func (r *fnDocumentTemplateRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.DocumentTemplate, error) {
+	var template models.DocumentTemplate
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Scopes(scopeTemplates(ctx)).
+		First(&template, "document_templates.id = ? AND document_templates.deleted_at IS NOT NULL", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *fnDocumentTemplateRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return restoreDeleted(ctx, r.db, &models.DocumentTemplate{}, id)
+}
+
+func (r *fnDocumentTemplateRepository) Purge(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := purgeDraftDocuments(tx, "template_id", id); err != nil {
+			return err
+		}
 		if err := tx.Where("template_id = ?", id).Delete(&models.DocumentTemplateField{}).Error; err != nil {
 			return err
 		}
-		return tx.Delete(&models.DocumentTemplate{}, "id = ?", id).Error
+		return tx.Unscoped().Delete(&models.DocumentTemplate{}, "id = ?", id).Error
 	})
 }
 
+func (r *fnDocumentTemplateRepository) CountIssuedDocuments(ctx context.Context, templateID uuid.UUID) (int64, error) {
+	return countIssuedDocuments(ctx, r.db, "template_id", templateID)
+}
+
+func (r *fnDocumentTemplateRepository) CountEventsByTemplateID(ctx context.Context, templateID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Event{}).
+		Where("template_id = ?", templateID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *fnDocumentTemplateRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
 	return r.db.WithContext(ctx).
 		Model(&models.DocumentTemplate{}).
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnEvaluationRepository struct {
+	db *gorm.DB
+}
+
+// NewFNEvaluationRepository creates a new FN evaluation repository
+func NewFNEvaluationRepository(db *gorm.DB) FNEvaluationRepository {
+	return &fnEvaluationRepository{db: db}
+}
+
+// errAttemptRace rolls back a transaction whose attempt changed state meanwhile
+var errAttemptRace = errors.New("attempt state changed")
+
+func (r *fnEvaluationRepository) Create(ctx context.Context, evaluation *models.Evaluation) error {
+	return r.db.WithContext(ctx).Create(evaluation).Error
+}
+
+func (r *fnEvaluationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Evaluation, error) {
+	var evaluation models.Evaluation
+	err := r.db.WithContext(ctx).
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Where("attempt_id IS NULL").Order("question_number ASC")
+		}).
+		Preload("Questions.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		Preload("DrawRules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		First(&evaluation, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &evaluation, err
+}
+
+func (r *fnEvaluationRepository) UpdateSettings(ctx context.Context, evaluation *models.Evaluation) error {
+	return r.db.WithContext(ctx).
+		Model(evaluation).
+		Select("title", "description", "status", "passing_score", "time_limit_minutes", "max_attempts", "updated_at").
+		Updates(evaluation).Error
+}
+
+func (r *fnEvaluationRepository) ReplaceQuestions(ctx context.Context, evaluationID uuid.UUID, questions []models.EvaluationQuestion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		oldIDs := tx.Model(&models.EvaluationQuestion{}).Select("id").Where("evaluation_id = ? AND attempt_id IS NULL", evaluationID)
+		if err := tx.Where("question_id IN (?)", oldIDs).Delete(&models.EvaluationQuestionOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("evaluation_id = ? AND attempt_id IS NULL", evaluationID).Delete(&models.EvaluationQuestion{}).Error; err != nil {
+			return err
+		}
+		if len(questions) == 0 {
+			return nil
+		}
+		return tx.Create(&questions).Error
+	})
+}
+
+func (r *fnEvaluationRepository) ReplaceDrawRules(ctx context.Context, evaluationID uuid.UUID, rules []models.EvaluationDrawRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("evaluation_id = ?", evaluationID).Delete(&models.EvaluationDrawRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+func (r *fnEvaluationRepository) CountAttempts(ctx context.Context, evaluationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.EvaluationAttempt{}).
+		Where("evaluation_id = ?", evaluationID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *fnEvaluationRepository) GetLatestAttempt(ctx context.Context, evaluationID, userID uuid.UUID) (*models.EvaluationAttempt, error) {
+	var attempt models.EvaluationAttempt
+	err := preloadAttemptQuestions(r.db.WithContext(ctx)).
+		Where("evaluation_id = ? AND user_id = ?", evaluationID, userID).
+		Order("attempt_number DESC").
+		First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &attempt, err
+}
+
+func (r *fnEvaluationRepository) GetAttempt(ctx context.Context, id uuid.UUID) (*models.EvaluationAttempt, error) {
+	var attempt models.EvaluationAttempt
+	err := preloadAttemptQuestions(r.db.WithContext(ctx)).
+		Preload("Answers").
+		Preload("Scores").
+		First(&attempt, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &attempt, err
+}
+
+// preloadAttemptQuestions loads the questions drawn for an attempt in their shuffled order
+func preloadAttemptQuestions(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("question_number ASC")
+		}).
+		Preload("Questions.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		})
+}
+
+func (r *fnEvaluationRepository) ListAttempts(ctx context.Context, evaluationID uuid.UUID) ([]models.EvaluationAttempt, error) {
+	var attempts []models.EvaluationAttempt
+	err := r.db.WithContext(ctx).
+		Where("evaluation_id = ?", evaluationID).
+		Order("started_at DESC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *fnEvaluationRepository) CreateAttempt(ctx context.Context, attempt *models.EvaluationAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *fnEvaluationRepository) ExpireAttempt(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	passed := false
+	res := r.db.WithContext(ctx).
+		Model(&models.EvaluationAttempt{}).
+		Where("id = ? AND status = ?", id, dto.AttemptStatusInProgress).
+		Updates(map[string]interface{}{
+			"status":     dto.AttemptStatusExpired,
+			"passed":     &passed,
+			"updated_at": at,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *fnEvaluationRepository) SaveSubmission(ctx context.Context, attempt *models.EvaluationAttempt, answers []models.EvaluationAnswer, scores []models.EvaluationScore) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.EvaluationAttempt{}).
+			Where("id = ? AND status = ?", attempt.ID, dto.AttemptStatusInProgress).
+			Updates(map[string]interface{}{
+				"status":       attempt.Status,
+				"submitted_at": attempt.SubmittedAt,
+				"graded_at":    attempt.GradedAt,
+				"score":        attempt.Score,
+				"max_score":    attempt.MaxScore,
+				"percentage":   attempt.Percentage,
+				"passed":       attempt.Passed,
+				"updated_at":   attempt.UpdatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errAttemptRace
+		}
+		if len(answers) > 0 {
+			if err := tx.Create(&answers).Error; err != nil {
+				return err
+			}
+		}
+		if len(scores) > 0 {
+			if err := tx.Create(&scores).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errAttemptRace) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *fnEvaluationRepository) GetScore(ctx context.Context, id uuid.UUID) (*models.EvaluationScore, error) {
+	var score models.EvaluationScore
+	err := r.db.WithContext(ctx).
+		Preload("Question").
+		First(&score, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &score, err
+}
+
+func (r *fnEvaluationRepository) ListPendingReviews(ctx context.Context, params dto.ReviewQueueQuery) ([]models.EvaluationScore, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.EvaluationScore{}).
+		Where("admin_verdict = ? AND attempt_id IS NOT NULL", dto.VerdictPending)
+	if params.EvaluationID != nil {
+		query = query.Where("evaluation_id = ?", *params.EvaluationID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []models.EvaluationScore
+	err := query.
+		Preload("Evaluation").
+		Preload("Question").
+		Preload("Attempt").
+		Order("reviewed_at ASC").
+		Limit(params.PageSize).
+		Offset((params.Page - 1) * params.PageSize).
+		Find(&scores).Error
+	return scores, total, err
+}
+
+func (r *fnEvaluationRepository) GetAnswers(ctx context.Context, attemptIDs []uuid.UUID) ([]models.EvaluationAnswer, error) {
+	var answers []models.EvaluationAnswer
+	if len(attemptIDs) == 0 {
+		return answers, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("attempt_id IN ?", attemptIDs).
+		Find(&answers).Error
+	return answers, err
+}
+
+func (r *fnEvaluationRepository) SaveReview(ctx context.Context, score *models.EvaluationScore) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// serialize reviews of the same attempt so the last one sees no pending answers
+		var attempt models.EvaluationAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&attempt, "id = ?", score.AttemptID).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.EvaluationScore{}).
+			Where("id = ? AND admin_verdict = ?", score.ID, dto.VerdictPending).
+			Updates(map[string]interface{}{
+				"admin_verdict": score.AdminVerdict,
+				"score":         score.Score,
+				"remarks":       score.Remarks,
+				"reviewed_by":   score.ReviewedBy,
+				"reviewed_at":   score.ReviewedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errAttemptRace
+		}
+		return nil
+	})
+	if errors.Is(err, errAttemptRace) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *fnEvaluationRepository) FinalizeAttempt(ctx context.Context, attempt *models.EvaluationAttempt) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.EvaluationAttempt{}).
+		Where("id = ? AND status = ?", attempt.ID, dto.AttemptStatusUnderReview).
+		Updates(map[string]interface{}{
+			"status":     attempt.Status,
+			"graded_at":  attempt.GradedAt,
+			"score":      attempt.Score,
+			"percentage": attempt.Percentage,
+			"passed":     attempt.Passed,
+			"updated_at": attempt.UpdatedAt,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *fnEvaluationRepository) GetReportByAttemptID(ctx context.Context, attemptID uuid.UUID) (*models.EvaluationDoc, error) {
+	var doc models.EvaluationDoc
+	err := r.db.WithContext(ctx).First(&doc, "attempt_id = ?", attemptID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &doc, err
+}
+
+func (r *fnEvaluationRepository) GetReportByPDFJobID(ctx context.Context, pdfJobID uuid.UUID) (*models.EvaluationDoc, error) {
+	var doc models.EvaluationDoc
+	err := r.db.WithContext(ctx).First(&doc, "pdf_job_id = ?", pdfJobID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &doc, err
+}
+
+func (r *fnEvaluationRepository) SaveReport(ctx context.Context, doc *models.EvaluationDoc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.EvaluationDoc
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&existing, "attempt_id = ?", doc.AttemptID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(doc).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		doc.ID = existing.ID
+		doc.PdfStatus = nil
+		doc.PdfJobID = nil
+		doc.PdfFileID = nil
+		doc.PdfFileHash = nil
+		return tx.Model(&models.EvaluationDoc{}).
+			Where("id = ?", doc.ID).
+			Updates(map[string]interface{}{
+				"markdown_content": doc.MarkdownContent,
+				"generated_at":     doc.GeneratedAt,
+				"pdf_status":       nil,
+				"pdf_job_id":       nil,
+				"pdf_file_id":      nil,
+				"pdf_file_hash":    nil,
+			}).Error
+	})
+}
+
+func (r *fnEvaluationRepository) UpdateReportPDF(ctx context.Context, doc *models.EvaluationDoc) error {
+	return r.db.WithContext(ctx).
+		Model(&models.EvaluationDoc{}).
+		Where("id = ?", doc.ID).
+		Updates(map[string]interface{}{
+			"pdf_status":    doc.PdfStatus,
+			"pdf_job_id":    doc.PdfJobID,
+			"pdf_file_id":   doc.PdfFileID,
+			"pdf_file_hash": doc.PdfFileHash,
+		}).Error
+}
\ No newline at end of file
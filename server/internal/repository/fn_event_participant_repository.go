@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnEventParticipantRepository struct {
+	db *gorm.DB
+}
+
+// NewFNEventParticipantRepository creates a new FN event participant repository
+func NewFNEventParticipantRepository(db *gorm.DB) FNEventParticipantRepository {
+	return &fnEventParticipantRepository{db: db}
+}
+
+func (r *fnEventParticipantRepository) Create(ctx context.Context, participant *models.EventParticipant, newUserDetail *models.UserDetail) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if newUserDetail != nil {
+			if err := tx.Create(newUserDetail).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Event", "UserDetail").Create(participant).Error
+	})
+}
+
+func (r *fnEventParticipantRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.EventParticipant, error) {
+	var participant models.EventParticipant
+	err := r.db.WithContext(ctx).
+		Preload("UserDetail").
+		First(&participant, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+func (r *fnEventParticipantRepository) GetByEventAndUserDetail(ctx context.Context, eventID, userDetailID uuid.UUID) (*models.EventParticipant, error) {
+	var participant models.EventParticipant
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND user_detail_id = ?", eventID, userDetailID).
+		First(&participant).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+func (r *fnEventParticipantRepository) List(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]models.EventParticipant, int64, error) {
+	var participants []models.EventParticipant
+	var total int64
+
+	filters := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("event_participants.event_id = ?", eventID)
+
+		if params.RegistrationStatus != nil && strings.TrimSpace(*params.RegistrationStatus) != "" {
+			db = db.Where("event_participants.registration_status = ?", strings.TrimSpace(*params.RegistrationStatus))
+		}
+
+		if params.AttendanceStatus != nil && strings.TrimSpace(*params.AttendanceStatus) != "" {
+			db = db.Where("event_participants.attendance_status = ?", strings.TrimSpace(*params.AttendanceStatus))
+		}
+
+		if params.SearchQuery != nil && strings.TrimSpace(*params.SearchQuery) != "" {
+			q := "%" + strings.TrimSpace(*params.SearchQuery) + "%"
+			db = db.
+				Joins("JOIN user_details ud ON ud.id = event_participants.user_detail_id").
+				Where("ud.first_name ILIKE ? OR ud.last_name ILIKE ? OR ud.national_id ILIKE ? OR ud.email ILIKE ?", q, q, q, q)
+		}
+
+		return db
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.EventParticipant{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.EventParticipant{}, 0, nil
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+
+	// the current document comes from the same query instead of one lookup per participant
+	err := r.db.WithContext(ctx).
+		Scopes(filters).
+		Select("event_participants.*, d.id AS document_id, d.status AS document_status, d.serial_code AS document_serial_code").
+		Joins("LEFT JOIN documents d ON d.event_id = event_participants.event_id AND d.user_detail_id = event_participants.user_detail_id AND d.replaced_by_id IS NULL AND d.deleted_at IS NULL").
+		Preload("UserDetail").
+		Order("event_participants.created_at ASC, event_participants.id ASC").
+		Offset(offset).
+		Limit(params.PageSize).
+		Find(&participants).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return participants, total, nil
+}
+
+func (r *fnEventParticipantRepository) Update(ctx context.Context, participant *models.EventParticipant) error {
+	return r.db.WithContext(ctx).
+		Omit("Event", "UserDetail").
+		Save(participant).Error
+}
+
+func (r *fnEventParticipantRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.EventParticipant{}, "id = ?", id).Error
+}
+
+func (r *fnEventParticipantRepository) Remove(ctx context.Context, id uuid.UUID, draftDocumentID *uuid.UUID, revocation *models.DocumentRevocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if draftDocumentID != nil {
+			if err := tx.Delete(&models.Document{}, "id = ?", *draftDocumentID).Error; err != nil {
+				return err
+			}
+		}
+		if revocation != nil {
+			if err := tx.Create(revocation).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&models.Document{}).
+				Where("id = ?", revocation.DocumentID).
+				Updates(map[string]interface{}{
+					"status":     dto.DocStatusRejected,
+					"updated_at": revocation.RevokedAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.EventParticipant{}, "id = ?", id).Error
+	})
+}
+
+func (r *fnEventParticipantRepository) BulkUpdateStatus(ctx context.Context, eventID uuid.UUID, ids []uuid.UUID, registrationStatus, attendanceStatus *string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	updates := map[string]interface{}{
+		"updated_at": time.Now().UTC(),
+	}
+	if registrationStatus != nil {
+		updates["registration_status"] = *registrationStatus
+	}
+	if attendanceStatus != nil {
+		updates["attendance_status"] = *attendanceStatus
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.EventParticipant{}).
+		Where("event_id = ? AND id IN ?", eventID, ids).
+		Updates(updates)
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *fnEventParticipantRepository) MarkEligible(ctx context.Context, id, attemptID uuid.UUID, finalScore float64, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.EventParticipant{}).
+		Where("id = ? AND certificate_eligible = ?", id, false).
+		Updates(map[string]interface{}{
+			"certificate_eligible":  true,
+			"eligible_at":           at,
+			"final_score":           finalScore,
+			"evaluation_attempt_id": attemptID,
+			"updated_at":            at,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *fnEventParticipantRepository) StreamRegister(ctx context.Context, eventID uuid.UUID, params dto.DocumentListQuery, onlyWithDocument bool, fn func(row dto.DocumentRegisterRow) error) error {
+	query := r.db.WithContext(ctx).
+		Table("event_participants ep").
+		Select(`ud.national_id, ud.first_name, ud.last_name, ud.email,
+			ep.registration_status, ep.attendance_status,
+			d.id AS document_id, d.serial_code, d.verification_code, d.status,
+			d.digital_signature_status, d.issue_date`).
+		Joins("JOIN user_details ud ON ud.id = ep.user_detail_id").
+		Joins("LEFT JOIN documents d ON d.event_id = ep.event_id AND d.user_detail_id = ep.user_detail_id AND d.replaced_by_id IS NULL AND d.deleted_at IS NULL").
+		Where("ep.event_id = ?", eventID)
+
+	if onlyWithDocument {
+		query = query.Where("d.id IS NOT NULL")
+	}
+
+	if params.TemplateID != nil && *params.TemplateID != "" {
+		templateID, err := uuid.Parse(*params.TemplateID)
+		if err == nil {
+			query = query.Where("d.template_id = ?", templateID)
+		}
+	}
+
+	if params.Status != nil && *params.Status != "" {
+		query = query.Where("d.status = ?", *params.Status)
+	}
+
+	if params.SearchQuery != nil && strings.TrimSpace(*params.SearchQuery) != "" {
+		q := "%" + strings.TrimSpace(*params.SearchQuery) + "%"
+		query = query.Where("d.serial_code ILIKE ? OR ud.first_name ILIKE ? OR ud.last_name ILIKE ? OR ud.national_id ILIKE ?", q, q, q, q)
+	}
+
+	rows, err := query.Order("ud.last_name ASC, ud.first_name ASC, ud.national_id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dto.DocumentRegisterRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *fnEventParticipantRepository) ListByUserDetailID(ctx context.Context, userDetailID uuid.UUID, params dto.MyEventListQuery) ([]models.EventParticipant, int64, error) {
+	var participants []models.EventParticipant
+	var total int64
+
+	filters := func(db *gorm.DB) *gorm.DB {
+		return db.Where("event_participants.user_detail_id = ?", userDetailID)
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.EventParticipant{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.EventParticipant{}, 0, nil
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+
+	err := r.db.WithContext(ctx).
+		Scopes(filters).
+		Preload("Event").
+		Order("event_participants.created_at DESC, event_participants.id ASC").
+		Offset(offset).
+		Limit(params.PageSize).
+		Find(&participants).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return participants, total, nil
+}
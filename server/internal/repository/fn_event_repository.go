@@ -57,6 +57,7 @@ func (r *fnEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		Preload("EventParticipants").
 		Preload("EventParticipants.UserDetail").
 		Preload("User").
+		Scopes(scopeEvents(ctx)).
 		First(&event, "id = ?", id).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -75,6 +76,7 @@ func (r *fnEventRepository) GetByCode(ctx context.Context, code string) (*models
 		Preload("Schedules").
 		Preload("EventParticipants").
 		Preload("EventParticipants.UserDetail").
+		Scopes(scopeEvents(ctx)).
 		First(&event, "code = ?", code).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -102,7 +104,7 @@ func (r *fnEventRepository) List(ctx context.Context, params dto.EventListQuery)
 		pageSize = 100
 	}
 
-	query := r.db.WithContext(ctx).Model(&models.Event{})
+	query := scopeEvents(ctx)(r.db.WithContext(ctx).Model(&models.Event{}))
 
 	// filters
 	if params.IsPublic != nil {
@@ -158,6 +160,45 @@ func (r *fnEventRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Event{}, "id = ?", id).Error
 }
 
+func (r *fnEventRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	var event models.Event
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Scopes(scopeEvents(ctx)).
+		First(&event, "id = ? AND deleted_at IS NOT NULL", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *fnEventRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return restoreDeleted(ctx, r.db, &models.Event{}, id)
+}
+
+func (r *fnEventRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := purgeDraftDocuments(tx, "event_id", id); err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventParticipant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventSchedule{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Event{}, "id = ?", id).Error
+	})
+}
+
+func (r *fnEventRepository) CountIssuedDocuments(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	return countIssuedDocuments(ctx, r.db, "event_id", eventID)
+}
+
 func (r *fnEventRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -192,4 +233,24 @@ func (r *fnEventRepository) CountSchedulesByEventID(ctx context.Context, eventID
 		Where("event_id = ?", eventID).
 		Count(&count).Error
 	return count, err
-}
\ No newline at end of file
+}
+
+func (r *fnEventRepository) ListByEvaluationID(ctx context.Context, evaluationID uuid.UUID) ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.WithContext(ctx).
+		Scopes(scopeEvents(ctx)).
+		Where("evaluation_id = ?", evaluationID).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *fnEventRepository) ListByStudyMaterialID(ctx context.Context, studyMaterialID uuid.UUID) ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.WithContext(ctx).
+		Scopes(scopeEvents(ctx)).
+		Where("study_material_id = ?", studyMaterialID).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
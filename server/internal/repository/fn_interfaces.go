@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -26,6 +27,13 @@ type FNDocumentTemplateRepository interface {
 	GetCategoryByCodeAndTypeID(ctx context.Context, code string, typeID uuid.UUID) (*models.DocumentCategory, error)
 	CountFieldsByTemplateID(ctx context.Context, templateID uuid.UUID) (int64, error)
 
+	// soft delete
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.DocumentTemplate, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	CountIssuedDocuments(ctx context.Context, templateID uuid.UUID) (int64, error)
+	CountEventsByTemplateID(ctx context.Context, templateID uuid.UUID) (int64, error)
+
 	// field operations
 	CreateField(ctx context.Context, field *models.DocumentTemplateField) error
 	UpdateField(ctx context.Context, field *models.DocumentTemplateField) error
@@ -51,14 +59,46 @@ type FNEventRepository interface {
 	ExistsByCodeExcludingID(ctx context.Context, code string, excludeID uuid.UUID) (bool, error)
 	CountParticipantsByEventID(ctx context.Context, eventID uuid.UUID) (int64, error)
 	CountSchedulesByEventID(ctx context.Context, eventID uuid.UUID) (int64, error)
+	// ListByEvaluationID returns the events that require the evaluation
+	ListByEvaluationID(ctx context.Context, evaluationID uuid.UUID) ([]models.Event, error)
+	// ListByStudyMaterialID returns the events that require the study material
+	ListByStudyMaterialID(ctx context.Context, studyMaterialID uuid.UUID) ([]models.Event, error)
+
+	// soft delete
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	CountIssuedDocuments(ctx context.Context, eventID uuid.UUID) (int64, error)
 }
 
 // -- fn user detail repository
 
 // FNUserDetailRepository defines the interface for user detail data access
 type FNUserDetailRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.UserDetail, error)
 	GetByNationalID(ctx context.Context, nationalID string) (*models.UserDetail, error)
 	Create(ctx context.Context, userDetail *models.UserDetail) error
+	Update(ctx context.Context, userDetail *models.UserDetail) error
+}
+
+// -- fn event participant repository
+
+// FNEventParticipantRepository defines the interface for event participant data access
+type FNEventParticipantRepository interface {
+	// Create inserts the participant, and newUserDetail when the beneficiary is new, in one transaction
+	Create(ctx context.Context, participant *models.EventParticipant, newUserDetail *models.UserDetail) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.EventParticipant, error)
+	GetByEventAndUserDetail(ctx context.Context, eventID, userDetailID uuid.UUID) (*models.EventParticipant, error)
+	List(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]models.EventParticipant, int64, error)
+	Update(ctx context.Context, participant *models.EventParticipant) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Remove deletes the participant together with its draft document, or revokes its generated one, in one transaction
+	Remove(ctx context.Context, id uuid.UUID, draftDocumentID *uuid.UUID, revocation *models.DocumentRevocation) error
+	BulkUpdateStatus(ctx context.Context, eventID uuid.UUID, ids []uuid.UUID, registrationStatus, attendanceStatus *string) (int64, error)
+	// MarkEligible records the passed evaluation of a participant; false when already eligible
+	MarkEligible(ctx context.Context, id, attemptID uuid.UUID, finalScore float64, at time.Time) (bool, error)
+	StreamRegister(ctx context.Context, eventID uuid.UUID, params dto.DocumentListQuery, onlyWithDocument bool, fn func(row dto.DocumentRegisterRow) error) error
+	ListByUserDetailID(ctx context.Context, userDetailID uuid.UUID, params dto.MyEventListQuery) ([]models.EventParticipant, int64, error)
 }
 
 // -- fn document repository
@@ -68,7 +108,12 @@ type FNDocumentRepository interface {
 	Create(ctx context.Context, doc *models.Document) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Document, error)
 	GetBySerialCode(ctx context.Context, serialCode string) (*models.Document, error)
+	GetByVerificationCode(ctx context.Context, verificationCode string) (*models.Document, error)
+	// GetByEventAndUserDetail returns the current version of the participant's document
 	GetByEventAndUserDetail(ctx context.Context, eventID, userDetailID uuid.UUID) (*models.Document, error)
+	// Reissue creates the next version of a document, links both and revokes the previous
+	// one; revocation is nil when the previous version is already revoked
+	Reissue(ctx context.Context, previous, next *models.Document, revocation *models.DocumentRevocation) error
 	List(ctx context.Context, params dto.DocumentListQuery) ([]models.Document, int64, error)
 	Update(ctx context.Context, doc *models.Document) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
@@ -76,8 +121,12 @@ type FNDocumentRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetNextSerialNumber(ctx context.Context, prefix string) (int64, error)
 	GetDocumentsByPDFJobID(ctx context.Context, pdfJobID uuid.UUID) ([]models.Document, error)
+	GetDocumentsWithPDFsByEventID(ctx context.Context, eventID uuid.UUID) ([]models.Document, error)
+	GetDocumentsWithPDFsByPDFJobID(ctx context.Context, pdfJobID uuid.UUID) ([]models.Document, error)
 	GetDocumentByUserIDAndPDFJobID(ctx context.Context, userDetailID, pdfJobID uuid.UUID) (*models.Document, error)
 	BulkUpdateStatus(ctx context.Context, ids []uuid.UUID, status string) error
+	ListByUserDetailID(ctx context.Context, userDetailID uuid.UUID, params dto.MyDocumentListQuery) ([]models.Document, int64, error)
+	GetByUserDetailAndEventIDs(ctx context.Context, userDetailID uuid.UUID, eventIDs []uuid.UUID) ([]models.Document, error)
 }
 
 // -- fn document pdf repository
@@ -87,4 +136,232 @@ type FNDocumentPDFRepository interface {
 	Create(ctx context.Context, pdf *models.DocumentPDF) error
 	GetByDocumentID(ctx context.Context, documentID uuid.UUID) ([]models.DocumentPDF, error)
 	GetLatestByDocumentID(ctx context.Context, documentID uuid.UUID) (*models.DocumentPDF, error)
+	GetLatestByDocumentIDAndVersion(ctx context.Context, documentID uuid.UUID, version int) (*models.DocumentPDF, error)
+}
+
+// -- fn document archive repository
+
+// FNDocumentArchiveRepository defines the interface for bulk archive job data access
+type FNDocumentArchiveRepository interface {
+	Create(ctx context.Context, job *models.DocumentArchiveJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.DocumentArchiveJob, error)
+	Update(ctx context.Context, job *models.DocumentArchiveJob) error
+	UpdateProgress(ctx context.Context, id uuid.UUID, processed, failed int) error
+	// FailUnfinished marks every pending or processing job as failed
+	FailUnfinished(ctx context.Context, message string, at time.Time) (int64, error)
+	GetExpired(ctx context.Context, now time.Time) ([]models.DocumentArchiveJob, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// -- fn user repository
+
+// FNUserRepository defines the interface for account data access
+type FNUserRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByKeycloakSub(ctx context.Context, sub string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByNationalID(ctx context.Context, nationalID string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+}
+
+// -- fn document download log repository
+
+// FNDocumentDownloadLogRepository defines the interface for certificate download logging
+type FNDocumentDownloadLogRepository interface {
+	Create(ctx context.Context, entry *models.DocumentDownloadLog) error
+}
+
+// -- fn api key repository
+
+// FNAPIKeyRepository defines the interface for integration API key data access
+type FNAPIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// -- fn audit log repository
+
+// FNAuditLogRepository defines the interface for the hash-chained audit trail
+type FNAuditLogRepository interface {
+	// Append assigns the next sequence number and previous hash under a lock,
+	// seals the entry with hash and stores it
+	Append(ctx context.Context, entry *models.AuditLog, hash func(*models.AuditLog) string) error
+	List(ctx context.Context, params dto.AuditListQuery) ([]models.AuditLog, int64, error)
+	ListAfterSeq(ctx context.Context, seq int64, limit int) ([]models.AuditLog, error)
+	// Snapshot reads a raw row of table by primary key; nil when it does not exist
+	Snapshot(ctx context.Context, table, id string) (map[string]interface{}, error)
+}
+
+// -- fn document revocation repository
+
+// FNDocumentRevocationRepository defines the interface for certificate revocation data access
+type FNDocumentRevocationRepository interface {
+	// Revoke stores the revocation and marks the document REJECTED in one transaction
+	Revoke(ctx context.Context, revocation *models.DocumentRevocation) error
+	// Lift ends the revocation in force of a document
+	Lift(ctx context.Context, documentID, liftedBy uuid.UUID, at time.Time) error
+	ListActive(ctx context.Context) ([]models.DocumentRevocation, error)
+	LastChangedAt(ctx context.Context) (time.Time, error)
+}
+
+// -- fn notification repository
+
+// FNNotificationRepository defines the interface for notification dispatch data access
+type FNNotificationRepository interface {
+	// Create stores the in-app notification (optional) and its deliveries. It returns
+	// false when the deliveries' dedup keys already exist (event already dispatched).
+	Create(ctx context.Context, notification *models.Notification, deliveries []models.NotificationDelivery) (bool, error)
+
+	// retry queue
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error)
+	MarkDeliverySent(ctx context.Context, id uuid.UUID, attempts int, at time.Time) error
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt *time.Time, at time.Time) error
+	GetDeliveryByID(ctx context.Context, id uuid.UUID) (*models.NotificationDelivery, error)
+	RequeueDelivery(ctx context.Context, id uuid.UUID, at time.Time) error
+	ListDeliveries(ctx context.Context, params dto.NotificationDeliveryListQuery) ([]models.NotificationDelivery, int64, error)
+
+	// preferences
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	GetPreferencesByType(ctx context.Context, userIDs []uuid.UUID, notificationType string) ([]models.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, prefs []models.NotificationPreference) error
+
+	// digests
+	ListDigestGroups(ctx context.Context, mode string, before time.Time) ([]dto.NotificationDigestGroup, error)
+	GetDigestItems(ctx context.Context, group dto.NotificationDigestGroup, mode string, before time.Time) ([]models.NotificationDelivery, error)
+	// CreateDigest stores the summary (and its in-app notification) and marks the items as
+	// digested. It returns false when another worker already digested them.
+	CreateDigest(ctx context.Context, notification *models.Notification, digest *models.NotificationDelivery, itemIDs []uuid.UUID) (bool, error)
+
+	// in-app notifications
+	GetUserNotification(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error)
+	// GetUserNotificationsAfter returns the user's notifications after the (createdAt, id) cursor, oldest first
+	GetUserNotificationsAfter(ctx context.Context, userID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	ListUserNotifications(ctx context.Context, userID uuid.UUID, filter dto.UserNotificationFilter) ([]models.Notification, error)
+	// MarkUserNotificationsRead marks the given (or, with nil ids, all) unread notifications as read
+	MarkUserNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error)
+	DeleteUserNotificationsBefore(ctx context.Context, userID uuid.UUID, before time.Time) (int64, error)
+
+	// recipients
+	GetRecipientsByUserDetailIDs(ctx context.Context, ids []uuid.UUID) ([]dto.NotificationRecipient, error)
+	GetRecipientsByEventID(ctx context.Context, eventID uuid.UUID) ([]dto.NotificationRecipient, error)
+	GetRecipientByUserID(ctx context.Context, userID uuid.UUID) (*dto.NotificationRecipient, error)
+}
+
+// -- fn evaluation repository
+
+// FNEvaluationRepository defines the interface for evaluation authoring and attempt data access
+type FNEvaluationRepository interface {
+	// authoring
+	Create(ctx context.Context, evaluation *models.Evaluation) error
+	// GetByID returns the evaluation with its fixed questions and options in order, and its draw rules
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Evaluation, error)
+	UpdateSettings(ctx context.Context, evaluation *models.Evaluation) error
+	// ReplaceQuestions deletes the fixed questions (and options) of an evaluation and stores the new ones
+	ReplaceQuestions(ctx context.Context, evaluationID uuid.UUID, questions []models.EvaluationQuestion) error
+	ReplaceDrawRules(ctx context.Context, evaluationID uuid.UUID, rules []models.EvaluationDrawRule) error
+
+	// attempts
+	CountAttempts(ctx context.Context, evaluationID uuid.UUID) (int64, error)
+	// GetLatestAttempt and GetAttempt include the questions drawn for the attempt
+	GetLatestAttempt(ctx context.Context, evaluationID, userID uuid.UUID) (*models.EvaluationAttempt, error)
+	GetAttempt(ctx context.Context, id uuid.UUID) (*models.EvaluationAttempt, error)
+	ListAttempts(ctx context.Context, evaluationID uuid.UUID) ([]models.EvaluationAttempt, error)
+	// CreateAttempt stores the attempt with its drawn questions, if any
+	CreateAttempt(ctx context.Context, attempt *models.EvaluationAttempt) error
+	// ExpireAttempt closes an in-progress attempt that ran out of time; false if it was no longer in progress
+	ExpireAttempt(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// SaveSubmission stores the answers and scores and the attempt result in one transaction.
+	// It returns false when the attempt was no longer in progress (already submitted or expired).
+	SaveSubmission(ctx context.Context, attempt *models.EvaluationAttempt, answers []models.EvaluationAnswer, scores []models.EvaluationScore) (bool, error)
+
+	// manual review
+	GetScore(ctx context.Context, id uuid.UUID) (*models.EvaluationScore, error)
+	ListPendingReviews(ctx context.Context, params dto.ReviewQueueQuery) ([]models.EvaluationScore, int64, error)
+	GetAnswers(ctx context.Context, attemptIDs []uuid.UUID) ([]models.EvaluationAnswer, error)
+	// SaveReview stores the verdict of a pending score; false when it was already reviewed
+	SaveReview(ctx context.Context, score *models.EvaluationScore) (bool, error)
+	// FinalizeAttempt stores the result of an attempt under review; false when another review already did
+	FinalizeAttempt(ctx context.Context, attempt *models.EvaluationAttempt) (bool, error)
+
+	// reports
+	GetReportByAttemptID(ctx context.Context, attemptID uuid.UUID) (*models.EvaluationDoc, error)
+	GetReportByPDFJobID(ctx context.Context, pdfJobID uuid.UUID) (*models.EvaluationDoc, error)
+	// SaveReport creates the report of an attempt or replaces its content; the PDF fields are reset
+	SaveReport(ctx context.Context, doc *models.EvaluationDoc) error
+	// UpdateReportPDF stores the PDF status, job and file of a report
+	UpdateReportPDF(ctx context.Context, doc *models.EvaluationDoc) error
+}
+
+// -- fn question bank repository
+
+// FNQuestionBankRepository defines the interface for question bank data access
+type FNQuestionBankRepository interface {
+	Create(ctx context.Context, question *models.BankQuestion) error
+	// GetByID returns the question with its options in order
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BankQuestion, error)
+	List(ctx context.Context, params dto.BankQuestionListQuery) ([]models.BankQuestion, int64, error)
+	// Update stores the question and replaces its options
+	Update(ctx context.Context, question *models.BankQuestion) error
+	SetActive(ctx context.Context, id uuid.UUID, active bool) error
+
+	// drawing (topics match case-insensitively, difficulty nil = any)
+	CountActive(ctx context.Context, topic string, difficulty *string) (int64, error)
+	// Draw returns up to count random active questions, skipping the excluded ones
+	Draw(ctx context.Context, topic string, difficulty *string, count int, exclude []uuid.UUID) ([]models.BankQuestion, error)
+
+	// ListItemScores returns the graded answers to drawn copies of the given questions
+	ListItemScores(ctx context.Context, questionIDs []uuid.UUID) ([]dto.BankItemScoreRow, error)
+}
+
+// -- fn study material repository
+
+// FNStudyMaterialRepository defines the interface for the learning path of study
+// materials: outline management, per-user progress and personal annotations
+type FNStudyMaterialRepository interface {
+	GetMaterial(ctx context.Context, id uuid.UUID) (*models.StudyMaterial, error)
+	// GetOutline returns the material with its sections, subsections and resources in order
+	GetOutline(ctx context.Context, id uuid.UUID) (*models.StudyMaterial, error)
+
+	// sections
+	GetSection(ctx context.Context, id uuid.UUID) (*models.StudySection, error)
+	CreateSection(ctx context.Context, section *models.StudySection) error
+	UpdateSection(ctx context.Context, section *models.StudySection) error
+	// DeleteSection removes the section with its subsections and their resources, progress and annotations
+	DeleteSection(ctx context.Context, id uuid.UUID) error
+	// NextSectionIndex returns the order index after the material's last section
+	NextSectionIndex(ctx context.Context, materialID uuid.UUID) (int, error)
+	// ReorderSections sets the order index of each section to its position in ids
+	ReorderSections(ctx context.Context, materialID uuid.UUID, ids []uuid.UUID) error
+
+	// subsections
+	// GetSubsection returns the subsection with its section
+	GetSubsection(ctx context.Context, id uuid.UUID) (*models.StudySubsection, error)
+	CreateSubsection(ctx context.Context, subsection *models.StudySubsection) error
+	UpdateSubsection(ctx context.Context, subsection *models.StudySubsection) error
+	// DeleteSubsection removes the subsection with its resources, progress and annotations
+	DeleteSubsection(ctx context.Context, id uuid.UUID) error
+	NextSubsectionIndex(ctx context.Context, sectionID uuid.UUID) (int, error)
+	ReorderSubsections(ctx context.Context, sectionID uuid.UUID, ids []uuid.UUID) error
+
+	// progress
+	ListProgress(ctx context.Context, materialID, userID uuid.UUID) ([]models.StudyProgress, error)
+	// SetProgress inserts or updates the user's row for the subsection
+	SetProgress(ctx context.Context, progress *models.StudyProgress) error
+	CountSubsections(ctx context.Context, materialID uuid.UUID) (int64, error)
+	CountCompleted(ctx context.Context, materialID, userID uuid.UUID) (int64, error)
+
+	// annotations
+	ListAnnotations(ctx context.Context, subsectionID, userID uuid.UUID) ([]models.StudyAnnotation, error)
+	// CountAnnotations returns the user's annotation count per subsection of the material
+	CountAnnotations(ctx context.Context, materialID, userID uuid.UUID) (map[uuid.UUID]int64, error)
+	GetAnnotation(ctx context.Context, id uuid.UUID) (*models.StudyAnnotation, error)
+	CreateAnnotation(ctx context.Context, annotation *models.StudyAnnotation) error
+	UpdateAnnotation(ctx context.Context, annotation *models.StudyAnnotation) error
+	DeleteAnnotation(ctx context.Context, id uuid.UUID) error
 }
\ No newline at end of file
@@ -0,0 +1,432 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewFNNotificationRepository creates a new FN notification repository
+func NewFNNotificationRepository(db *gorm.DB) FNNotificationRepository {
+	return &fnNotificationRepository{db: db}
+}
+
+func (r *fnNotificationRepository) Create(ctx context.Context, notification *models.Notification, deliveries []models.NotificationDelivery) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keys := make([]string, 0, len(deliveries))
+		for _, d := range deliveries {
+			keys = append(keys, d.DedupKey)
+		}
+		if len(keys) > 0 {
+			var existing int64
+			if err := tx.Model(&models.NotificationDelivery{}).Where("dedup_key IN ?", keys).Count(&existing).Error; err != nil {
+				return err
+			}
+			// the domain event was already dispatched to this recipient
+			if existing > 0 {
+				return nil
+			}
+		}
+
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+			for i := range deliveries {
+				deliveries[i].NotificationID = &notification.ID
+			}
+		}
+		if len(deliveries) > 0 {
+			if err := tx.Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *fnNotificationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+	var claimed []models.NotificationDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", dto.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&claimed).Error
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(claimed))
+		for _, d := range claimed {
+			ids = append(ids, d.ID)
+		}
+		// push the claimed rows out of the queue while they are being sent;
+		// a crashed sender leaves them to be picked again after the lease
+		return tx.Model(&models.NotificationDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return claimed, err
+}
+
+func (r *fnNotificationRepository) MarkDeliverySent(ctx context.Context, id uuid.UUID, attempts int, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     dto.DeliveryStatusSent,
+			"attempts":   attempts,
+			"sent_at":    at,
+			"last_error": nil,
+			"updated_at": at,
+		}).Error
+}
+
+func (r *fnNotificationRepository) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt *time.Time, at time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": lastError,
+		"updated_at": at,
+	}
+	if nextAttemptAt != nil {
+		updates["status"] = dto.DeliveryStatusPending
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = dto.DeliveryStatusFailed
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *fnNotificationRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*models.NotificationDelivery, error) {
+	var delivery models.NotificationDelivery
+	err := r.db.WithContext(ctx).First(&delivery, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *fnNotificationRepository) RequeueDelivery(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          dto.DeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"updated_at":      at,
+		}).Error
+}
+
+func (r *fnNotificationRepository) ListDeliveries(ctx context.Context, params dto.NotificationDeliveryListQuery) ([]models.NotificationDelivery, int64, error) {
+	var deliveries []models.NotificationDelivery
+	var total int64
+
+	page := params.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := params.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	filters := func(db *gorm.DB) *gorm.DB {
+		if params.Status != nil && strings.TrimSpace(*params.Status) != "" {
+			db = db.Where("status = ?", strings.ToUpper(strings.TrimSpace(*params.Status)))
+		}
+		if params.Channel != nil && strings.TrimSpace(*params.Channel) != "" {
+			db = db.Where("channel = ?", strings.ToUpper(strings.TrimSpace(*params.Channel)))
+		}
+		if params.TemplateKey != nil && strings.TrimSpace(*params.TemplateKey) != "" {
+			db = db.Where("template_key = ?", strings.TrimSpace(*params.TemplateKey))
+		}
+		if params.UserDetailID != nil {
+			db = db.Where("user_detail_id = ?", *params.UserDetailID)
+		}
+		if params.UserID != nil {
+			db = db.Where("user_id = ?", *params.UserID)
+		}
+		if params.NotificationID != nil {
+			db = db.Where("notification_id = ?", *params.NotificationID)
+		}
+		return db
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.NotificationDelivery{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []models.NotificationDelivery{}, 0, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Scopes(filters).
+		Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// -- preferences
+
+func (r *fnNotificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("notification_type ASC, channel ASC").
+		Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *fnNotificationRepository) GetPreferencesByType(ctx context.Context, userIDs []uuid.UUID, notificationType string) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	if len(userIDs) == 0 {
+		return prefs, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("user_id IN ? AND notification_type = ?", userIDs, notificationType).
+		Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *fnNotificationRepository) UpsertPreferences(ctx context.Context, prefs []models.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "notification_type"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_at"}),
+		}).
+		Create(&prefs).Error
+}
+
+// -- digests
+
+func (r *fnNotificationRepository) ListDigestGroups(ctx context.Context, mode string, before time.Time) ([]dto.NotificationDigestGroup, error) {
+	var groups []dto.NotificationDigestGroup
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Select("user_id, channel").
+		Where("status = ? AND digest_mode = ? AND created_at < ? AND user_id IS NOT NULL", dto.DeliveryStatusDigestPending, mode, before).
+		Group("user_id, channel").
+		Scan(&groups).Error
+	return groups, err
+}
+
+func (r *fnNotificationRepository) GetDigestItems(ctx context.Context, group dto.NotificationDigestGroup, mode string, before time.Time) ([]models.NotificationDelivery, error) {
+	var items []models.NotificationDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND digest_mode = ? AND created_at < ?", dto.DeliveryStatusDigestPending, mode, before).
+		Where("user_id = ? AND channel = ?", group.UserID, group.Channel).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// errDigestRace rolls back a digest whose items were taken by another worker
+var errDigestRace = errors.New("digest items already taken")
+
+func (r *fnNotificationRepository) CreateDigest(ctx context.Context, notification *models.Notification, digest *models.NotificationDelivery, itemIDs []uuid.UUID) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+			digest.NotificationID = &notification.ID
+		}
+		if err := tx.Create(digest).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.NotificationDelivery{}).
+			Where("id IN ? AND status = ?", itemIDs, dto.DeliveryStatusDigestPending).
+			Updates(map[string]interface{}{
+				"status":     dto.DeliveryStatusDigested,
+				"digest_id":  digest.ID,
+				"updated_at": digest.CreatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(itemIDs)) {
+			return errDigestRace
+		}
+		return nil
+	})
+	if errors.Is(err, errDigestRace) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// -- in-app notifications
+
+func (r *fnNotificationRepository) GetUserNotification(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).First(&notification, "id = ? AND user_id = ?", id, userID).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *fnNotificationRepository) GetUserNotificationsAfter(ctx context.Context, userID uuid.UUID, createdAt time.Time, id uuid.UUID, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND (created_at, id) > (?, ?)", userID, createdAt, id).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *fnNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *fnNotificationRepository) ListUserNotifications(ctx context.Context, userID uuid.UUID, filter dto.UserNotificationFilter) ([]models.Notification, error) {
+	var notifications []models.Notification
+
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.BeforeCreatedAt != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *filter.BeforeCreatedAt, filter.BeforeID)
+	}
+	if len(filter.Types) > 0 {
+		query = query.Where("notification_type IN ?", filter.Types)
+	}
+	if filter.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *fnNotificationRepository) MarkUserNotificationsRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+
+	res := query.Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": at,
+	})
+	return res.RowsAffected, res.Error
+}
+
+func (r *fnNotificationRepository) DeleteUserNotificationsBefore(ctx context.Context, userID uuid.UUID, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := tx.Model(&models.Notification{}).Select("id").Where("user_id = ? AND created_at < ?", userID, before)
+
+		// keep the delivery log; it just loses the link to the removed notification
+		if err := tx.Model(&models.NotificationDelivery{}).
+			Where("notification_id IN (?)", ids).
+			Update("notification_id", nil).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("user_id = ? AND created_at < ?", userID, before).Delete(&models.Notification{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	return deleted, err
+}
+
+// -- recipients
+
+// a beneficiary and an account are the same person when they share the national ID
+const recipientColumns = `ud.id AS user_detail_id, u.id AS user_id,
+	COALESCE(NULLIF(ud.first_name, ''), u.first_name) AS first_name,
+	COALESCE(NULLIF(ud.last_name, ''), u.last_name) AS last_name,
+	COALESCE(NULLIF(ud.email, ''), u.email) AS email`
+
+func (r *fnNotificationRepository) GetRecipientsByUserDetailIDs(ctx context.Context, ids []uuid.UUID) ([]dto.NotificationRecipient, error) {
+	var recipients []dto.NotificationRecipient
+	if len(ids) == 0 {
+		return recipients, nil
+	}
+	err := r.db.WithContext(ctx).
+		Table("user_details ud").
+		Select(recipientColumns).
+		Joins("LEFT JOIN users u ON u.national_id = ud.national_id").
+		Where("ud.id IN ? AND ud.deleted_at IS NULL", ids).
+		Scan(&recipients).Error
+	return recipients, err
+}
+
+func (r *fnNotificationRepository) GetRecipientsByEventID(ctx context.Context, eventID uuid.UUID) ([]dto.NotificationRecipient, error) {
+	var recipients []dto.NotificationRecipient
+	err := r.db.WithContext(ctx).
+		Table("event_participants ep").
+		Select(recipientColumns).
+		Joins("JOIN user_details ud ON ud.id = ep.user_detail_id AND ud.deleted_at IS NULL").
+		Joins("LEFT JOIN users u ON u.national_id = ud.national_id").
+		Where("ep.event_id = ?", eventID).
+		Scan(&recipients).Error
+	return recipients, err
+}
+
+func (r *fnNotificationRepository) GetRecipientByUserID(ctx context.Context, userID uuid.UUID) (*dto.NotificationRecipient, error) {
+	var recipients []dto.NotificationRecipient
+	err := r.db.WithContext(ctx).
+		Table("users u").
+		Select(recipientColumns).
+		Joins("LEFT JOIN user_details ud ON ud.national_id = u.national_id AND ud.deleted_at IS NULL").
+		Where("u.id = ?", userID).
+		Limit(1).
+		Scan(&recipients).Error
+	if err != nil || len(recipients) == 0 {
+		return nil, err
+	}
+	return &recipients[0], nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"server/internal/domain/orgunit"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// orgUnitCondition builds "column is inside one of the scope subtrees"
+func orgUnitCondition(column string, scope orgunit.Scope) (string, []interface{}) {
+	if len(scope.Paths) == 0 {
+		return "1 = 0", nil
+	}
+
+	clauses := make([]string, 0, len(scope.Paths))
+	args := make([]interface{}, 0, len(scope.Paths)*2)
+	for _, p := range scope.Paths {
+		clauses = append(clauses, "("+column+" = ? OR "+column+` LIKE ? ESCAPE '\')`)
+		args = append(args, p, likeEscaper.Replace(p)+"/%")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// scopeEvents restricts events to the caller's organizational units
+func scopeEvents(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope := orgunit.FromContext(ctx)
+		if scope.Bypass {
+			return db
+		}
+		cond, args := orgUnitCondition("events.organizational_units_path", scope)
+		return db.Where(cond, args...)
+	}
+}
+
+// scopeDocuments restricts documents to those of events in the caller's organizational units
+func scopeDocuments(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope := orgunit.FromContext(ctx)
+		if scope.Bypass {
+			return db
+		}
+		cond, args := orgUnitCondition("events.organizational_units_path", scope)
+		return db.Where("documents.event_id IN (SELECT events.id FROM events WHERE "+cond+")", args...)
+	}
+}
+
+// scopeTemplates restricts templates to the caller's organizational units.
+// Templates without a unit are shared and visible to every scope.
+func scopeTemplates(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope := orgunit.FromContext(ctx)
+		if scope.Bypass {
+			return db
+		}
+		cond, args := orgUnitCondition("document_templates.organizational_units_path", scope)
+		return db.Where("(document_templates.organizational_units_path = '' OR "+cond+")", args...)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
+)
+
+// dryRunDB builds statements without a database connection
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return db
+}
+
+func TestOrgUnitCondition(t *testing.T) {
+	cond, args := orgUnitCondition("events.organizational_units_path", orgunit.NewScope("/gra/ggr", "/gra/sub_gerencia%"))
+
+	want := `((events.organizational_units_path = ? OR events.organizational_units_path LIKE ? ESCAPE '\') OR ` +
+		`(events.organizational_units_path = ? OR events.organizational_units_path LIKE ? ESCAPE '\'))`
+	if cond != want {
+		t.Fatalf("cond = %s", cond)
+	}
+	wantArgs := []interface{}{"/gra/ggr", "/gra/ggr/%", "/gra/sub_gerencia%", `/gra/sub\_gerencia\%/%`}
+	if !slices.Equal(args, wantArgs) {
+		t.Fatalf("args = %q, want %q", args, wantArgs)
+	}
+
+	if cond, args := orgUnitCondition("events.organizational_units_path", orgunit.NewScope()); cond != "1 = 0" || args != nil {
+		t.Fatalf("empty scope = %s %v, want 1 = 0", cond, args)
+	}
+}
+
+func TestScopeQueries(t *testing.T) {
+	db := dryRunDB(t)
+
+	cases := []struct {
+		name  string
+		scope orgunit.Scope
+		query func(ctx context.Context) *gorm.DB
+		want  string
+		args  int
+	}{
+		{"events unrestricted", orgunit.Unrestricted, func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeEvents(ctx)).Find(&[]models.Event{})
+		}, "", 0},
+		{"events scoped", orgunit.NewScope("/gra/ggr"), func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeEvents(ctx)).Find(&[]models.Event{})
+		}, `events.organizational_units_path = $1 OR events.organizational_units_path LIKE $2`, 2},
+		{"events without unit or groups", orgunit.NewScope(), func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeEvents(ctx)).Find(&[]models.Event{})
+		}, "1 = 0", 0},
+		{"documents scoped", orgunit.NewScope("/gra/ggr"), func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeDocuments(ctx)).Find(&[]models.Document{})
+		}, "documents.event_id IN (SELECT events.id FROM events WHERE ((events.organizational_units_path = $1", 2},
+		{"documents without unit or groups", orgunit.NewScope(), func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeDocuments(ctx)).Find(&[]models.Document{})
+		}, "documents.event_id IN (SELECT events.id FROM events WHERE 1 = 0)", 0},
+		{"templates keep shared rows", orgunit.NewScope(), func(ctx context.Context) *gorm.DB {
+			return db.Scopes(scopeTemplates(ctx)).Find(&[]models.DocumentTemplate{})
+		}, "(document_templates.organizational_units_path = '' OR 1 = 0)", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt := tc.query(orgunit.WithScope(context.Background(), tc.scope)).Statement
+			sql := stmt.SQL.String()
+
+			if tc.want == "" {
+				if strings.Contains(sql, "organizational_units_path") || strings.Contains(sql, "1 = 0") {
+					t.Fatalf("unrestricted query is scoped: %s", sql)
+				}
+			} else if !strings.Contains(sql, tc.want) {
+				t.Fatalf("sql = %s\nwant it to contain %s", sql, tc.want)
+			}
+			if len(stmt.Vars) != tc.args {
+				t.Fatalf("vars = %v, want %d", stmt.Vars, tc.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+type fnQuestionBankRepository struct {
+	db *gorm.DB
+}
+
+// NewFNQuestionBankRepository creates a new FN question bank repository
+func NewFNQuestionBankRepository(db *gorm.DB) FNQuestionBankRepository {
+	return &fnQuestionBankRepository{db: db}
+}
+
+func (r *fnQuestionBankRepository) Create(ctx context.Context, question *models.BankQuestion) error {
+	return r.db.WithContext(ctx).Create(question).Error
+}
+
+func (r *fnQuestionBankRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BankQuestion, error) {
+	var question models.BankQuestion
+	err := r.db.WithContext(ctx).
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		First(&question, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &question, err
+}
+
+func (r *fnQuestionBankRepository) List(ctx context.Context, params dto.BankQuestionListQuery) ([]models.BankQuestion, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.BankQuestion{})
+
+	if params.Topic != nil && strings.TrimSpace(*params.Topic) != "" {
+		query = query.Where("LOWER(topic) = LOWER(?)", strings.TrimSpace(*params.Topic))
+	}
+	if params.Difficulty != nil {
+		query = query.Where("difficulty = ?", *params.Difficulty)
+	}
+	if params.QuestionType != nil {
+		query = query.Where("question_type = ?", *params.QuestionType)
+	}
+	if params.IsActive != nil {
+		query = query.Where("is_active = ?", *params.IsActive)
+	}
+	if params.SearchQuery != nil && strings.TrimSpace(*params.SearchQuery) != "" {
+		q := "%" + strings.TrimSpace(*params.SearchQuery) + "%"
+		query = query.Where("question_text ILIKE ? OR topic ILIKE ?", q, q)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var questions []models.BankQuestion
+	err := query.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		Order("topic ASC, created_at DESC").
+		Limit(params.PageSize).
+		Offset((params.Page - 1) * params.PageSize).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *fnQuestionBankRepository) Update(ctx context.Context, question *models.BankQuestion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(question).
+			Select("topic", "difficulty", "question_text", "question_type", "max_score", "updated_at").
+			Updates(question).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", question.ID).Delete(&models.BankQuestionOption{}).Error; err != nil {
+			return err
+		}
+		if len(question.Options) == 0 {
+			return nil
+		}
+		return tx.Create(&question.Options).Error
+	})
+}
+
+func (r *fnQuestionBankRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.BankQuestion{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_active":  active,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *fnQuestionBankRepository) CountActive(ctx context.Context, topic string, difficulty *string) (int64, error) {
+	var count int64
+	err := r.activeQuery(ctx, topic, difficulty).Count(&count).Error
+	return count, err
+}
+
+func (r *fnQuestionBankRepository) Draw(ctx context.Context, topic string, difficulty *string, count int, exclude []uuid.UUID) ([]models.BankQuestion, error) {
+	query := r.activeQuery(ctx, topic, difficulty)
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+
+	var questions []models.BankQuestion
+	err := query.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		Order("random()").
+		Limit(count).
+		Find(&questions).Error
+	return questions, err
+}
+
+func (r *fnQuestionBankRepository) ListItemScores(ctx context.Context, questionIDs []uuid.UUID) ([]dto.BankItemScoreRow, error) {
+	var rows []dto.BankItemScoreRow
+	if len(questionIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.WithContext(ctx).
+		Table("evaluation_scores s").
+		Select("q.bank_question_id, s.score AS item_score, q.max_score AS item_max, a.score AS attempt_score, a.max_score AS attempt_max").
+		Joins("JOIN evaluation_questions q ON q.id = s.question_id").
+		Joins("JOIN evaluation_attempts a ON a.id = s.attempt_id").
+		Where("q.bank_question_id IN ? AND a.status = ?", questionIDs, dto.AttemptStatusGraded).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *fnQuestionBankRepository) activeQuery(ctx context.Context, topic string, difficulty *string) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&models.BankQuestion{}).
+		Where("is_active = ? AND LOWER(topic) = LOWER(?)", true, strings.TrimSpace(topic))
+	if difficulty != nil {
+		query = query.Where("difficulty = ?", *difficulty)
+	}
+	return query
+}
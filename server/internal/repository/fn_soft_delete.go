@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+// restoreDeleted clears deleted_at of a soft-deleted row
+func restoreDeleted(ctx context.Context, db *gorm.DB, model interface{}, id uuid.UUID) error {
+	return db.WithContext(ctx).
+		Unscoped().
+		Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil).Error
+}
+
+// countIssuedDocuments counts documents (deleted ones included) referencing id through
+// column that went past the CREATED draft, i.e. were generated, rejected or renewed.
+// Those are certificate history and must never be hard deleted.
+func countIssuedDocuments(ctx context.Context, db *gorm.DB, column string, id uuid.UUID) (int64, error) {
+	var count int64
+	err := db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Document{}).
+		Where(column+" = ? AND status <> ?", id, dto.DocStatusCreated).
+		Count(&count).Error
+	return count, err
+}
+
+// purgeDraftDocuments hard deletes CREATED documents referencing id through column
+func purgeDraftDocuments(tx *gorm.DB, column string, id uuid.UUID) error {
+	drafts := tx.Unscoped().
+		Model(&models.Document{}).
+		Select("id").
+		Where(column+" = ? AND status = ?", id, dto.DocStatusCreated)
+
+	if err := tx.Where("document_id IN (?)", drafts).Delete(&models.DocumentPDF{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.DocumentRevocation{}).Where("superseded_by_id IN (?)", drafts).Update("superseded_by_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Document{}).Unscoped().Where("replaced_by_id IN (?)", drafts).Update("replaced_by_id", nil).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().
+		Where(column+" = ? AND status = ?", id, dto.DocStatusCreated).
+		Delete(&models.Document{}).Error
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"server/internal/domain/models"
+)
+
+// studyOrder puts unordered rows last, in creation order
+const studyOrder = "order_index ASC NULLS LAST, created_at ASC"
+
+type fnStudyMaterialRepository struct {
+	db *gorm.DB
+}
+
+// NewFNStudyMaterialRepository creates a new FN study material repository
+func NewFNStudyMaterialRepository(db *gorm.DB) FNStudyMaterialRepository {
+	return &fnStudyMaterialRepository{db: db}
+}
+
+func (r *fnStudyMaterialRepository) GetMaterial(ctx context.Context, id uuid.UUID) (*models.StudyMaterial, error) {
+	var material models.StudyMaterial
+	err := r.db.WithContext(ctx).First(&material, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &material, err
+}
+
+func (r *fnStudyMaterialRepository) GetOutline(ctx context.Context, id uuid.UUID) (*models.StudyMaterial, error) {
+	var material models.StudyMaterial
+	err := r.db.WithContext(ctx).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order(studyOrder)
+		}).
+		Preload("Sections.Subsections", func(db *gorm.DB) *gorm.DB {
+			return db.Order(studyOrder)
+		}).
+		Preload("Sections.Subsections.Resources", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&material, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &material, err
+}
+
+// -- sections
+
+func (r *fnStudyMaterialRepository) GetSection(ctx context.Context, id uuid.UUID) (*models.StudySection, error) {
+	var section models.StudySection
+	err := r.db.WithContext(ctx).First(&section, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &section, err
+}
+
+func (r *fnStudyMaterialRepository) CreateSection(ctx context.Context, section *models.StudySection) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(section).Error
+}
+
+func (r *fnStudyMaterialRepository) UpdateSection(ctx context.Context, section *models.StudySection) error {
+	return r.db.WithContext(ctx).
+		Model(section).
+		Select("title", "description", "order_index").
+		Updates(section).Error
+}
+
+func (r *fnStudyMaterialRepository) DeleteSection(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subsectionIDs := func() *gorm.DB {
+			return tx.Model(&models.StudySubsection{}).Select("id").Where("section_id = ?", id)
+		}
+		if err := deleteSubsectionChildren(tx, subsectionIDs); err != nil {
+			return err
+		}
+		if err := tx.Where("section_id = ?", id).Delete(&models.StudySubsection{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.StudySection{}).Error
+	})
+}
+
+func (r *fnStudyMaterialRepository) NextSectionIndex(ctx context.Context, materialID uuid.UUID) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).
+		Model(&models.StudySection{}).
+		Select("COALESCE(MAX(order_index), -1) + 1").
+		Where("material_id = ?", materialID).
+		Scan(&next).Error
+	return next, err
+}
+
+func (r *fnStudyMaterialRepository) ReorderSections(ctx context.Context, materialID uuid.UUID, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.StudySection{}).
+				Where("id = ? AND material_id = ?", id, materialID).
+				Update("order_index", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// -- subsections
+
+func (r *fnStudyMaterialRepository) GetSubsection(ctx context.Context, id uuid.UUID) (*models.StudySubsection, error) {
+	var subsection models.StudySubsection
+	err := r.db.WithContext(ctx).
+		Preload("Section").
+		First(&subsection, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &subsection, err
+}
+
+func (r *fnStudyMaterialRepository) CreateSubsection(ctx context.Context, subsection *models.StudySubsection) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(subsection).Error
+}
+
+func (r *fnStudyMaterialRepository) UpdateSubsection(ctx context.Context, subsection *models.StudySubsection) error {
+	return r.db.WithContext(ctx).
+		Model(subsection).
+		Select("title", "description", "video_url", "order_index").
+		Updates(subsection).Error
+}
+
+func (r *fnStudyMaterialRepository) DeleteSubsection(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subsectionIDs := func() *gorm.DB {
+			return tx.Model(&models.StudySubsection{}).Select("id").Where("id = ?", id)
+		}
+		if err := deleteSubsectionChildren(tx, subsectionIDs); err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.StudySubsection{}).Error
+	})
+}
+
+func (r *fnStudyMaterialRepository) NextSubsectionIndex(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).
+		Model(&models.StudySubsection{}).
+		Select("COALESCE(MAX(order_index), -1) + 1").
+		Where("section_id = ?", sectionID).
+		Scan(&next).Error
+	return next, err
+}
+
+func (r *fnStudyMaterialRepository) ReorderSubsections(ctx context.Context, sectionID uuid.UUID, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.StudySubsection{}).
+				Where("id = ? AND section_id = ?", id, sectionID).
+				Update("order_index", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// -- progress
+
+func (r *fnStudyMaterialRepository) ListProgress(ctx context.Context, materialID, userID uuid.UUID) ([]models.StudyProgress, error) {
+	db := r.db.WithContext(ctx)
+	var progress []models.StudyProgress
+	err := db.
+		Where("user_id = ? AND subsection_id IN (?)", userID, materialSubsectionIDs(db, materialID)).
+		Find(&progress).Error
+	return progress, err
+}
+
+func (r *fnStudyMaterialRepository) SetProgress(ctx context.Context, progress *models.StudyProgress) error {
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "subsection_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"completed", "completed_at"}),
+		}).
+		Create(progress).Error
+}
+
+func (r *fnStudyMaterialRepository) CountSubsections(ctx context.Context, materialID uuid.UUID) (int64, error) {
+	var count int64
+	err := materialSubsectionIDs(r.db.WithContext(ctx), materialID).Count(&count).Error
+	return count, err
+}
+
+func (r *fnStudyMaterialRepository) CountCompleted(ctx context.Context, materialID, userID uuid.UUID) (int64, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	err := db.Model(&models.StudyProgress{}).
+		Where("user_id = ? AND completed = ? AND subsection_id IN (?)", userID, true, materialSubsectionIDs(db, materialID)).
+		Count(&count).Error
+	return count, err
+}
+
+// -- annotations
+
+func (r *fnStudyMaterialRepository) ListAnnotations(ctx context.Context, subsectionID, userID uuid.UUID) ([]models.StudyAnnotation, error) {
+	var annotations []models.StudyAnnotation
+	err := r.db.WithContext(ctx).
+		Where("subsection_id = ? AND user_id = ?", subsectionID, userID).
+		Order("created_at ASC").
+		Find(&annotations).Error
+	return annotations, err
+}
+
+func (r *fnStudyMaterialRepository) CountAnnotations(ctx context.Context, materialID, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	db := r.db.WithContext(ctx)
+	var rows []struct {
+		SubsectionID uuid.UUID
+		Count        int64
+	}
+	err := db.Model(&models.StudyAnnotation{}).
+		Select("subsection_id, COUNT(*) AS count").
+		Where("user_id = ? AND subsection_id IN (?)", userID, materialSubsectionIDs(db, materialID)).
+		Group("subsection_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SubsectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *fnStudyMaterialRepository) GetAnnotation(ctx context.Context, id uuid.UUID) (*models.StudyAnnotation, error) {
+	var annotation models.StudyAnnotation
+	err := r.db.WithContext(ctx).First(&annotation, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &annotation, err
+}
+
+func (r *fnStudyMaterialRepository) CreateAnnotation(ctx context.Context, annotation *models.StudyAnnotation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(annotation).Error
+}
+
+func (r *fnStudyMaterialRepository) UpdateAnnotation(ctx context.Context, annotation *models.StudyAnnotation) error {
+	return r.db.WithContext(ctx).
+		Model(annotation).
+		Select("content", "updated_at").
+		Updates(annotation).Error
+}
+
+func (r *fnStudyMaterialRepository) DeleteAnnotation(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.StudyAnnotation{}).Error
+}
+
+// materialSubsectionIDs selects the IDs of every subsection of a material
+func materialSubsectionIDs(db *gorm.DB, materialID uuid.UUID) *gorm.DB {
+	return db.Model(&models.StudySubsection{}).
+		Select("study_subsections.id").
+		Joins("JOIN study_sections ON study_sections.id = study_subsections.section_id").
+		Where("study_sections.material_id = ?", materialID)
+}
+
+// deleteSubsectionChildren removes the rows pointing to the selected subsections
+func deleteSubsectionChildren(tx *gorm.DB, subsectionIDs func() *gorm.DB) error {
+	for _, model := range []interface{}{&models.StudyProgress{}, &models.StudyAnnotation{}, &models.StudyResource{}} {
+		if err := tx.Where("subsection_id IN (?)", subsectionIDs()).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"server/internal/domain/models"
@@ -18,6 +19,19 @@ func NewFNUserDetailRepository(db *gorm.DB) FNUserDetailRepository {
 	return &fnUserDetailRepository{db: db}
 }
 
+func (r *fnUserDetailRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserDetail, error) {
+	var userDetail models.UserDetail
+	err := r.db.WithContext(ctx).First(&userDetail, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userDetail, nil
+}
+
 func (r *fnUserDetailRepository) GetByNationalID(ctx context.Context, nationalID string) (*models.UserDetail, error) {
 	var userDetail models.UserDetail
 	err := r.db.WithContext(ctx).First(&userDetail, "national_id = ?", nationalID).Error
@@ -33,4 +47,7 @@ func (r *fnUserDetailRepository) GetByNationalID(ctx context.Context, nationalID
 
 func (r *fnUserDetailRepository) Create(ctx context.Context, userDetail *models.UserDetail) error {
 	return r.db.WithContext(ctx).Create(userDetail).Error
-}
\ No newline at end of file
+}
+func (r *fnUserDetailRepository) Update(ctx context.Context, userDetail *models.UserDetail) error {
+	return r.db.WithContext(ctx).Save(userDetail).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"server/internal/domain/models"
+)
+
+type fnUserRepository struct {
+	db *gorm.DB
+}
+
+// NewFNUserRepository creates a new FN user repository
+func NewFNUserRepository(db *gorm.DB) FNUserRepository {
+	return &fnUserRepository{db: db}
+}
+
+func (r *fnUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *fnUserRepository) GetByKeycloakSub(ctx context.Context, sub string) (*models.User, error) {
+	return r.getBy(ctx, "keycloak_sub = ?", sub)
+}
+
+func (r *fnUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.getBy(ctx, "LOWER(email) = LOWER(?)", email)
+}
+
+func (r *fnUserRepository) GetByNationalID(ctx context.Context, nationalID string) (*models.User, error) {
+	return r.getBy(ctx, "national_id = ?", nationalID)
+}
+
+func (r *fnUserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *fnUserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *fnUserRepository) getBy(ctx context.Context, query string, args ...interface{}) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where(query, args...).First(&user).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
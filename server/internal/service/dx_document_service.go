@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 
 	"server/internal/domain/models"
+	"server/internal/dto"
 	"server/internal/repository"
 )
 
@@ -71,4 +73,37 @@ func (s *DocumentService) Update(ctx context.Context, doc *models.Document) (*mo
 
 func (s *DocumentService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
+}
+
+func (s *DocumentService) Restore(ctx context.Context, id uuid.UUID) (*models.Document, error) {
+	doc, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("deleted document not found")
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// Purge permanently removes a deleted document. Only drafts can be purged;
+// generated documents are certificate history.
+func (s *DocumentService) Purge(ctx context.Context, id uuid.UUID) error {
+	doc, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		return fmt.Errorf("deleted document not found")
+	}
+	if doc.Status != dto.DocStatusCreated {
+		return fmt.Errorf("document purge blocked: document '%s' has already been issued", doc.SerialCode)
+	}
+
+	return s.repo.Purge(ctx, id)
 }
\ No newline at end of file
@@ -11,10 +11,11 @@ import (
 
 type NotificationService struct {
 	repo repository.NotificationRepository
+	hub  *NotificationHub
 }
 
-func NewNotificationService(repo repository.NotificationRepository) *NotificationService {
-	return &NotificationService{repo: repo}
+func NewNotificationService(repo repository.NotificationRepository, hub *NotificationHub) *NotificationService {
+	return &NotificationService{repo: repo, hub: hub}
 }
 
 func (s *NotificationService) GetByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
@@ -26,7 +27,7 @@ func (s *NotificationService) GetByUserID(ctx context.Context, userID uuid.UUID,
 	if err != nil {
 		return nil, 0, err
 	}
-	total, err := s.repo.CountUnreadByUserID(ctx, userID)
+	total, err := s.repo.CountByUserID(ctx, userID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -53,20 +54,41 @@ func (s *NotificationService) Create(ctx context.Context, notification *models.N
 	if err := s.repo.Create(ctx, notification); err != nil {
 		return nil, err
 	}
+	s.hub.NotifyCreated(ctx, notification)
 
 	return notification, nil
 }
 
 func (s *NotificationService) MarkAsRead(ctx context.Context, id uuid.UUID) error {
-	return s.repo.MarkAsRead(ctx, id)
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil || notification == nil {
+		return err
+	}
+	if err := s.repo.MarkAsRead(ctx, id); err != nil {
+		return err
+	}
+	s.hub.NotifyUnreadChanged(ctx, notification.UserID)
+	return nil
 }
 
 func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
-	return s.repo.MarkAllAsRead(ctx, userID)
+	if err := s.repo.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+	s.hub.NotifyUnreadChanged(ctx, userID)
+	return nil
 }
 
 func (s *NotificationService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil || notification == nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.hub.NotifyUnreadChanged(ctx, notification.UserID)
+	return nil
 }
 
 func (s *NotificationService) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 
@@ -59,4 +60,51 @@ func (s *UserDetailService) Update(ctx context.Context, detail *models.UserDetai
 
 func (s *UserDetailService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
+}
+
+func (s *UserDetailService) Restore(ctx context.Context, id uuid.UUID) (*models.UserDetail, error) {
+	detail, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		return nil, fmt.Errorf("deleted user detail not found")
+	}
+
+	// the national ID may have been registered again while this one was deleted
+	active, err := s.repo.GetByNationalID(ctx, detail.NationalID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("user detail with national ID '%s' already exists", detail.NationalID)
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// Purge permanently removes a deleted user detail with its event registrations
+// and draft documents. Beneficiaries with issued documents cannot be purged.
+func (s *UserDetailService) Purge(ctx context.Context, id uuid.UUID) error {
+	detail, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if detail == nil {
+		return fmt.Errorf("deleted user detail not found")
+	}
+
+	issued, err := s.repo.CountIssuedDocuments(ctx, id)
+	if err != nil {
+		return err
+	}
+	if issued > 0 {
+		return fmt.Errorf("user detail purge blocked: %d issued documents reference it", issued)
+	}
+
+	return s.repo.Purge(ctx, id)
 }
\ No newline at end of file
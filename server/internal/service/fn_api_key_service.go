@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+const (
+	// apiKeyTag starts every key so leaked keys are easy to spot and grep for
+	apiKeyTag = "csk"
+	// apiKeyTouchInterval limits how often last_used_at is written
+	apiKeyTouchInterval = time.Minute
+)
+
+// FNAPIKeyService defines the interface for integration API keys
+type FNAPIKeyService interface {
+	Create(ctx context.Context, ownerID uuid.UUID, req dto.APIKeyCreateRequest) (*dto.APIKeyCreatedResponse, error)
+	List(ctx context.Context) ([]dto.APIKeyResponse, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Authenticate(ctx context.Context, rawKey string) (*dto.APIKeyPrincipal, error)
+}
+
+type fnAPIKeyService struct {
+	repo     repository.FNAPIKeyRepository
+	userRepo repository.FNUserRepository
+	scopes   map[string]bool
+}
+
+// NewFNAPIKeyService creates a new FN API key service. scopes lists the permissions
+// that may be granted to keys (the matrix without api_keys.manage); there is no
+// wildcard scope.
+func NewFNAPIKeyService(repo repository.FNAPIKeyRepository, userRepo repository.FNUserRepository, scopes []string) FNAPIKeyService {
+	allowed := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		allowed[s] = true
+	}
+
+	return &fnAPIKeyService{repo: repo, userRepo: userRepo, scopes: allowed}
+}
+
+func (s *fnAPIKeyService) Create(ctx context.Context, ownerID uuid.UUID, req dto.APIKeyCreateRequest) (*dto.APIKeyCreatedResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if !s.scopes[scope] {
+			return nil, fmt.Errorf("invalid scope: %s", scope)
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("invalid expires_at: must be in the future")
+	}
+
+	orgUnitPath := ""
+	if req.OrgUnitPath != nil {
+		orgUnitPath = orgunit.Normalize(*req.OrgUnitPath)
+	}
+
+	prefix, secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, fmt.Errorf("error generating key: %w", err)
+	}
+	rawKey := apiKeyTag + "_" + prefix + "_" + secret
+
+	key := &models.APIKey{
+		ID:          uuid.New(),
+		Name:        name,
+		Prefix:      prefix,
+		KeyHash:     hashAPIKey(rawKey),
+		Scopes:      strings.Join(scopes, " "),
+		OrgUnitPath: orgUnitPath,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   ownerID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("error creating api key: %w", err)
+	}
+
+	log.Info().
+		Str("api_key_id", key.ID.String()).
+		Str("name", key.Name).
+		Str("created_by", ownerID.String()).
+		Msg("API key created")
+
+	return &dto.APIKeyCreatedResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+func (s *fnAPIKeyService) List(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api keys: %w", err)
+	}
+
+	items := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		items = append(items, toAPIKeyResponse(&keys[i]))
+	}
+	return items, nil
+}
+
+func (s *fnAPIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching api key: %w", err)
+	}
+	if key == nil {
+		return fmt.Errorf("api key not found")
+	}
+
+	if err := s.repo.Revoke(ctx, id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("error revoking api key: %w", err)
+	}
+
+	log.Info().Str("api_key_id", id.String()).Msg("API key revoked")
+	return nil
+}
+
+// Authenticate resolves a raw key to its principal. Unknown, revoked and expired
+// keys, and keys whose owner no longer exists, all fail with the same error.
+func (s *fnAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*dto.APIKeyPrincipal, error) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	key, err := s.repo.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error fetching api key: %w", err)
+	}
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(rawKey))) != 1 {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	now := time.Now().UTC()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	// the owner's current roles bound the key's scopes
+	owner, err := s.userRepo.GetByID(ctx, key.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching api key owner: %w", err)
+	}
+	if owner == nil {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			log.Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to update api key last use")
+		}
+	}
+
+	return &dto.APIKeyPrincipal{
+		ID:          key.ID,
+		Name:        key.Name,
+		OwnerID:     key.CreatedBy,
+		OwnerRoles:  strings.Fields(owner.Roles),
+		Scopes:      strings.Fields(key.Scopes),
+		OrgUnitPath: key.OrgUnitPath,
+	}, nil
+}
+
+// newAPIKeySecret returns a random lookup prefix and secret
+func newAPIKeySecret() (string, string, error) {
+	buf := make([]byte, 6+32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:6]), base64.RawURLEncoding.EncodeToString(buf[6:]), nil
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKeyResponse(key *models.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      strings.Fields(key.Scopes),
+		OrgUnitPath: key.OrgUnitPath,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		CreatedBy:   key.CreatedBy,
+		CreatedAt:   key.CreatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type memAPIKeyRepo struct {
+	repository.FNAPIKeyRepository
+	keys map[string]*models.APIKey
+}
+
+func (r *memAPIKeyRepo) Create(_ context.Context, key *models.APIKey) error {
+	r.keys[key.Prefix] = key
+	return nil
+}
+
+func (r *memAPIKeyRepo) GetByPrefix(_ context.Context, prefix string) (*models.APIKey, error) {
+	return r.keys[prefix], nil
+}
+
+func (r *memAPIKeyRepo) TouchLastUsed(_ context.Context, _ uuid.UUID, _ time.Time) error {
+	return nil
+}
+
+type stubAPIKeyUserRepo struct {
+	repository.FNUserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *stubAPIKeyUserRepo) GetByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return r.users[id], nil
+}
+
+func TestAPIKeyCreateRejectsUngrantableScopes(t *testing.T) {
+	svc := NewFNAPIKeyService(&memAPIKeyRepo{keys: map[string]*models.APIKey{}}, nil, []string{"catalog.read", "records.purge"})
+	ctx := context.Background()
+
+	for _, scope := range []string{"*", "api_keys.manage", "unknown.permission"} {
+		_, err := svc.Create(ctx, uuid.New(), dto.APIKeyCreateRequest{Name: "erp", Scopes: []string{scope}})
+		if err == nil || !strings.Contains(err.Error(), "invalid scope") {
+			t.Errorf("scope %q: got %v, want invalid scope", scope, err)
+		}
+	}
+
+	if _, err := svc.Create(ctx, uuid.New(), dto.APIKeyCreateRequest{Name: "erp", Scopes: []string{"catalog.read"}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func TestAPIKeyAuthenticateCarriesOwnerRoles(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), Roles: "admin issuer"}
+	users := &stubAPIKeyUserRepo{users: map[uuid.UUID]*models.User{owner.ID: owner}}
+	svc := NewFNAPIKeyService(&memAPIKeyRepo{keys: map[string]*models.APIKey{}}, users, []string{"catalog.read"})
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, owner.ID, dto.APIKeyCreateRequest{Name: "erp", Scopes: []string{"catalog.read"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	principal, err := svc.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if strings.Join(principal.OwnerRoles, " ") != "admin issuer" {
+		t.Fatalf("owner roles = %v", principal.OwnerRoles)
+	}
+
+	// the owner's roles are read on every use, so a demotion applies at once
+	owner.Roles = "beneficiary"
+	if principal, _ = svc.Authenticate(ctx, created.Key); strings.Join(principal.OwnerRoles, " ") != "beneficiary" {
+		t.Fatalf("owner roles after demotion = %v", principal.OwnerRoles)
+	}
+
+	delete(users.users, owner.ID)
+	if _, err := svc.Authenticate(ctx, created.Key); err == nil {
+		t.Fatal("key of a deleted owner authenticated")
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// auditVerifyBatchSize is how many entries Verify loads per query
+const auditVerifyBatchSize = 500
+
+// auditEntityTables maps audited entity types to the table their snapshots are read from
+var auditEntityTables = map[string]string{
+	"user":              "users",
+	"user_detail":       "user_details",
+	"api_key":           "api_keys",
+	"document_type":     "document_types",
+	"document_category": "document_categories",
+	"document_template": "document_templates",
+	"document":          "documents",
+	"document_archive":  "document_archive_jobs",
+	"event":             "events",
+	"event_participant": "event_participants",
+	"notification":      "notifications",
+	"evaluation":        "evaluations",
+	"study_material":    "study_materials",
+	"study_section":     "study_sections",
+	"study_subsection":  "study_subsections",
+}
+
+// auditRedactedFields are never copied into audit snapshots
+var auditRedactedFields = map[string]bool{
+	"key_hash": true,
+	"password": true,
+	"secret":   true,
+}
+
+// auditIgnoredDiffFields change on every write and would only add noise to diffs
+var auditIgnoredDiffFields = map[string]bool{
+	"updated_at": true,
+}
+
+// FNAuditService defines the interface for the audit trail
+type FNAuditService interface {
+	Snapshot(ctx context.Context, entityType, entityID string) (map[string]interface{}, error)
+	Record(ctx context.Context, entry dto.AuditEntry) error
+	List(ctx context.Context, params dto.AuditListQuery) ([]dto.AuditLogResponse, int64, error)
+	Verify(ctx context.Context) (*dto.AuditVerifyResponse, error)
+}
+
+type fnAuditService struct {
+	repo repository.FNAuditLogRepository
+}
+
+// NewFNAuditService creates a new FN audit service
+func NewFNAuditService(repo repository.FNAuditLogRepository) FNAuditService {
+	return &fnAuditService{repo: repo}
+}
+
+func (s *fnAuditService) Snapshot(ctx context.Context, entityType, entityID string) (map[string]interface{}, error) {
+	table, ok := auditEntityTables[entityType]
+	if !ok || entityID == "" {
+		return nil, nil
+	}
+
+	row, err := s.repo.Snapshot(ctx, table, entityID)
+	if err != nil || row == nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if auditRedactedFields[key] {
+			continue
+		}
+		switch v := value.(type) {
+		case [16]byte:
+			snapshot[key] = uuid.UUID(v).String()
+		case []byte:
+			snapshot[key] = string(v)
+		default:
+			snapshot[key] = v
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *fnAuditService) Record(ctx context.Context, entry dto.AuditEntry) error {
+	action := strings.ToUpper(strings.TrimSpace(entry.Action))
+	if action == "" {
+		return fmt.Errorf("action is required")
+	}
+
+	log := &models.AuditLog{
+		ActorID:       entry.ActorID,
+		ActorUsername: entry.ActorUsername,
+		ActorType:     entry.ActorType,
+		Method:        entry.Method,
+		Route:         entry.Route,
+		Path:          truncate(entry.Path, 500),
+		EntityType:    entry.EntityType,
+		EntityID:      entry.EntityID,
+		Action:        action,
+		Before:        marshalAuditJSON(entry.Before),
+		After:         marshalAuditJSON(entry.After),
+		Diff:          marshalAuditJSON(auditDiff(entry.Before, entry.After)),
+		RequestID:     entry.RequestID,
+		IPAddress:     entry.IPAddress,
+		StatusCode:    entry.StatusCode,
+		Outcome:       auditOutcome(entry.StatusCode),
+		// postgres keeps microseconds; truncating keeps the hash reproducible after a round trip
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	if err := s.repo.Append(ctx, log, auditHash); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *fnAuditService) List(ctx context.Context, params dto.AuditListQuery) ([]dto.AuditLogResponse, int64, error) {
+	entries, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	items := make([]dto.AuditLogResponse, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, dto.AuditLogResponse{
+			ID:            e.ID,
+			Seq:           e.Seq,
+			ActorID:       e.ActorID,
+			ActorUsername: e.ActorUsername,
+			ActorType:     e.ActorType,
+			Method:        e.Method,
+			Route:         e.Route,
+			Path:          e.Path,
+			EntityType:    e.EntityType,
+			EntityID:      e.EntityID,
+			Action:        e.Action,
+			Before:        rawAuditJSON(e.Before),
+			After:         rawAuditJSON(e.After),
+			Diff:          rawAuditJSON(e.Diff),
+			RequestID:     e.RequestID,
+			IPAddress:     e.IPAddress,
+			StatusCode:    e.StatusCode,
+			Outcome:       e.Outcome,
+			PrevHash:      e.PrevHash,
+			Hash:          e.Hash,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+	return items, total, nil
+}
+
+// Verify walks the whole chain checking sequence gaps, previous-hash links and
+// entry hashes. Removing or editing any entry but the last breaks the chain;
+// keep LastSeq/LastHash somewhere else to also detect truncation.
+func (s *fnAuditService) Verify(ctx context.Context) (*dto.AuditVerifyResponse, error) {
+	result := &dto.AuditVerifyResponse{Valid: true}
+
+	broken := func(seq int64, reason string) (*dto.AuditVerifyResponse, error) {
+		result.Valid = false
+		result.BrokenAtSeq = &seq
+		result.Reason = reason
+		return result, nil
+	}
+
+	for {
+		entries, err := s.repo.ListAfterSeq(ctx, result.LastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit entries: %w", err)
+		}
+
+		for i := range entries {
+			e := &entries[i]
+			if e.Seq != result.LastSeq+1 {
+				return broken(result.LastSeq+1, fmt.Sprintf("missing entries before seq %d", e.Seq))
+			}
+			if e.PrevHash != result.LastHash {
+				return broken(e.Seq, "previous hash does not match the preceding entry")
+			}
+			if auditHash(e) != e.Hash {
+				return broken(e.Seq, "entry hash does not match its content")
+			}
+
+			result.Checked++
+			result.LastSeq = e.Seq
+			result.LastHash = e.Hash
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// -- helpers
+
+// auditHashInput fixes the field order hashed for each entry
+type auditHashInput struct {
+	Seq           int64  `json:"seq"`
+	PrevHash      string `json:"prev_hash"`
+	ActorID       string `json:"actor_id"`
+	ActorUsername string `json:"actor_username"`
+	ActorType     string `json:"actor_type"`
+	Method        string `json:"method"`
+	Route         string `json:"route"`
+	Path          string `json:"path"`
+	EntityType    string `json:"entity_type"`
+	EntityID      string `json:"entity_id"`
+	Action        string `json:"action"`
+	Before        string `json:"before"`
+	After         string `json:"after"`
+	Diff          string `json:"diff"`
+	RequestID     string `json:"request_id"`
+	IPAddress     string `json:"ip_address"`
+	StatusCode    int    `json:"status_code"`
+	Outcome       string `json:"outcome"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// auditHash returns the sha256 of an entry chained to its previous hash
+func auditHash(e *models.AuditLog) string {
+	input := auditHashInput{
+		Seq:           e.Seq,
+		PrevHash:      e.PrevHash,
+		ActorUsername: e.ActorUsername,
+		ActorType:     e.ActorType,
+		Method:        e.Method,
+		Route:         e.Route,
+		Path:          e.Path,
+		EntityType:    e.EntityType,
+		EntityID:      e.EntityID,
+		Action:        e.Action,
+		Before:        derefString(e.Before),
+		After:         derefString(e.After),
+		Diff:          derefString(e.Diff),
+		RequestID:     e.RequestID,
+		IPAddress:     e.IPAddress,
+		StatusCode:    e.StatusCode,
+		Outcome:       e.Outcome,
+		CreatedAt:     e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if e.ActorID != nil {
+		input.ActorID = e.ActorID.String()
+	}
+
+	payload, _ := json.Marshal(input)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditDiff lists the fields that changed between two snapshots as {before, after}
+func auditDiff(before, after map[string]interface{}) map[string]interface{} {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	diff := map[string]interface{}{}
+	compare := func(key string) {
+		if auditIgnoredDiffFields[key] {
+			return
+		}
+		if _, done := diff[key]; done {
+			return
+		}
+		b, _ := json.Marshal(before[key])
+		a, _ := json.Marshal(after[key])
+		if string(a) != string(b) {
+			diff[key] = map[string]interface{}{"before": before[key], "after": after[key]}
+		}
+	}
+	for key := range before {
+		compare(key)
+	}
+	for key := range after {
+		compare(key)
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func auditOutcome(status int) string {
+	switch {
+	case status == 401 || status == 403:
+		return dto.AuditOutcomeDenied
+	case status >= 400:
+		return dto.AuditOutcomeFailure
+	default:
+		return dto.AuditOutcomeSuccess
+	}
+}
+
+func marshalAuditJSON(v map[string]interface{}) *string {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
+func rawAuditJSON(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// memAuditLogRepo keeps the chain in memory, appending the way the postgres
+// repository does under its advisory lock
+type memAuditLogRepo struct {
+	repository.FNAuditLogRepository
+	entries []models.AuditLog
+}
+
+func (r *memAuditLogRepo) Append(_ context.Context, entry *models.AuditLog, hash func(*models.AuditLog) string) error {
+	if n := len(r.entries); n > 0 {
+		entry.Seq = r.entries[n-1].Seq + 1
+		entry.PrevHash = r.entries[n-1].Hash
+	} else {
+		entry.Seq = 1
+	}
+	entry.Hash = hash(entry)
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *memAuditLogRepo) ListAfterSeq(_ context.Context, seq int64, limit int) ([]models.AuditLog, error) {
+	var out []models.AuditLog
+	for _, e := range r.entries {
+		if e.Seq > seq && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestAuditVerify(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name       string
+		tamper     func(entries []models.AuditLog) []models.AuditLog
+		wantBroken int64
+		wantReason string
+	}{
+		{"intact chain", nil, 0, ""},
+		{"deleted entry", func(e []models.AuditLog) []models.AuditLog {
+			return slices.Delete(e, 1, 2)
+		}, 2, "missing entries before seq 3"},
+		{"edited entry", func(e []models.AuditLog) []models.AuditLog {
+			e[2].Action = "DELETE"
+			return e
+		}, 3, "entry hash does not match its content"},
+		{"broken prev_hash", func(e []models.AuditLog) []models.AuditLog {
+			e[3].PrevHash = e[1].Hash
+			return e
+		}, 4, "previous hash does not match the preceding entry"},
+		{"edited entry resealed", func(e []models.AuditLog) []models.AuditLog {
+			e[1].StatusCode = http.StatusOK
+			e[1].Hash = auditHash(&e[1])
+			return e
+		}, 3, "previous hash does not match the preceding entry"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &memAuditLogRepo{}
+			svc := NewFNAuditService(repo)
+			for i := range 5 {
+				entry := dto.AuditEntry{
+					Method:     http.MethodPut,
+					Path:       fmt.Sprintf("/api/v1/fn/events/%d", i),
+					EntityType: "events",
+					Action:     "update",
+					StatusCode: http.StatusForbidden,
+				}
+				if err := svc.Record(ctx, entry); err != nil {
+					t.Fatalf("Record: %v", err)
+				}
+			}
+			if tc.tamper != nil {
+				repo.entries = tc.tamper(repo.entries)
+			}
+
+			result, err := svc.Verify(ctx)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if tc.wantBroken == 0 {
+				if !result.Valid || result.Checked != 5 || result.LastHash != repo.entries[4].Hash {
+					t.Fatalf("result = %+v", result)
+				}
+				return
+			}
+			if result.Valid || result.BrokenAtSeq == nil || *result.BrokenAtSeq != tc.wantBroken || result.Reason != tc.wantReason {
+				t.Fatalf("result = %+v, want broken at %d (%s)", result, tc.wantBroken, tc.wantReason)
+			}
+		})
+	}
+}
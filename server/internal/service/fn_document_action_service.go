@@ -37,11 +37,15 @@ type FNDocumentActionService interface {
 }
 
 type fnDocumentActionService struct {
-	docRepo        repository.FNDocumentRepository
-	docPDFRepo     repository.FNDocumentPDFRepository
-	eventRepo      repository.FNEventRepository
-	userDetailRepo repository.FNUserDetailRepository
-	natsConn       *nats.Conn
+	docRepo         repository.FNDocumentRepository
+	docPDFRepo      repository.FNDocumentPDFRepository
+	eventRepo       repository.FNEventRepository
+	userDetailRepo  repository.FNUserDetailRepository
+	revocationRepo  repository.FNDocumentRevocationRepository
+	participantRepo repository.FNEventParticipantRepository
+	userRepo        repository.FNUserRepository
+	studyRepo       repository.FNStudyMaterialRepository
+	natsConn        *nats.Conn
 }
 
 // NewFNDocumentActionService creates a new FN document action service
@@ -50,14 +54,22 @@ func NewFNDocumentActionService(
 	docPDFRepo repository.FNDocumentPDFRepository,
 	eventRepo repository.FNEventRepository,
 	userDetailRepo repository.FNUserDetailRepository,
+	revocationRepo repository.FNDocumentRevocationRepository,
+	participantRepo repository.FNEventParticipantRepository,
+	userRepo repository.FNUserRepository,
+	studyRepo repository.FNStudyMaterialRepository,
 	natsConn *nats.Conn,
 ) FNDocumentActionService {
 	return &fnDocumentActionService{
-		docRepo:        docRepo,
-		docPDFRepo:     docPDFRepo,
-		eventRepo:      eventRepo,
-		userDetailRepo: userDetailRepo,
-		natsConn:       natsConn,
+		docRepo:         docRepo,
+		docPDFRepo:      docPDFRepo,
+		eventRepo:       eventRepo,
+		userDetailRepo:  userDetailRepo,
+		revocationRepo:  revocationRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		studyRepo:       studyRepo,
+		natsConn:        natsConn,
 	}
 }
 
@@ -83,9 +95,11 @@ func (s *fnDocumentActionService) ExecuteAction(ctx context.Context, userID uuid
 	case "gen_doc":
 		return s.executeGenDoc(ctx, userID, event, req)
 	case "doc_reject":
-		return s.executeDocReject(ctx, event, req)
+		return s.executeDocReject(ctx, userID, event, req)
 	case "doc_renew":
-		return s.executeDocRenew(ctx, event, req)
+		return s.executeDocRenew(ctx, userID, event, req)
+	case "doc_reissue":
+		return s.executeDocReissue(ctx, userID, event, req)
 	default:
 		return nil, fmt.Errorf("unknown action: %s", req.Action)
 	}
@@ -378,8 +392,50 @@ func (s *fnDocumentActionService) executeGenDoc(ctx context.Context, userID uuid
 			continue
 		}
 
+		templateData := p.TemplateData
+		if event.EvaluationID != nil {
+			// the event requires an evaluation: only participants who passed it get a certificate
+			participant, err := s.participantRepo.GetByEventAndUserDetail(ctx, event.ID, userDetailID)
+			if err != nil || participant == nil || !participant.CertificateEligible {
+				errMsg := "participant not eligible: the event evaluation has not been passed"
+				if err != nil {
+					errMsg = fmt.Sprintf("error checking participant eligibility: %v", err)
+				}
+				results = append(results, dto.DocumentActionResultItem{
+					UserDetailID: userDetailID,
+					DocumentID:   doc.ID,
+					SerialCode:   doc.SerialCode,
+					Status:       doc.Status,
+					Error:        &errMsg,
+				})
+				failedCount++
+				continue
+			}
+			templateData = withFinalScore(templateData, participant.FinalScore)
+		}
+
+		if event.StudyMaterialID != nil {
+			// the event requires study material: only participants who completed it get a certificate
+			completed, err := s.studyCompleted(ctx, event, userDetailID)
+			if err != nil || !completed {
+				errMsg := "participant not eligible: the study material has not been completed"
+				if err != nil {
+					errMsg = fmt.Sprintf("error checking study progress: %v", err)
+				}
+				results = append(results, dto.DocumentActionResultItem{
+					UserDetailID: userDetailID,
+					DocumentID:   doc.ID,
+					SerialCode:   doc.SerialCode,
+					Status:       doc.Status,
+					Error:        &errMsg,
+				})
+				failedCount++
+				continue
+			}
+		}
+
 		validDocs = append(validDocs, doc)
-		templateDataMap[doc.ID] = p.TemplateData
+		templateDataMap[doc.ID] = templateData
 	}
 
 	if len(validDocs) == 0 {
@@ -477,7 +533,12 @@ func (s *fnDocumentActionService) executeGenDoc(ctx context.Context, userID uuid
 	}, nil
 }
 
-func (s *fnDocumentActionService) executeDocReject(ctx context.Context, event *models.Event, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
+func (s *fnDocumentActionService) executeDocReject(ctx context.Context, userID uuid.UUID, event *models.Event, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
+	reasonCode, reason, err := parseRevocationReason(req.ReasonCode, req.Reason, dto.RevocationReasonUnspecified)
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]dto.DocumentActionResultItem, 0, len(req.Participants))
 	processedCount := 0
 	failedCount := 0
@@ -533,8 +594,8 @@ func (s *fnDocumentActionService) executeDocReject(ctx context.Context, event *m
 			continue
 		}
 
-		if err := s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusRejected); err != nil {
-			errMsg := fmt.Sprintf("error updating status: %v", err)
+		supersededByID, errMsg := s.resolveSupersedingDocument(ctx, doc, p.SupersededByID)
+		if errMsg != "" {
 			results = append(results, dto.DocumentActionResultItem{
 				UserDetailID: userDetailID,
 				DocumentID:   doc.ID,
@@ -546,6 +607,29 @@ func (s *fnDocumentActionService) executeDocReject(ctx context.Context, event *m
 			continue
 		}
 
+		revocation := &models.DocumentRevocation{
+			DocumentID:     doc.ID,
+			ReasonCode:     reasonCode,
+			Reason:         reason,
+			SupersededByID: supersededByID,
+			RevokedBy:      userID,
+			RevokedAt:      time.Now().UTC(),
+		}
+		if err := s.revocationRepo.Revoke(ctx, revocation); err != nil {
+			errMsg := fmt.Sprintf("error revoking document: %v", err)
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: userDetailID,
+				DocumentID:   doc.ID,
+				SerialCode:   doc.SerialCode,
+				Status:       doc.Status,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		publishDocumentStatusChanged(s.natsConn, doc, doc.Status, dto.DocStatusRejected, &userID)
+
 		results = append(results, dto.DocumentActionResultItem{
 			UserDetailID: userDetailID,
 			DocumentID:   doc.ID,
@@ -565,7 +649,7 @@ func (s *fnDocumentActionService) executeDocReject(ctx context.Context, event *m
 	}, nil
 }
 
-func (s *fnDocumentActionService) executeDocRenew(ctx context.Context, event *models.Event, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
+func (s *fnDocumentActionService) executeDocRenew(ctx context.Context, userID uuid.UUID, event *models.Event, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
 	results := make([]dto.DocumentActionResultItem, 0, len(req.Participants))
 	processedCount := 0
 	failedCount := 0
@@ -597,6 +681,9 @@ func (s *fnDocumentActionService) executeDocRenew(ctx context.Context, event *mo
 
 		if !s.canTransitionTo(doc.Status, dto.DocStatusRenew) {
 			errMsg := fmt.Sprintf("cannot renew document from status '%s'", doc.Status)
+			if doc.Status == dto.DocStatusPDFCompleted {
+				errMsg = "document already issued, use doc_reissue to issue a new version"
+			}
 			results = append(results, dto.DocumentActionResultItem{
 				UserDetailID: userDetailID,
 				DocumentID:   doc.ID,
@@ -621,6 +708,24 @@ func (s *fnDocumentActionService) executeDocRenew(ctx context.Context, event *mo
 			continue
 		}
 
+		// a renewed document leaves the revocation list
+		if doc.Status == dto.DocStatusRejected {
+			if err := s.revocationRepo.Lift(ctx, doc.ID, userID, time.Now().UTC()); err != nil {
+				errMsg := fmt.Sprintf("error lifting revocation: %v", err)
+				results = append(results, dto.DocumentActionResultItem{
+					UserDetailID: userDetailID,
+					DocumentID:   doc.ID,
+					SerialCode:   doc.SerialCode,
+					Status:       dto.DocStatusRenew,
+					Error:        &errMsg,
+				})
+				failedCount++
+				continue
+			}
+		}
+
+		publishDocumentStatusChanged(s.natsConn, doc, doc.Status, dto.DocStatusRenew, &userID)
+
 		results = append(results, dto.DocumentActionResultItem{
 			UserDetailID: userDetailID,
 			DocumentID:   doc.ID,
@@ -640,6 +745,138 @@ func (s *fnDocumentActionService) executeDocRenew(ctx context.Context, event *mo
 	}, nil
 }
 
+func (s *fnDocumentActionService) executeDocReissue(ctx context.Context, userID uuid.UUID, event *models.Event, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
+	reasonCode, reason, err := parseRevocationReason(req.ReasonCode, req.Reason, dto.RevocationReasonSuperseded)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	results := make([]dto.DocumentActionResultItem, 0, len(req.Participants))
+	processedCount := 0
+	failedCount := 0
+
+	for _, p := range req.Participants {
+		userDetailID, err := uuid.Parse(p.UserDetailID)
+		if err != nil {
+			errMsg := "invalid user_detail_id"
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: uuid.Nil,
+				Status:       dto.DocStatusPDFFailed,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		doc, err := s.docRepo.GetByEventAndUserDetail(ctx, event.ID, userDetailID)
+		if err != nil || doc == nil {
+			errMsg := "document not found"
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: userDetailID,
+				Status:       dto.DocStatusPDFFailed,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		// only issued (or already revoked) certificates get a new version;
+		// drafts are simply regenerated with gen_doc
+		if doc.Status != dto.DocStatusPDFCompleted && doc.Status != dto.DocStatusRejected {
+			errMsg := fmt.Sprintf("cannot reissue document from status '%s'", doc.Status)
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: userDetailID,
+				DocumentID:   doc.ID,
+				SerialCode:   doc.SerialCode,
+				Status:       doc.Status,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		serialCode, err := s.generateSerialCode(ctx, event)
+		if err != nil {
+			errMsg := fmt.Sprintf("error generating serial code: %v", err)
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: userDetailID,
+				DocumentID:   doc.ID,
+				SerialCode:   doc.SerialCode,
+				Status:       doc.Status,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		next := &models.Document{
+			ID:                     uuid.New(),
+			UserDetailID:           userDetailID,
+			EventID:                &event.ID,
+			TemplateID:             event.TemplateID,
+			SerialCode:             serialCode,
+			VerificationCode:       s.generateVerificationCode(),
+			IssueDate:              now,
+			Version:                doc.Version + 1,
+			PreviousDocumentID:     &doc.ID,
+			Status:                 dto.DocStatusCreated,
+			DigitalSignatureStatus: "PENDING",
+			RequiredSignatures:     doc.RequiredSignatures,
+			SignedSignatures:       0,
+			CreatedBy:              userID,
+			CreatedAt:              now,
+			UpdatedAt:              now,
+		}
+
+		var revocation *models.DocumentRevocation
+		if doc.Status != dto.DocStatusRejected {
+			revocation = &models.DocumentRevocation{
+				DocumentID:     doc.ID,
+				ReasonCode:     reasonCode,
+				Reason:         reason,
+				SupersededByID: &next.ID,
+				RevokedBy:      userID,
+				RevokedAt:      now,
+			}
+		}
+
+		if err := s.docRepo.Reissue(ctx, doc, next, revocation); err != nil {
+			errMsg := fmt.Sprintf("error reissuing document: %v", err)
+			results = append(results, dto.DocumentActionResultItem{
+				UserDetailID: userDetailID,
+				DocumentID:   doc.ID,
+				SerialCode:   doc.SerialCode,
+				Status:       doc.Status,
+				Error:        &errMsg,
+			})
+			failedCount++
+			continue
+		}
+
+		if revocation != nil {
+			publishDocumentStatusChanged(s.natsConn, doc, doc.Status, dto.DocStatusRejected, &userID)
+		}
+
+		results = append(results, dto.DocumentActionResultItem{
+			UserDetailID: userDetailID,
+			DocumentID:   next.ID,
+			SerialCode:   next.SerialCode,
+			Status:       dto.DocStatusCreated,
+		})
+		processedCount++
+	}
+
+	return &dto.DocumentActionResponse{
+		Action:            req.Action,
+		EventID:           event.ID,
+		TotalParticipants: len(req.Participants),
+		ProcessedCount:    processedCount,
+		FailedCount:       failedCount,
+		Results:           results,
+	}, nil
+}
+
 func (s *fnDocumentActionService) ProcessPDFBatchCompleted(ctx context.Context, payload dto.PDFBatchCompletedPayload) error {
 	pdfJobID, err := uuid.Parse(payload.PDFJobID)
 	if err != nil {
@@ -647,6 +884,7 @@ func (s *fnDocumentActionService) ProcessPDFBatchCompleted(ctx context.Context,
 	}
 
 	now := time.Now().UTC()
+	finished := dto.PDFBatchFinishedPayload{PDFJobID: pdfJobID, TotalItems: len(payload.Items)}
 
 	for _, item := range payload.Items {
 		userDetailID, err := uuid.Parse(item.UserID)
@@ -658,11 +896,15 @@ func (s *fnDocumentActionService) ProcessPDFBatchCompleted(ctx context.Context,
 		if err != nil || doc == nil {
 			continue
 		}
+		if finished.EventID == nil {
+			finished.EventID = doc.EventID
+		}
 
 		if item.Status == "completed" && item.Data != nil {
 			fileID, err := uuid.Parse(item.Data.FileID)
 			if err != nil {
 				_ = s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusPDFFailed)
+				finished.FailedCount++
 				continue
 			}
 
@@ -682,15 +924,24 @@ func (s *fnDocumentActionService) ProcessPDFBatchCompleted(ctx context.Context,
 
 			if err := s.docPDFRepo.Create(ctx, docPDF); err != nil {
 				_ = s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusPDFFailed)
+				finished.FailedCount++
 				continue
 			}
 
-			_ = s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusPDFCompleted)
+			if err := s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusPDFCompleted); err != nil {
+				finished.FailedCount++
+				continue
+			}
+			finished.SuccessCount++
+			publishDocumentStatusChanged(s.natsConn, doc, doc.Status, dto.DocStatusPDFCompleted, nil)
 		} else {
 			_ = s.docRepo.UpdateStatus(ctx, doc.ID, dto.DocStatusPDFFailed)
+			finished.FailedCount++
 		}
 	}
 
+	publishDomainEvent(s.natsConn, dto.DomainEventPDFBatchFinished, finished)
+
 	return nil
 }
 
@@ -705,12 +956,25 @@ func (s *fnDocumentActionService) ProcessPDFBatchFailed(ctx context.Context, pay
 		return fmt.Errorf("error fetching documents: %w", err)
 	}
 
+	finished := dto.PDFBatchFinishedPayload{PDFJobID: pdfJobID, TotalItems: len(docs), FailedCount: len(docs)}
+	if payload.Message != "" {
+		finished.Error = &payload.Message
+	}
+
 	docIDs := make([]uuid.UUID, 0, len(docs))
 	for _, doc := range docs {
 		docIDs = append(docIDs, doc.ID)
+		if finished.EventID == nil {
+			finished.EventID = doc.EventID
+		}
 	}
 
-	return s.docRepo.BulkUpdateStatus(ctx, docIDs, dto.DocStatusPDFFailed)
+	if err := s.docRepo.BulkUpdateStatus(ctx, docIDs, dto.DocStatusPDFFailed); err != nil {
+		return err
+	}
+
+	publishDomainEvent(s.natsConn, dto.DomainEventPDFBatchFinished, finished)
+	return nil
 }
 
 func (s *fnDocumentActionService) GetByID(ctx context.Context, id uuid.UUID) (*dto.DocumentDetailResponse, error) {
@@ -838,6 +1102,49 @@ func (s *fnDocumentActionService) canTransitionTo(currentStatus, targetStatus st
 	return false
 }
 
+// parseRevocationReason validates the reason code of doc_reject / doc_reissue and forced participant removals
+func parseRevocationReason(code, text *string, defaultCode string) (string, *string, error) {
+	reasonCode := defaultCode
+	if code != nil && *code != "" {
+		reasonCode = strings.ToUpper(strings.TrimSpace(*code))
+	}
+	if !dto.RevocationReasonCodes[reasonCode] {
+		return "", nil, fmt.Errorf("invalid reason_code: %s", reasonCode)
+	}
+	var reason *string
+	if text != nil && strings.TrimSpace(*text) != "" {
+		trimmed := strings.TrimSpace(*text)
+		reason = &trimmed
+	}
+	return reasonCode, reason, nil
+}
+
+// resolveSupersedingDocument validates the optional document that replaces a revoked one.
+// It returns an error message for the participant result instead of an error.
+func (s *fnDocumentActionService) resolveSupersedingDocument(ctx context.Context, doc *models.Document, rawID *string) (*uuid.UUID, string) {
+	if rawID == nil || *rawID == "" {
+		return nil, ""
+	}
+	id, err := uuid.Parse(*rawID)
+	if err != nil {
+		return nil, "invalid superseded_by_id"
+	}
+	if id == doc.ID {
+		return nil, "document cannot supersede itself"
+	}
+	replacement, err := s.docRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Sprintf("error fetching superseding document: %v", err)
+	}
+	if replacement == nil {
+		return nil, "superseding document not found"
+	}
+	if replacement.Status == dto.DocStatusRejected {
+		return nil, "superseding document is rejected"
+	}
+	return &id, ""
+}
+
 func (s *fnDocumentActionService) toDetailResponse(doc *models.Document) *dto.DocumentDetailResponse {
 	resp := &dto.DocumentDetailResponse{
 		ID:                     doc.ID,
@@ -849,6 +1156,9 @@ func (s *fnDocumentActionService) toDetailResponse(doc *models.Document) *dto.Do
 		SignedSignatures:       doc.SignedSignatures,
 		IssueDate:              doc.IssueDate,
 		SignedAt:               doc.SignedAt,
+		Version:                doc.Version,
+		PreviousDocumentID:     doc.PreviousDocumentID,
+		ReplacedByID:           doc.ReplacedByID,
 		PDFJobID:               doc.PdfJobID,
 		CreatedBy:              doc.CreatedBy,
 		CreatedAt:              doc.CreatedAt,
@@ -894,5 +1204,60 @@ func (s *fnDocumentActionService) toDetailResponse(doc *models.Document) *dto.Do
 		})
 	}
 
+	if len(doc.Revocations) > 0 {
+		resp.Revocation = toRevocationResponse(&doc.Revocations[0])
+	}
+
+	return resp
+}
+
+func toRevocationResponse(rev *models.DocumentRevocation) *dto.DocumentRevocationResponse {
+	resp := &dto.DocumentRevocationResponse{
+		ID:         rev.ID,
+		ReasonCode: rev.ReasonCode,
+		Reason:     rev.Reason,
+		RevokedBy:  rev.RevokedBy,
+		RevokedAt:  rev.RevokedAt,
+	}
+	if rev.SupersededBy != nil {
+		resp.SupersededBy = &dto.DocumentRefEmbedded{
+			ID:         rev.SupersededBy.ID,
+			SerialCode: rev.SupersededBy.SerialCode,
+		}
+	}
 	return resp
+}
+
+// studyCompleted reports whether the participant reached the event's study material
+// minimum. Progress belongs to the account sharing the beneficiary's national ID, so
+// beneficiaries without an account have none.
+func (s *fnDocumentActionService) studyCompleted(ctx context.Context, event *models.Event, userDetailID uuid.UUID) (bool, error) {
+	userDetail, err := s.userDetailRepo.GetByID(ctx, userDetailID)
+	if err != nil || userDetail == nil {
+		return false, err
+	}
+	user, err := s.userRepo.GetByNationalID(ctx, userDetail.NationalID)
+	if err != nil || user == nil {
+		return false, err
+	}
+	_, met, err := studyRequirement(ctx, s.studyRepo, event, user.ID)
+	return met, err
+}
+
+// withFinalScore fills the final score template field from the participant's
+// evaluation unless the request already sets it
+func withFinalScore(templateData map[string]string, finalScore *float64) map[string]string {
+	if finalScore == nil {
+		return templateData
+	}
+	if _, ok := templateData[dto.TemplateFieldFinalScore]; ok {
+		return templateData
+	}
+
+	data := make(map[string]string, len(templateData)+1)
+	for k, v := range templateData {
+		data[k] = v
+	}
+	data[dto.TemplateFieldFinalScore] = fmt.Sprintf("%.2f", *finalScore)
+	return data
 }
\ No newline at end of file
@@ -0,0 +1,550 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/client/filesvc"
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// archive job defaults
+const (
+	archiveJobTimeout     = 2 * time.Hour
+	archiveProgressEvery  = 10
+	archiveMaxConcurrency = 2
+	archiveManifestName   = "manifest.csv"
+)
+
+var archiveManifestColumns = []string{
+	"serial_code",
+	"verification_code",
+	"national_id",
+	"full_name",
+	"file_name",
+	"file_id",
+	"size_bytes",
+	"sha256",
+	"expected_sha256",
+	"status",
+}
+
+// DocumentArchiveConfig holds storage settings for bulk certificate archives
+type DocumentArchiveConfig struct {
+	Dir       string
+	TTL       time.Duration
+	SyncLimit int
+}
+
+// FNDocumentArchiveService defines the interface for bulk ZIP downloads of certificates
+type FNDocumentArchiveService interface {
+	PrepareArchive(ctx context.Context, req dto.DocumentArchiveRequest) (*dto.DocumentArchiveInfo, error)
+	WriteArchive(ctx context.Context, req dto.DocumentArchiveRequest, w io.Writer) (*dto.DocumentArchiveResult, error)
+	CreateJob(ctx context.Context, userID uuid.UUID, req dto.DocumentArchiveRequest) (*dto.DocumentArchiveJobResponse, error)
+	GetJob(ctx context.Context, userID, id uuid.UUID) (*dto.DocumentArchiveJobResponse, error)
+	OpenJobFile(ctx context.Context, userID, id uuid.UUID) (*os.File, string, error)
+	PurgeExpired(ctx context.Context) (int, error)
+	FailInterrupted(ctx context.Context) (int64, error)
+}
+
+type fnDocumentArchiveService struct {
+	archiveRepo repository.FNDocumentArchiveRepository
+	docRepo     repository.FNDocumentRepository
+	eventRepo   repository.FNEventRepository
+	fileSvc     *filesvc.Client
+	cfg         DocumentArchiveConfig
+	slots       chan struct{}
+}
+
+// NewFNDocumentArchiveService creates a new FN document archive service
+func NewFNDocumentArchiveService(
+	archiveRepo repository.FNDocumentArchiveRepository,
+	docRepo repository.FNDocumentRepository,
+	eventRepo repository.FNEventRepository,
+	fileSvc *filesvc.Client,
+	cfg DocumentArchiveConfig,
+) FNDocumentArchiveService {
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(os.TempDir(), "cert-archives")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.SyncLimit <= 0 {
+		cfg.SyncLimit = 200
+	}
+
+	return &fnDocumentArchiveService{
+		archiveRepo: archiveRepo,
+		docRepo:     docRepo,
+		eventRepo:   eventRepo,
+		fileSvc:     fileSvc,
+		cfg:         cfg,
+		slots:       make(chan struct{}, archiveMaxConcurrency),
+	}
+}
+
+// PrepareArchive validates a direct download and resolves its file name so headers
+// can be sent before the archive is streamed. Large selections must use an async job.
+func (s *fnDocumentArchiveService) PrepareArchive(ctx context.Context, req dto.DocumentArchiveRequest) (*dto.DocumentArchiveInfo, error) {
+	docs, fileName, err := s.loadDocuments(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents found for the selection")
+	}
+	if len(docs) > s.cfg.SyncLimit {
+		return nil, fmt.Errorf("direct download blocked: %d documents exceed the limit of %d, request an async archive", len(docs), s.cfg.SyncLimit)
+	}
+
+	return &dto.DocumentArchiveInfo{
+		FileName:   fileName,
+		TotalItems: len(docs),
+	}, nil
+}
+
+// WriteArchive streams the ZIP archive of the selected certificates into w
+func (s *fnDocumentArchiveService) WriteArchive(ctx context.Context, req dto.DocumentArchiveRequest, w io.Writer) (*dto.DocumentArchiveResult, error) {
+	docs, _, err := s.loadDocuments(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.writeZip(ctx, docs, w, nil)
+}
+
+// CreateJob registers an async archive job and builds it in the background
+func (s *fnDocumentArchiveService) CreateJob(ctx context.Context, userID uuid.UUID, req dto.DocumentArchiveRequest) (*dto.DocumentArchiveJobResponse, error) {
+	docs, fileName, err := s.loadDocuments(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents found for the selection")
+	}
+
+	// expired archives are cleaned up opportunistically whenever a new one is requested
+	if n, err := s.PurgeExpired(ctx); err != nil {
+		log.Warn().Err(err).Msg("error purging expired archives")
+	} else if n > 0 {
+		log.Info().Int("purged", n).Msg("expired archives purged")
+	}
+
+	eventID, pdfJobID, _ := parseArchiveRequest(req)
+
+	job := &models.DocumentArchiveJob{
+		EventID:     eventID,
+		PdfJobID:    pdfJobID,
+		Status:      dto.ArchiveStatusPending,
+		TotalItems:  len(docs),
+		FileName:    fileName,
+		RequestedBy: userID,
+	}
+
+	if err := s.archiveRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("error creating archive job: %w", err)
+	}
+
+	// the request context ends with the response, so the job runs detached
+	go s.runJob(job.ID, docs)
+
+	return s.toJobResponse(job), nil
+}
+
+// GetJob returns the status of an archive job requested by userID
+func (s *fnDocumentArchiveService) GetJob(ctx context.Context, userID, id uuid.UUID) (*dto.DocumentArchiveJobResponse, error) {
+	job, err := s.getOwnJob(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toJobResponse(job), nil
+}
+
+// OpenJobFile opens the ZIP of a completed archive job requested by userID. The caller must close the file.
+func (s *fnDocumentArchiveService) OpenJobFile(ctx context.Context, userID, id uuid.UUID) (*os.File, string, error) {
+	job, err := s.getOwnJob(ctx, userID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if job.Status != dto.ArchiveStatusCompleted {
+		return nil, "", fmt.Errorf("archive download blocked: job is %s", job.Status)
+	}
+	if job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now().UTC()) {
+		return nil, "", fmt.Errorf("archive file not found: expired")
+	}
+
+	f, err := os.Open(job.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", fmt.Errorf("archive file not found")
+		}
+		return nil, "", fmt.Errorf("error opening archive file: %w", err)
+	}
+
+	return f, job.FileName, nil
+}
+
+// PurgeExpired removes expired archive files and their job rows
+func (s *fnDocumentArchiveService) PurgeExpired(ctx context.Context) (int, error) {
+	jobs, err := s.archiveRepo.GetExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error fetching expired archive jobs: %w", err)
+	}
+
+	purged := 0
+	for _, job := range jobs {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Warn().Err(err).Str("archive_job_id", job.ID.String()).Msg("error removing expired archive file")
+				continue
+			}
+		}
+		if err := s.archiveRepo.Delete(ctx, job.ID); err != nil {
+			return purged, fmt.Errorf("error deleting archive job: %w", err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// FailInterrupted marks the jobs left pending or processing by a previous run as
+// failed. Jobs run in-process, so nothing resumes them after a restart.
+func (s *fnDocumentArchiveService) FailInterrupted(ctx context.Context) (int64, error) {
+	n, err := s.archiveRepo.FailUnfinished(ctx, "archive job interrupted by a server restart, request it again", time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error failing interrupted archive jobs: %w", err)
+	}
+	return n, nil
+}
+
+// -- internal helpers
+
+// getOwnJob loads an archive job; jobs of other users are reported as not found
+// so their IDs cannot be probed
+func (s *fnDocumentArchiveService) getOwnJob(ctx context.Context, userID, id uuid.UUID) (*models.DocumentArchiveJob, error) {
+	job, err := s.archiveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching archive job: %w", err)
+	}
+	if job == nil || job.RequestedBy != userID {
+		return nil, fmt.Errorf("archive job not found")
+	}
+	return job, nil
+}
+
+func (s *fnDocumentArchiveService) runJob(jobID uuid.UUID, docs []models.Document) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), archiveJobTimeout)
+	defer cancel()
+
+	job, err := s.archiveRepo.GetByID(ctx, jobID)
+	if err != nil || job == nil {
+		log.Error().Err(err).Str("archive_job_id", jobID.String()).Msg("archive job disappeared before processing")
+		return
+	}
+
+	job.Status = dto.ArchiveStatusProcessing
+	if err := s.archiveRepo.Update(ctx, job); err != nil {
+		log.Error().Err(err).Str("archive_job_id", jobID.String()).Msg("error marking archive job as processing")
+		return
+	}
+
+	result, path, err := s.buildJobFile(ctx, job, docs)
+	if err != nil {
+		msg := err.Error()
+		job.Status = dto.ArchiveStatusFailed
+		job.ErrorMessage = &msg
+		log.Error().Err(err).Str("archive_job_id", jobID.String()).Msg("archive job failed")
+	} else {
+		now := time.Now().UTC()
+		expires := now.Add(s.cfg.TTL)
+		job.Status = dto.ArchiveStatusCompleted
+		job.ProcessedItems = result.ProcessedItems
+		job.FailedItems = result.FailedItems
+		job.FilePath = path
+		job.CompletedAt = &now
+		job.ExpiresAt = &expires
+		if info, statErr := os.Stat(path); statErr == nil {
+			size := info.Size()
+			job.FileSizeBytes = &size
+		}
+	}
+
+	if err := s.archiveRepo.Update(ctx, job); err != nil {
+		log.Error().Err(err).Str("archive_job_id", jobID.String()).Msg("error saving archive job result")
+	}
+}
+
+func (s *fnDocumentArchiveService) buildJobFile(ctx context.Context, job *models.DocumentArchiveJob, docs []models.Document) (*dto.DocumentArchiveResult, string, error) {
+	if err := os.MkdirAll(s.cfg.Dir, 0o750); err != nil {
+		return nil, "", fmt.Errorf("error creating archive directory: %w", err)
+	}
+
+	path := filepath.Join(s.cfg.Dir, job.ID.String()+".zip")
+	partial := path + ".part"
+
+	f, err := os.Create(partial)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating archive file: %w", err)
+	}
+
+	result, err := s.writeZip(ctx, docs, f, func(processed, failed int) {
+		if err := s.archiveRepo.UpdateProgress(ctx, job.ID, processed, failed); err != nil {
+			log.Warn().Err(err).Str("archive_job_id", job.ID.String()).Msg("error updating archive progress")
+		}
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(partial)
+		return nil, "", err
+	}
+
+	if err := os.Rename(partial, path); err != nil {
+		_ = os.Remove(partial)
+		return nil, "", fmt.Errorf("error finalizing archive file: %w", err)
+	}
+
+	return result, path, nil
+}
+
+// writeZip adds the latest PDF of every document, named by serial code, followed by
+// a manifest with the sha256 of each entry. Each PDF is read and checked in full
+// before its entry is written: missing, unreadable and tampered files are left out
+// of the ZIP and only listed in the manifest instead of aborting the archive.
+func (s *fnDocumentArchiveService) writeZip(ctx context.Context, docs []models.Document, w io.Writer, progress func(processed, failed int)) (*dto.DocumentArchiveResult, error) {
+	zw := zip.NewWriter(w)
+	result := &dto.DocumentArchiveResult{TotalItems: len(docs)}
+	manifest := make([][]string, 0, len(docs))
+	usedNames := make(map[string]int, len(docs))
+
+	for i, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		row := []string{
+			doc.SerialCode,
+			doc.VerificationCode,
+			doc.UserDetail.NationalID,
+			strings.TrimSpace(doc.UserDetail.FirstName + " " + doc.UserDetail.LastName),
+			"", "", "", "", "",
+			dto.ArchiveItemOK,
+		}
+
+		if len(doc.PDFs) == 0 {
+			row[9] = dto.ArchiveItemNoPDF
+			result.FailedItems++
+		} else {
+			pdf := doc.PDFs[0]
+			row[5] = pdf.FileID.String()
+			row[8] = pdf.FileHash
+
+			data, sum, err := s.fetchEntry(ctx, pdf.FileID)
+			switch {
+			case err != nil:
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				log.Warn().Err(err).Str("serial_code", doc.SerialCode).Str("file_id", pdf.FileID.String()).Msg("error downloading certificate for archive")
+				row[9] = dto.ArchiveItemDownloadFailed
+				result.FailedItems++
+			case pdf.FileHash != "" && !strings.EqualFold(pdf.FileHash, sum):
+				log.Warn().Str("serial_code", doc.SerialCode).Str("file_id", pdf.FileID.String()).Msg("certificate left out of archive: checksum mismatch")
+				row[6] = strconv.Itoa(len(data))
+				row[7] = sum
+				row[9] = dto.ArchiveItemHashMismatch
+				result.FailedItems++
+			default:
+				name := archiveEntryName(doc.SerialCode, usedNames)
+				if err := addEntry(zw, name, data); err != nil {
+					return nil, fmt.Errorf("error writing %s: %w", name, err)
+				}
+				row[4] = name
+				row[6] = strconv.Itoa(len(data))
+				row[7] = sum
+			}
+		}
+
+		manifest = append(manifest, row)
+		result.ProcessedItems++
+
+		if progress != nil && ((i+1)%archiveProgressEvery == 0 || i+1 == len(docs)) {
+			progress(result.ProcessedItems, result.FailedItems)
+		}
+	}
+
+	mw, err := zw.Create(archiveManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("error creating manifest: %w", err)
+	}
+	cw := csv.NewWriter(mw)
+	if err := cw.Write(archiveManifestColumns); err != nil {
+		return nil, fmt.Errorf("error writing manifest: %w", err)
+	}
+	if err := cw.WriteAll(manifest); err != nil {
+		return nil, fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("error closing archive: %w", err)
+	}
+
+	return result, nil
+}
+
+// fetchEntry downloads a whole file from file-svc and returns it with its sha256,
+// so that a failed download never leaves a truncated entry behind
+func (s *fnDocumentArchiveService) fetchEntry(ctx context.Context, fileID uuid.UUID) ([]byte, string, error) {
+	file, err := s.fileSvc.Download(ctx, fileID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file.Body, maxVerifiedPDFBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxVerifiedPDFBytes {
+		return nil, "", fmt.Errorf("pdf exceeds %d bytes", maxVerifiedPDFBytes)
+	}
+
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+// addEntry writes an already verified file as a new ZIP entry
+func addEntry(zw *zip.Writer, name string, data []byte) error {
+	// PDFs are already compressed, store them as-is
+	ew, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = ew.Write(data)
+	return err
+}
+
+func (s *fnDocumentArchiveService) loadDocuments(ctx context.Context, req dto.DocumentArchiveRequest) ([]models.Document, string, error) {
+	eventID, pdfJobID, err := parseArchiveRequest(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	date := time.Now().Format("20060102")
+
+	if eventID != nil {
+		event, err := s.eventRepo.GetByID(ctx, *eventID)
+		if err != nil {
+			return nil, "", fmt.Errorf("error fetching event: %w", err)
+		}
+		if event == nil {
+			return nil, "", fmt.Errorf("event not found")
+		}
+
+		docs, err := s.docRepo.GetDocumentsWithPDFsByEventID(ctx, *eventID)
+		if err != nil {
+			return nil, "", fmt.Errorf("error fetching documents: %w", err)
+		}
+
+		base := event.Code
+		if base == "" {
+			base = event.ID.String()
+		}
+		return docs, fmt.Sprintf("certificados_%s_%s.zip", unsafeFileNameChars.ReplaceAllString(base, "_"), date), nil
+	}
+
+	docs, err := s.docRepo.GetDocumentsWithPDFsByPDFJobID(ctx, *pdfJobID)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching documents: %w", err)
+	}
+	return docs, fmt.Sprintf("certificados_job_%s_%s.zip", pdfJobID.String(), date), nil
+}
+
+func (s *fnDocumentArchiveService) toJobResponse(job *models.DocumentArchiveJob) *dto.DocumentArchiveJobResponse {
+	resp := &dto.DocumentArchiveJobResponse{
+		ID:             job.ID,
+		EventID:        job.EventID,
+		PDFJobID:       job.PdfJobID,
+		Status:         job.Status,
+		TotalItems:     job.TotalItems,
+		ProcessedItems: job.ProcessedItems,
+		FailedItems:    job.FailedItems,
+		FileName:       job.FileName,
+		FileSizeBytes:  job.FileSizeBytes,
+		ErrorMessage:   job.ErrorMessage,
+		RequestedBy:    job.RequestedBy,
+		CompletedAt:    job.CompletedAt,
+		ExpiresAt:      job.ExpiresAt,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+	}
+
+	if job.Status == dto.ArchiveStatusCompleted {
+		url := fmt.Sprintf("/api/v1/fn/documents/archives/%s/download", job.ID)
+		resp.DownloadURL = &url
+	}
+
+	return resp
+}
+
+func parseArchiveRequest(req dto.DocumentArchiveRequest) (*uuid.UUID, *uuid.UUID, error) {
+	hasEvent := req.EventID != nil && *req.EventID != ""
+	hasJob := req.PDFJobID != nil && *req.PDFJobID != ""
+
+	if hasEvent == hasJob {
+		return nil, nil, fmt.Errorf("exactly one of event_id or pdf_job_id is required")
+	}
+
+	if hasEvent {
+		id, err := uuid.Parse(*req.EventID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid event_id")
+		}
+		return &id, nil, nil
+	}
+
+	id, err := uuid.Parse(*req.PDFJobID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pdf_job_id")
+	}
+	return nil, &id, nil
+}
+
+// archiveEntryName builds a safe, unique ZIP entry name from a serial code
+func archiveEntryName(serialCode string, used map[string]int) string {
+	base := unsafeFileNameChars.ReplaceAllString(serialCode, "_")
+	if base == "" {
+		base = "documento"
+	}
+
+	used[base]++
+	if n := used[base]; n > 1 {
+		return fmt.Sprintf("%s_%d.pdf", base, n)
+	}
+	return base + ".pdf"
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/client/filesvc"
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// fakeFileSvc stands in for file-svc: GET /download?file_id=uuid serves the stored
+// bytes and unknown IDs get the JSON 404 envelope file-svc answers with. IDs in
+// truncated announce the full length but drop the connection halfway.
+func fakeFileSvc(t *testing.T, files map[uuid.UUID][]byte, truncated ...uuid.UUID) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/download" {
+			http.NotFound(w, r)
+			return
+		}
+		id, err := uuid.Parse(r.URL.Query().Get("file_id"))
+		data, ok := files[id]
+		if err != nil || !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"status":"error","message":"file not found"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		for _, cut := range truncated {
+			if cut == id {
+				w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+				_, _ = w.Write(data[:len(data)/2])
+				return
+			}
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type memArchiveRepo struct {
+	repository.FNDocumentArchiveRepository
+
+	mu   sync.Mutex
+	jobs map[uuid.UUID]models.DocumentArchiveJob
+}
+
+func newMemArchiveRepo() *memArchiveRepo {
+	return &memArchiveRepo{jobs: map[uuid.UUID]models.DocumentArchiveJob{}}
+}
+
+func (r *memArchiveRepo) Create(_ context.Context, job *models.DocumentArchiveJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memArchiveRepo) GetByID(_ context.Context, id uuid.UUID) (*models.DocumentArchiveJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (r *memArchiveRepo) Update(_ context.Context, job *models.DocumentArchiveJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *memArchiveRepo) UpdateProgress(_ context.Context, id uuid.UUID, processed, failed int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	job.ProcessedItems = processed
+	job.FailedItems = failed
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *memArchiveRepo) GetExpired(context.Context, time.Time) ([]models.DocumentArchiveJob, error) {
+	return nil, nil
+}
+
+func (r *memArchiveRepo) FailUnfinished(_ context.Context, message string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, job := range r.jobs {
+		if job.Status == dto.ArchiveStatusPending || job.Status == dto.ArchiveStatusProcessing {
+			job.Status = dto.ArchiveStatusFailed
+			job.ErrorMessage = &message
+			job.UpdatedAt = at
+			r.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+type stubArchiveDocRepo struct {
+	repository.FNDocumentRepository
+	docs []models.Document
+}
+
+func (r *stubArchiveDocRepo) GetDocumentsWithPDFsByEventID(context.Context, uuid.UUID) ([]models.Document, error) {
+	return r.docs, nil
+}
+
+type stubArchiveEventRepo struct {
+	repository.FNEventRepository
+	event *models.Event
+}
+
+func (r *stubArchiveEventRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Event, error) {
+	if r.event == nil || r.event.ID != id {
+		return nil, nil
+	}
+	return r.event, nil
+}
+
+func archiveTestDoc(serial string, pdf *models.DocumentPDF) models.Document {
+	doc := models.Document{
+		ID:               uuid.New(),
+		SerialCode:       serial,
+		VerificationCode: "V-" + serial,
+		UserDetail:       models.UserDetail{NationalID: serial, FirstName: "Ana", LastName: serial},
+	}
+	if pdf != nil {
+		doc.PDFs = []models.DocumentPDF{*pdf}
+	}
+	return doc
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestArchiveJobAgainstFileSvc(t *testing.T) {
+	okID, tamperedID, missingID, cutID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	okPDF := []byte("%PDF-1.7 ok")
+	tamperedPDF := []byte("%PDF-1.7 tampered")
+	cutPDF := []byte("%PDF-1.7 cut off halfway through the transfer")
+
+	srv := fakeFileSvc(t, map[uuid.UUID][]byte{
+		okID:       okPDF,
+		tamperedID: tamperedPDF,
+		cutID:      cutPDF,
+	}, cutID)
+
+	event := &models.Event{ID: uuid.New(), Code: "EVT-01"}
+	docs := []models.Document{
+		archiveTestDoc("CERT-1", &models.DocumentPDF{FileID: okID, FileHash: sha256Hex(okPDF)}),
+		archiveTestDoc("CERT-2", &models.DocumentPDF{FileID: tamperedID, FileHash: sha256Hex([]byte("original"))}),
+		archiveTestDoc("CERT-3", &models.DocumentPDF{FileID: missingID, FileHash: "abc"}),
+		archiveTestDoc("CERT-4", nil),
+		archiveTestDoc("CERT-5", &models.DocumentPDF{FileID: cutID, FileHash: sha256Hex(cutPDF)}),
+	}
+
+	archiveRepo := newMemArchiveRepo()
+	svc := NewFNDocumentArchiveService(
+		archiveRepo,
+		&stubArchiveDocRepo{docs: docs},
+		&stubArchiveEventRepo{event: event},
+		filesvc.New(filesvc.Config{BaseURL: srv.URL}),
+		DocumentArchiveConfig{Dir: t.TempDir(), TTL: time.Hour},
+	)
+
+	ctx := context.Background()
+	owner := uuid.New()
+	eventID := event.ID.String()
+
+	job, err := svc.CreateJob(ctx, owner, dto.DocumentArchiveRequest{EventID: &eventID})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != dto.ArchiveStatusCompleted {
+		if job.Status == dto.ArchiveStatusFailed || time.Now().After(deadline) {
+			t.Fatalf("job did not complete, status %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = svc.GetJob(ctx, owner, job.ID); err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+	}
+
+	if job.ProcessedItems != 5 || job.FailedItems != 4 {
+		t.Fatalf("processed/failed = %d/%d, want 5/4", job.ProcessedItems, job.FailedItems)
+	}
+
+	if _, err := svc.GetJob(ctx, uuid.New(), job.ID); err == nil {
+		t.Fatal("GetJob by another user succeeded")
+	}
+	if _, _, err := svc.OpenJobFile(ctx, uuid.New(), job.ID); err == nil {
+		t.Fatal("OpenJobFile by another user succeeded")
+	}
+
+	f, fileName, err := svc.OpenJobFile(ctx, owner, job.ID)
+	if err != nil {
+		t.Fatalf("OpenJobFile: %v", err)
+	}
+	defer f.Close()
+	if fileName != job.FileName {
+		t.Errorf("file name = %q, want %q", fileName, job.FileName)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("opening archive: %v", err)
+	}
+
+	entries := map[string][]byte{}
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", zf.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[zf.Name] = b
+	}
+
+	if !bytes.Equal(entries["CERT-1.pdf"], okPDF) {
+		t.Errorf("CERT-1.pdf content does not match file-svc")
+	}
+	// tampered and truncated files are only listed in the manifest
+	if len(entries) != 2 {
+		names := make([]string, 0, len(entries))
+		for name := range entries {
+			names = append(names, name)
+		}
+		t.Errorf("archive entries = %v, want CERT-1.pdf and the manifest", names)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(entries[archiveManifestName])).ReadAll()
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	want := map[string]string{
+		"CERT-1": dto.ArchiveItemOK,
+		"CERT-2": dto.ArchiveItemHashMismatch,
+		"CERT-3": dto.ArchiveItemDownloadFailed,
+		"CERT-4": dto.ArchiveItemNoPDF,
+		"CERT-5": dto.ArchiveItemDownloadFailed,
+	}
+	for _, row := range rows[1:] {
+		if got := row[9]; got != want[row[0]] {
+			t.Errorf("manifest status of %s = %s, want %s", row[0], got, want[row[0]])
+		}
+	}
+}
+
+func TestFailInterruptedArchiveJobs(t *testing.T) {
+	archiveRepo := newMemArchiveRepo()
+	svc := NewFNDocumentArchiveService(archiveRepo, nil, nil, nil, DocumentArchiveConfig{Dir: t.TempDir()})
+
+	ctx := context.Background()
+	for _, status := range []string{dto.ArchiveStatusPending, dto.ArchiveStatusProcessing, dto.ArchiveStatusCompleted} {
+		_ = archiveRepo.Create(ctx, &models.DocumentArchiveJob{Status: status})
+	}
+
+	n, err := svc.FailInterrupted(ctx)
+	if err != nil {
+		t.Fatalf("FailInterrupted: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("failed %d jobs, want 2", n)
+	}
+
+	for _, job := range archiveRepo.jobs {
+		if job.Status == dto.ArchiveStatusPending || job.Status == dto.ArchiveStatusProcessing {
+			t.Errorf("job %s left %s", job.ID, job.Status)
+		}
+	}
+}
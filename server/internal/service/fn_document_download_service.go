@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/client/filesvc"
+	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// maxVerifiedPDFBytes caps the PDFs buffered for an integrity check before being sent
+const maxVerifiedPDFBytes = 32 << 20
+
+// FNDocumentDownloadService defines the interface for authenticated certificate PDF downloads
+type FNDocumentDownloadService interface {
+	OpenPDF(ctx context.Context, req dto.DocumentPDFDownloadRequest) (*dto.DocumentPDFDownload, error)
+}
+
+type fnDocumentDownloadService struct {
+	docRepo    repository.FNDocumentRepository
+	docPDFRepo repository.FNDocumentPDFRepository
+	userRepo   repository.FNUserRepository
+	logRepo    repository.FNDocumentDownloadLogRepository
+	fileSvc    *filesvc.Client
+}
+
+// NewFNDocumentDownloadService creates a new FN document download service
+func NewFNDocumentDownloadService(
+	docRepo repository.FNDocumentRepository,
+	docPDFRepo repository.FNDocumentPDFRepository,
+	userRepo repository.FNUserRepository,
+	logRepo repository.FNDocumentDownloadLogRepository,
+	fileSvc *filesvc.Client,
+) FNDocumentDownloadService {
+	return &fnDocumentDownloadService{
+		docRepo:    docRepo,
+		docPDFRepo: docPDFRepo,
+		userRepo:   userRepo,
+		logRepo:    logRepo,
+		fileSvc:    fileSvc,
+	}
+}
+
+// OpenPDF checks that the caller may read the certificate and opens the latest
+// (or requested) PDF version from file-svc. Revoked and replaced certificates are
+// only served to admins. Every attempt is logged.
+func (s *fnDocumentDownloadService) OpenPDF(ctx context.Context, req dto.DocumentPDFDownloadRequest) (*dto.DocumentPDFDownload, error) {
+	// beneficiaries download outside any unit scope; resolveAccess applies its own rules
+	doc, err := s.docRepo.GetByID(orgunit.WithScope(ctx, orgunit.Unrestricted), req.DocumentID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document not found")
+	}
+
+	reason, err := s.resolveAccess(ctx, doc, req.Accessor)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		s.logDownload(ctx, req, nil, "", dto.DownloadOutcomeDenied)
+		return nil, fmt.Errorf("access denied: only admins, the event owner or the beneficiary can download this certificate")
+	}
+	if withdrawn := withdrawnReason(doc); withdrawn != "" && !req.Accessor.IsAdmin {
+		s.logDownload(ctx, req, nil, reason, dto.DownloadOutcomeDenied)
+		return nil, fmt.Errorf("access denied: the certificate is no longer in force, %s", withdrawn)
+	}
+
+	var pdf *models.DocumentPDF
+	if req.Version != nil {
+		pdf, err = s.docPDFRepo.GetLatestByDocumentIDAndVersion(ctx, doc.ID, *req.Version)
+	} else {
+		pdf, err = s.docPDFRepo.GetLatestByDocumentID(ctx, doc.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching document pdf: %w", err)
+	}
+	if pdf == nil {
+		if req.Version != nil {
+			return nil, fmt.Errorf("pdf version %d not found for document", *req.Version)
+		}
+		return nil, fmt.Errorf("pdf not found for document")
+	}
+
+	file, err := s.openVerified(ctx, pdf)
+	if err != nil {
+		s.logDownload(ctx, req, &pdf.ID, reason, dto.DownloadOutcomeFailed)
+		if errors.Is(err, filesvc.ErrNotFound) {
+			return nil, fmt.Errorf("pdf file not found in storage")
+		}
+		if errors.Is(err, filesvc.ErrChecksumMismatch) {
+			log.Error().Err(err).Str("document_id", doc.ID.String()).Str("file_id", pdf.FileID.String()).Msg("certificate pdf failed its integrity check")
+			return nil, fmt.Errorf("error downloading pdf: stored file failed its integrity check")
+		}
+		return nil, fmt.Errorf("error downloading pdf: %w", err)
+	}
+
+	s.logDownload(ctx, req, &pdf.ID, reason, dto.DownloadOutcomeGranted)
+
+	size := file.ContentLength
+	if size <= 0 && pdf.FileSizeBytes != nil {
+		size = *pdf.FileSizeBytes
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/pdf"
+	}
+
+	name := unsafeFileNameChars.ReplaceAllString(doc.SerialCode, "_")
+	if req.Version != nil {
+		name = fmt.Sprintf("%s_v%d", name, *req.Version)
+	}
+
+	return &dto.DocumentPDFDownload{
+		Body:        file.Body,
+		FileName:    name + ".pdf",
+		ContentType: contentType,
+		Size:        size,
+	}, nil
+}
+
+// openVerified downloads the PDF from file-svc. When a SHA-256 is stored and the file
+// fits in maxVerifiedPDFBytes, the whole body is read and checked before anything is
+// returned, so a tampered file is never sent (ranged requests included, since file-svc
+// serves whole files anyway). Larger files are streamed and the check only detects a
+// mismatch once the bytes have already gone out.
+func (s *fnDocumentDownloadService) openVerified(ctx context.Context, pdf *models.DocumentPDF) (*filesvc.File, error) {
+	if pdf.FileHash == "" {
+		return s.fileSvc.Download(ctx, pdf.FileID)
+	}
+
+	file, err := s.fileSvc.DownloadVerified(ctx, pdf.FileID, pdf.FileHash)
+	if err != nil {
+		return nil, err
+	}
+	if file.ContentLength > maxVerifiedPDFBytes || (file.ContentLength <= 0 && pdf.FileSizeBytes != nil && *pdf.FileSizeBytes > maxVerifiedPDFBytes) {
+		return file, nil
+	}
+	defer file.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file.Body, maxVerifiedPDFBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxVerifiedPDFBytes {
+		return nil, fmt.Errorf("pdf exceeds %d bytes without a declared size", maxVerifiedPDFBytes)
+	}
+
+	file.Body = io.NopCloser(bytes.NewReader(data))
+	file.ContentLength = int64(len(data))
+	return file, nil
+}
+
+// resolveAccess returns why the caller may read the document, or "" when it may not
+func (s *fnDocumentDownloadService) resolveAccess(ctx context.Context, doc *models.Document, accessor dto.DocumentAccessor) (string, error) {
+	if accessor.IsAdmin {
+		return dto.DownloadAccessAdmin, nil
+	}
+	if doc.Event != nil && doc.Event.CreatedBy == accessor.UserID {
+		return dto.DownloadAccessEventOwner, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, accessor.UserID)
+	if err != nil {
+		return "", fmt.Errorf("error fetching user: %w", err)
+	}
+	if user != nil && user.NationalID != "" && user.NationalID == doc.UserDetail.NationalID {
+		return dto.DownloadAccessBeneficiary, nil
+	}
+
+	return "", nil
+}
+
+// withdrawnReason tells why a certificate is no longer in force, or "" when it is.
+// A reissue points to its replacement.
+func withdrawnReason(doc *models.Document) string {
+	switch {
+	case doc.ReplacedByID != nil:
+		return fmt.Sprintf("it was replaced by document %s", *doc.ReplacedByID)
+	case doc.Status == dto.DocStatusRejected || len(doc.Revocations) > 0:
+		return "it has been revoked"
+	default:
+		return ""
+	}
+}
+
+// logDownload records a download attempt; failures to log never block the download
+func (s *fnDocumentDownloadService) logDownload(ctx context.Context, req dto.DocumentPDFDownloadRequest, pdfID *uuid.UUID, reason, outcome string) {
+	entry := &models.DocumentDownloadLog{
+		DocumentID:    req.DocumentID,
+		DocumentPDFID: pdfID,
+		UserID:        req.Accessor.UserID,
+		AccessReason:  reason,
+		Outcome:       outcome,
+		Version:       req.Version,
+		RangeHeader:   req.RangeHeader,
+		IPAddress:     req.IPAddress,
+		UserAgent:     truncate(req.UserAgent, 500),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if err := s.logRepo.Create(ctx, entry); err != nil {
+		log.Error().Err(err).Str("document_id", req.DocumentID.String()).Msg("error logging certificate download")
+	}
+
+	log.Info().
+		Str("document_id", req.DocumentID.String()).
+		Str("user_id", req.Accessor.UserID.String()).
+		Str("access_reason", reason).
+		Str("outcome", outcome).
+		Msg("certificate download")
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	// cut on a rune boundary so the result stays valid UTF-8
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type stubDownloadUserRepo struct {
+	repository.FNUserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *stubDownloadUserRepo) GetByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return r.users[id], nil
+}
+
+type stubDownloadDocRepo struct {
+	repository.FNDocumentRepository
+	doc *models.Document
+}
+
+func (r *stubDownloadDocRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Document, error) {
+	if r.doc == nil || r.doc.ID != id {
+		return nil, nil
+	}
+	return r.doc, nil
+}
+
+type memDownloadLogRepo struct {
+	repository.FNDocumentDownloadLogRepository
+	entries []models.DocumentDownloadLog
+}
+
+func (r *memDownloadLogRepo) Create(_ context.Context, entry *models.DocumentDownloadLog) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func downloadTestFixture() (*models.Document, *stubDownloadUserRepo, uuid.UUID, uuid.UUID, uuid.UUID) {
+	ownerID, beneficiaryID, strangerID := uuid.New(), uuid.New(), uuid.New()
+	doc := &models.Document{
+		ID:         uuid.New(),
+		SerialCode: "CERT-1",
+		Status:     dto.DocStatusPDFCompleted,
+		Event:      &models.Event{CreatedBy: ownerID},
+		UserDetail: models.UserDetail{NationalID: "12345678"},
+	}
+	users := &stubDownloadUserRepo{users: map[uuid.UUID]*models.User{
+		beneficiaryID: {ID: beneficiaryID, NationalID: "12345678"},
+		strangerID:    {ID: strangerID, NationalID: "87654321"},
+	}}
+	return doc, users, ownerID, beneficiaryID, strangerID
+}
+
+func TestResolveDownloadAccess(t *testing.T) {
+	doc, users, ownerID, beneficiaryID, strangerID := downloadTestFixture()
+	svc := &fnDocumentDownloadService{userRepo: users}
+
+	cases := []struct {
+		name     string
+		accessor dto.DocumentAccessor
+		want     string
+	}{
+		{"admin", dto.DocumentAccessor{UserID: uuid.New(), IsAdmin: true}, dto.DownloadAccessAdmin},
+		{"event owner", dto.DocumentAccessor{UserID: ownerID}, dto.DownloadAccessEventOwner},
+		{"beneficiary", dto.DocumentAccessor{UserID: beneficiaryID}, dto.DownloadAccessBeneficiary},
+		{"someone else's certificate", dto.DocumentAccessor{UserID: strangerID}, ""},
+		{"unknown account", dto.DocumentAccessor{UserID: uuid.New()}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := svc.resolveAccess(context.Background(), doc, tc.accessor)
+			if err != nil {
+				t.Fatalf("resolveAccess: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("access = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOpenPDFWithholdsCertificatesNotInForce(t *testing.T) {
+	replacement := uuid.New()
+
+	cases := []struct {
+		name     string
+		withdraw func(*models.Document)
+		want     string
+	}{
+		{"rejected", func(d *models.Document) { d.Status = dto.DocStatusRejected }, "revoked"},
+		{"active revocation", func(d *models.Document) {
+			d.Revocations = []models.DocumentRevocation{{ReasonCode: dto.RevocationReasonFraud}}
+		}, "revoked"},
+		{"replaced by a reissue", func(d *models.Document) { d.ReplacedByID = &replacement }, replacement.String()},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, users, _, beneficiaryID, _ := downloadTestFixture()
+			tc.withdraw(doc)
+			logs := &memDownloadLogRepo{}
+			svc := NewFNDocumentDownloadService(&stubDownloadDocRepo{doc: doc}, nil, users, logs, nil)
+
+			_, err := svc.OpenPDF(context.Background(), dto.DocumentPDFDownloadRequest{
+				DocumentID: doc.ID,
+				Accessor:   dto.DocumentAccessor{UserID: beneficiaryID},
+			})
+			if err == nil || !strings.Contains(err.Error(), "access denied") || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("got %v, want access denied mentioning %q", err, tc.want)
+			}
+			if len(logs.entries) != 1 || logs.entries[0].Outcome != dto.DownloadOutcomeDenied {
+				t.Fatalf("download log = %+v, want one DENIED entry", logs.entries)
+			}
+		})
+	}
+}
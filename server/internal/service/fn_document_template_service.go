@@ -8,7 +8,9 @@ import (
 
 	"github.com/google/uuid"
 
+	"server/internal/client/filesvc"
 	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
 	"server/internal/dto"
 	"server/internal/repository"
 )
@@ -23,15 +25,19 @@ type FNDocumentTemplateService interface {
 	Enable(ctx context.Context, id uuid.UUID) error
 	Disable(ctx context.Context, id uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) (*dto.DocumentTemplateResponse, error)
+	Purge(ctx context.Context, id uuid.UUID) error
 }
 
 type fnDocumentTemplateService struct {
-	repo repository.FNDocumentTemplateRepository
+	repo    repository.FNDocumentTemplateRepository
+	fileSvc *filesvc.Client
 }
 
-// NewFNDocumentTemplateService creates a new FN document template service
-func NewFNDocumentTemplateService(repo repository.FNDocumentTemplateRepository) FNDocumentTemplateService {
-	return &fnDocumentTemplateService{repo: repo}
+// NewFNDocumentTemplateService creates a new FN document template service.
+// When fileSvc is nil, template file IDs are not checked against file-svc.
+func NewFNDocumentTemplateService(repo repository.FNDocumentTemplateRepository, fileSvc *filesvc.Client) FNDocumentTemplateService {
+	return &fnDocumentTemplateService{repo: repo, fileSvc: fileSvc}
 }
 
 func (s *fnDocumentTemplateService) Create(ctx context.Context, userID uuid.UUID, req dto.DocumentTemplateCreateRequest) (*dto.DocumentTemplateResponse, error) {
@@ -77,6 +83,21 @@ func (s *fnDocumentTemplateService) Create(ctx context.Context, userID uuid.UUID
 	if err != nil {
 		return nil, fmt.Errorf("invalid prev_file_id: must be a valid UUID")
 	}
+	if err := s.ensureFileExists(ctx, "file_id", fileID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureFileExists(ctx, "prev_file_id", prevFileID); err != nil {
+		return nil, err
+	}
+
+	orgUnitPath := ""
+	if req.OrganizationalUnitsPath != nil {
+		orgUnitPath = *req.OrganizationalUnitsPath
+	}
+	orgUnitPath, err = resolveOrgUnitPath(ctx, orgUnitPath, false)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now().UTC()
 	isActive := true
@@ -85,17 +106,18 @@ func (s *fnDocumentTemplateService) Create(ctx context.Context, userID uuid.UUID
 	}
 
 	template := &models.DocumentTemplate{
-		ID:             uuid.New(),
-		DocumentTypeID: docType.ID,
-		CategoryID:     categoryID,
-		Code:           code,
-		Name:           strings.TrimSpace(req.Name),
-		FileID:         fileID,
-		PrevFileID:     prevFileID,
-		IsActive:       isActive,
-		CreatedBy:      &userID,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:                      uuid.New(),
+		DocumentTypeID:          docType.ID,
+		CategoryID:              categoryID,
+		Code:                    code,
+		Name:                    strings.TrimSpace(req.Name),
+		FileID:                  fileID,
+		PrevFileID:              prevFileID,
+		IsActive:                isActive,
+		OrganizationalUnitsPath: orgUnitPath,
+		CreatedBy:               &userID,
+		CreatedAt:               now,
+		UpdatedAt:               now,
 	}
 
 	var fields []models.DocumentTemplateField
@@ -194,18 +216,19 @@ func (s *fnDocumentTemplateService) List(ctx context.Context, params dto.Documen
 		fieldsCount, _ := s.repo.CountFieldsByTemplateID(ctx, t.ID)
 
 		item := dto.DocumentTemplateListItem{
-			ID:               t.ID,
-			Code:             t.Code,
-			Name:             t.Name,
-			FileID:           t.FileID.String(),
-			PrevFileID:       t.PrevFileID.String(),
-			IsActive:         t.IsActive,
-			CreatedAt:        t.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:        t.UpdatedAt.Format(time.RFC3339),
-			DocumentTypeID:   t.DocumentTypeID,
-			DocumentTypeCode: t.DocumentType.Code,
-			DocumentTypeName: t.DocumentType.Name,
-			FieldsCount:      int(fieldsCount),
+			ID:                      t.ID,
+			Code:                    t.Code,
+			Name:                    t.Name,
+			FileID:                  t.FileID.String(),
+			PrevFileID:              t.PrevFileID.String(),
+			IsActive:                t.IsActive,
+			OrganizationalUnitsPath: t.OrganizationalUnitsPath,
+			CreatedAt:               t.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:               t.UpdatedAt.Format(time.RFC3339),
+			DocumentTypeID:          t.DocumentTypeID,
+			DocumentTypeCode:        t.DocumentType.Code,
+			DocumentTypeName:        t.DocumentType.Name,
+			FieldsCount:             int(fieldsCount),
 		}
 
 		if t.CategoryID != nil {
@@ -230,6 +253,9 @@ func (s *fnDocumentTemplateService) Update(ctx context.Context, id uuid.UUID, re
 	if template == nil {
 		return nil, fmt.Errorf("template not found")
 	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return nil, err
+	}
 
 	now := time.Now().UTC()
 
@@ -257,6 +283,11 @@ func (s *fnDocumentTemplateService) Update(ctx context.Context, id uuid.UUID, re
 		if err != nil {
 			return nil, fmt.Errorf("invalid file_id")
 		}
+		if fileID != template.FileID {
+			if err := s.ensureFileExists(ctx, "file_id", fileID); err != nil {
+				return nil, err
+			}
+		}
 		template.FileID = fileID
 	}
 
@@ -265,6 +296,11 @@ func (s *fnDocumentTemplateService) Update(ctx context.Context, id uuid.UUID, re
 		if err != nil {
 			return nil, fmt.Errorf("invalid prev_file_id")
 		}
+		if prevFileID != template.PrevFileID {
+			if err := s.ensureFileExists(ctx, "prev_file_id", prevFileID); err != nil {
+				return nil, err
+			}
+		}
 		template.PrevFileID = prevFileID
 	}
 
@@ -272,6 +308,14 @@ func (s *fnDocumentTemplateService) Update(ctx context.Context, id uuid.UUID, re
 		template.IsActive = *req.IsActive
 	}
 
+	if req.OrganizationalUnitsPath != nil {
+		path, err := resolveOrgUnitPath(ctx, *req.OrganizationalUnitsPath, false)
+		if err != nil {
+			return nil, err
+		}
+		template.OrganizationalUnitsPath = path
+	}
+
 	template.UpdatedAt = now
 
 	if err := s.repo.Update(ctx, template); err != nil {
@@ -409,6 +453,9 @@ func (s *fnDocumentTemplateService) Enable(ctx context.Context, id uuid.UUID) er
 	if template == nil {
 		return fmt.Errorf("template not found")
 	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return err
+	}
 
 	return s.repo.SetActive(ctx, id, true)
 }
@@ -421,6 +468,9 @@ func (s *fnDocumentTemplateService) Disable(ctx context.Context, id uuid.UUID) e
 	if template == nil {
 		return fmt.Errorf("template not found")
 	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return err
+	}
 
 	return s.repo.SetActive(ctx, id, false)
 }
@@ -433,25 +483,119 @@ func (s *fnDocumentTemplateService) Delete(ctx context.Context, id uuid.UUID) er
 	if template == nil {
 		return fmt.Errorf("template not found")
 	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return err
+	}
 
 	return s.repo.Delete(ctx, id)
 }
 
+func (s *fnDocumentTemplateService) Restore(ctx context.Context, id uuid.UUID) (*dto.DocumentTemplateResponse, error) {
+	template, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching template: %w", err)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("deleted template not found")
+	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return nil, err
+	}
+
+	// the code may have been reused while the template was deleted
+	exists, err := s.repo.ExistsByCodeExcludingID(ctx, template.Code, id)
+	if err != nil {
+		return nil, fmt.Errorf("error checking code: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("template with code '%s' already exists", template.Code)
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, fmt.Errorf("error restoring template: %w", err)
+	}
+
+	restored, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching restored template: %w", err)
+	}
+
+	return s.toResponse(restored), nil
+}
+
+// Purge permanently removes a deleted template with its fields and draft documents.
+// Templates used by events or by issued documents cannot be purged.
+func (s *fnDocumentTemplateService) Purge(ctx context.Context, id uuid.UUID) error {
+	template, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching template: %w", err)
+	}
+	if template == nil {
+		return fmt.Errorf("deleted template not found")
+	}
+	if err := ensureTemplateWritable(ctx, template); err != nil {
+		return err
+	}
+
+	issued, err := s.repo.CountIssuedDocuments(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error counting template documents: %w", err)
+	}
+	if issued > 0 {
+		return fmt.Errorf("template purge blocked: %d documents have already been issued", issued)
+	}
+
+	events, err := s.repo.CountEventsByTemplateID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error counting template events: %w", err)
+	}
+	if events > 0 {
+		return fmt.Errorf("template purge blocked: %d events use it", events)
+	}
+
+	return s.repo.Purge(ctx, id)
+}
+
+// ensureTemplateWritable rejects changes to shared templates (no unit) by unit-scoped callers
+func ensureTemplateWritable(ctx context.Context, t *models.DocumentTemplate) error {
+	if t.OrganizationalUnitsPath == "" && !orgunit.FromContext(ctx).Bypass {
+		return fmt.Errorf("access denied: shared templates can only be changed by an administrator")
+	}
+	return nil
+}
+
+// ensureFileExists checks that a template file reference points to a file stored in file-svc
+func (s *fnDocumentTemplateService) ensureFileExists(ctx context.Context, field string, fileID uuid.UUID) error {
+	if s.fileSvc == nil {
+		return nil
+	}
+
+	exists, err := s.fileSvc.Exists(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("error validating %s against file-svc: %w", field, err)
+	}
+	if !exists {
+		return fmt.Errorf("invalid %s: file %s does not exist in file-svc", field, fileID)
+	}
+	return nil
+}
+
 func (s *fnDocumentTemplateService) toResponse(t *models.DocumentTemplate) *dto.DocumentTemplateResponse {
 	if t == nil {
 		return nil
 	}
 
 	resp := &dto.DocumentTemplateResponse{
-		ID:         t.ID,
-		Code:       t.Code,
-		Name:       t.Name,
-		FileID:     t.FileID.String(),
-		PrevFileID: t.PrevFileID.String(),
-		IsActive:   t.IsActive,
-		CreatedBy:  t.CreatedBy,
-		CreatedAt:  t.CreatedAt,
-		UpdatedAt:  t.UpdatedAt,
+		ID:                      t.ID,
+		Code:                    t.Code,
+		Name:                    t.Name,
+		FileID:                  t.FileID.String(),
+		PrevFileID:              t.PrevFileID.String(),
+		IsActive:                t.IsActive,
+		OrganizationalUnitsPath: t.OrganizationalUnitsPath,
+		CreatedBy:               t.CreatedBy,
+		CreatedAt:               t.CreatedAt,
+		UpdatedAt:               t.UpdatedAt,
 		DocumentType: dto.DocumentTypeEmbedded{
 			ID:       t.DocumentType.ID,
 			Code:     t.DocumentType.Code,
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+// Domain events are published on "domain.<event_type>"
+const (
+	SubjectDomainEventPrefix = "domain."
+	SubjectDomainEvents      = "domain.>"
+)
+
+// publishDomainEvent publishes a domain event for the notification dispatcher.
+// Publishing is best effort: NATS is optional and a lost event only skips a notification.
+func publishDomainEvent(nc *nats.Conn, eventType string, payload interface{}) {
+	if nc == nil {
+		return
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", eventType).Msg("error marshaling domain event payload")
+		return
+	}
+	data, err := json.Marshal(dto.DomainEvent{
+		ID:         uuid.New(),
+		EventType:  eventType,
+		OccurredAt: time.Now().UTC(),
+		Payload:    raw,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("event_type", eventType).Msg("error marshaling domain event")
+		return
+	}
+
+	if err := nc.Publish(SubjectDomainEventPrefix+eventType, data); err != nil {
+		log.Warn().Err(err).Str("event_type", eventType).Msg("error publishing domain event")
+	}
+}
+
+// publishDocumentStatusChanged publishes the status change of a certificate
+func publishDocumentStatusChanged(nc *nats.Conn, doc *models.Document, previousStatus, status string, actorID *uuid.UUID) {
+	publishDomainEvent(nc, dto.DomainEventDocumentStatusChanged, dto.DocumentStatusChangedPayload{
+		DocumentID:     doc.ID,
+		UserDetailID:   doc.UserDetailID,
+		EventID:        doc.EventID,
+		PreviousStatus: previousStatus,
+		Status:         status,
+		ActorID:        actorID,
+	})
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/client/filesvc"
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// reportTimeLayout formats the dates shown in evaluation reports
+const reportTimeLayout = "2006-01-02 15:04 UTC"
+
+// EvaluationReportConfig enables PDF rendering of evaluation reports through pdf-svc.
+// TemplateFileID is a file-svc PDF whose placeholders receive the report fields, and
+// pdf-svc always stamps a QR, which links to QR.BaseURL plus the report ID. Without
+// both, reports are only kept as Markdown.
+type EvaluationReportConfig struct {
+	TemplateFileID *uuid.UUID
+	QR             *dto.QRConfigRequest
+	// AutoPDF also requests the PDF of the reports generated after grading
+	AutoPDF bool
+}
+
+// FNEvaluationReportService generates the Markdown report of graded attempts and
+// renders it to PDF through the pdf-svc batch pipeline
+type FNEvaluationReportService interface {
+	// AttemptGraded generates the report of a newly graded attempt
+	AttemptGraded(ctx context.Context, attempt *models.EvaluationAttempt)
+	// Regenerate rebuilds the report from the stored answers and scores; withPDF also requests its PDF
+	Regenerate(ctx context.Context, attemptID uuid.UUID, withPDF bool) (*dto.EvaluationReportResponse, error)
+	// Open returns the report as Markdown or PDF to the attempt's taker or to staff
+	Open(ctx context.Context, attemptID, userID uuid.UUID, staff bool, format string) (*dto.EvaluationReportFile, error)
+
+	// ProcessPDFBatchCompleted stores the rendered PDF; false when the job is not a report
+	ProcessPDFBatchCompleted(ctx context.Context, payload dto.PDFBatchCompletedPayload) (bool, error)
+	// ProcessPDFBatchFailed marks the report PDF as failed; false when the job is not a report
+	ProcessPDFBatchFailed(ctx context.Context, payload dto.PDFBatchFailedPayload) (bool, error)
+}
+
+type fnEvaluationReportService struct {
+	repo     repository.FNEvaluationRepository
+	userRepo repository.FNUserRepository
+	fileSvc  *filesvc.Client
+	natsConn *nats.Conn
+	cfg      EvaluationReportConfig
+}
+
+// NewFNEvaluationReportService creates a new FN evaluation report service
+func NewFNEvaluationReportService(
+	repo repository.FNEvaluationRepository,
+	userRepo repository.FNUserRepository,
+	fileSvc *filesvc.Client,
+	natsConn *nats.Conn,
+	cfg EvaluationReportConfig,
+) FNEvaluationReportService {
+	return &fnEvaluationReportService{
+		repo:     repo,
+		userRepo: userRepo,
+		fileSvc:  fileSvc,
+		natsConn: natsConn,
+		cfg:      cfg,
+	}
+}
+
+// AttemptGraded is best effort: the grade is already stored and the report can be
+// regenerated at any time
+func (s *fnEvaluationReportService) AttemptGraded(ctx context.Context, attempt *models.EvaluationAttempt) {
+	ctx = context.WithoutCancel(ctx)
+	logger := log.With().Str("attempt_id", attempt.ID.String()).Logger()
+
+	if _, err := s.Regenerate(ctx, attempt.ID, s.cfg.AutoPDF && s.pdfEnabled()); err != nil {
+		logger.Error().Err(err).Msg("error generating evaluation report")
+	}
+}
+
+func (s *fnEvaluationReportService) Regenerate(ctx context.Context, attemptID uuid.UUID, withPDF bool) (*dto.EvaluationReportResponse, error) {
+	if withPDF && !s.pdfEnabled() {
+		return nil, fmt.Errorf("report pdf blocked: PDF rendering is not configured")
+	}
+
+	attempt, err := s.getAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != dto.AttemptStatusGraded {
+		return nil, fmt.Errorf("report blocked: attempt is %s", attempt.Status)
+	}
+
+	doc, err := s.generate(ctx, attempt, withPDF)
+	if err != nil {
+		return nil, err
+	}
+	return toEvaluationReportResponse(doc), nil
+}
+
+func (s *fnEvaluationReportService) Open(ctx context.Context, attemptID, userID uuid.UUID, staff bool, format string) (*dto.EvaluationReportFile, error) {
+	if format != dto.ReportFormatMarkdown && format != dto.ReportFormatPDF {
+		return nil, fmt.Errorf("invalid format: must be %s or %s", dto.ReportFormatMarkdown, dto.ReportFormatPDF)
+	}
+
+	attempt, err := s.getAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if !staff && attempt.UserID != userID {
+		return nil, fmt.Errorf("access denied: attempt belongs to another user")
+	}
+
+	doc, err := s.repo.GetReportByAttemptID(ctx, attempt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching report: %w", err)
+	}
+	if doc == nil {
+		// attempts graded before reports existed get theirs on first download
+		if attempt.Status != dto.AttemptStatusGraded {
+			return nil, fmt.Errorf("report not found: attempt is %s", attempt.Status)
+		}
+		if doc, err = s.generate(ctx, attempt, false); err != nil {
+			return nil, err
+		}
+	}
+
+	name := fmt.Sprintf("evaluation-report-%s", attempt.ID)
+	if format == dto.ReportFormatMarkdown {
+		return &dto.EvaluationReportFile{
+			Body:        io.NopCloser(strings.NewReader(doc.MarkdownContent)),
+			FileName:    name + ".md",
+			ContentType: "text/markdown; charset=utf-8",
+			Size:        int64(len(doc.MarkdownContent)),
+		}, nil
+	}
+
+	switch {
+	case doc.PdfStatus == nil:
+		return nil, fmt.Errorf("report pdf not found: regenerate the report with pdf=true")
+	case *doc.PdfStatus == dto.ReportPDFStatusPending:
+		return nil, fmt.Errorf("report pdf blocked: rendering is in progress")
+	case *doc.PdfStatus == dto.ReportPDFStatusFailed || doc.PdfFileID == nil:
+		return nil, fmt.Errorf("report pdf blocked: rendering failed, regenerate the report")
+	}
+
+	var file *filesvc.File
+	if doc.PdfFileHash != nil && *doc.PdfFileHash != "" {
+		file, err = s.fileSvc.DownloadVerified(ctx, *doc.PdfFileID, *doc.PdfFileHash)
+	} else {
+		file, err = s.fileSvc.Download(ctx, *doc.PdfFileID)
+	}
+	if err != nil {
+		if errors.Is(err, filesvc.ErrNotFound) {
+			return nil, fmt.Errorf("report pdf file not found in storage")
+		}
+		return nil, fmt.Errorf("error downloading report pdf: %w", err)
+	}
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/pdf"
+	}
+	return &dto.EvaluationReportFile{
+		Body:        file.Body,
+		FileName:    name + ".pdf",
+		ContentType: contentType,
+		Size:        file.ContentLength,
+	}, nil
+}
+
+func (s *fnEvaluationReportService) ProcessPDFBatchCompleted(ctx context.Context, payload dto.PDFBatchCompletedPayload) (bool, error) {
+	doc, err := s.getReportByPDFJobID(ctx, payload.PDFJobID)
+	if err != nil || doc == nil {
+		return false, err
+	}
+
+	// report jobs carry a single item
+	status := dto.ReportPDFStatusFailed
+	for _, item := range payload.Items {
+		if item.Status != "completed" || item.Data == nil {
+			continue
+		}
+		fileID, err := uuid.Parse(item.Data.FileID)
+		if err != nil {
+			continue
+		}
+		fileHash := item.Data.FileHash
+		doc.PdfFileID = &fileID
+		doc.PdfFileHash = &fileHash
+		status = dto.ReportPDFStatusCompleted
+	}
+	doc.PdfStatus = &status
+
+	if err := s.repo.UpdateReportPDF(ctx, doc); err != nil {
+		return true, fmt.Errorf("error updating report pdf: %w", err)
+	}
+	return true, nil
+}
+
+func (s *fnEvaluationReportService) ProcessPDFBatchFailed(ctx context.Context, payload dto.PDFBatchFailedPayload) (bool, error) {
+	doc, err := s.getReportByPDFJobID(ctx, payload.PDFJobID)
+	if err != nil || doc == nil {
+		return false, err
+	}
+
+	status := dto.ReportPDFStatusFailed
+	doc.PdfStatus = &status
+	if err := s.repo.UpdateReportPDF(ctx, doc); err != nil {
+		return true, fmt.Errorf("error updating report pdf: %w", err)
+	}
+	return true, nil
+}
+
+// generate builds and stores the report of a graded attempt, replacing any previous one
+func (s *fnEvaluationReportService) generate(ctx context.Context, attempt *models.EvaluationAttempt, withPDF bool) (*models.EvaluationDoc, error) {
+	evaluation, err := s.repo.GetByID(ctx, attempt.EvaluationID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching evaluation: %w", err)
+	}
+	if evaluation == nil {
+		return nil, fmt.Errorf("evaluation not found")
+	}
+	user, err := s.userRepo.GetByID(ctx, attempt.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+
+	doc := &models.EvaluationDoc{
+		ID:              uuid.New(),
+		EvaluationID:    evaluation.ID,
+		AttemptID:       &attempt.ID,
+		MarkdownContent: buildEvaluationReport(evaluation, attempt, user),
+		GeneratedAt:     time.Now().UTC(),
+	}
+	if err := s.repo.SaveReport(ctx, doc); err != nil {
+		return nil, fmt.Errorf("error saving report: %w", err)
+	}
+
+	if withPDF {
+		if err := s.requestPDF(ctx, doc, evaluation, attempt, user); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// requestPDF publishes a one-item pdf.batch.requested job for the report
+func (s *fnEvaluationReportService) requestPDF(ctx context.Context, doc *models.EvaluationDoc, evaluation *models.Evaluation, attempt *models.EvaluationAttempt, user *models.User) error {
+	pdfJobID := uuid.New()
+	batchEvent := dto.PDFBatchRequestEvent{
+		EventType: "pdf.batch.requested",
+		Payload: dto.PDFBatchRequestPayload{
+			PDFJobID: pdfJobID.String(),
+			Items: []dto.PDFBatchRequestItem{{
+				UserID:     attempt.UserID.String(),
+				TemplateID: s.cfg.TemplateFileID.String(),
+				SerialCode: fmt.Sprintf("evaluation-report-%s", attempt.ID),
+				IsPublic:   false,
+				PDF:        reportPDFFields(evaluation, attempt, user, doc.MarkdownContent),
+				QR: []dto.PDFKeyValue{
+					{Key: "base_url", Value: s.cfg.QR.BaseURL},
+					{Key: "verify_code", Value: doc.ID.String()},
+				},
+				QRPDF: []dto.PDFKeyValue{
+					{Key: "qr_size_cm", Value: fmt.Sprintf("%.2f", s.cfg.QR.QRSizeCM)},
+					{Key: "qr_margin_y_cm", Value: fmt.Sprintf("%.2f", s.cfg.QR.QRMarginYCM)},
+					{Key: "qr_page", Value: fmt.Sprintf("%d", s.cfg.QR.QRPage)},
+				},
+			}},
+		},
+	}
+
+	eventData, err := json.Marshal(batchEvent)
+	if err != nil {
+		return fmt.Errorf("error marshaling batch event: %w", err)
+	}
+
+	status := dto.ReportPDFStatusPending
+	doc.PdfStatus = &status
+	doc.PdfJobID = &pdfJobID
+	if err := s.repo.UpdateReportPDF(ctx, doc); err != nil {
+		return fmt.Errorf("error updating report pdf: %w", err)
+	}
+
+	if err := s.natsConn.Publish(SubjectPDFBatchRequested, eventData); err != nil {
+		failed := dto.ReportPDFStatusFailed
+		doc.PdfStatus = &failed
+		_ = s.repo.UpdateReportPDF(ctx, doc)
+		return fmt.Errorf("error publishing pdf batch event: %w", err)
+	}
+	return nil
+}
+
+func (s *fnEvaluationReportService) pdfEnabled() bool {
+	return s.natsConn != nil && s.cfg.TemplateFileID != nil && s.cfg.QR != nil
+}
+
+func (s *fnEvaluationReportService) getAttempt(ctx context.Context, id uuid.UUID) (*models.EvaluationAttempt, error) {
+	attempt, err := s.repo.GetAttempt(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attempt: %w", err)
+	}
+	if attempt == nil {
+		return nil, fmt.Errorf("attempt not found")
+	}
+	return attempt, nil
+}
+
+func (s *fnEvaluationReportService) getReportByPDFJobID(ctx context.Context, value string) (*models.EvaluationDoc, error) {
+	// malformed job IDs are left to the certificate pipeline to report
+	pdfJobID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, nil
+	}
+	doc, err := s.repo.GetReportByPDFJobID(ctx, pdfJobID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching report: %w", err)
+	}
+	return doc, nil
+}
+
+// reportPDFFields fills the placeholders of the report template: evaluacion, participante,
+// intento, puntaje, porcentaje, resultado, fecha and informe (the Markdown report)
+func reportPDFFields(e *models.Evaluation, a *models.EvaluationAttempt, user *models.User, markdown string) []dto.PDFKeyValue {
+	gradedAt := ""
+	if a.GradedAt != nil {
+		gradedAt = a.GradedAt.UTC().Format(reportTimeLayout)
+	}
+	return []dto.PDFKeyValue{
+		{Key: "evaluacion", Value: e.Title},
+		{Key: "participante", Value: reportParticipant(a, user)},
+		{Key: "intento", Value: fmt.Sprintf("%d", a.AttemptNumber)},
+		{Key: "puntaje", Value: fmt.Sprintf("%.2f / %.2f", a.Score, a.MaxScore)},
+		{Key: "porcentaje", Value: fmt.Sprintf("%.2f %%", a.Percentage)},
+		{Key: "resultado", Value: reportOutcome(a)},
+		{Key: "fecha", Value: gradedAt},
+		{Key: "informe", Value: markdown},
+	}
+}
+
+// buildEvaluationReport renders a graded attempt as Markdown: header, every question
+// with the answer, its verdict, score and remarks, and a totals table
+func buildEvaluationReport(e *models.Evaluation, a *models.EvaluationAttempt, user *models.User) string {
+	responses := make(map[uuid.UUID]string, len(a.Answers))
+	for _, ans := range a.Answers {
+		responses[ans.QuestionID] = ans.ResponseText
+	}
+	scores := make(map[uuid.UUID]*models.EvaluationScore, len(a.Scores))
+	for i := range a.Scores {
+		scores[a.Scores[i].QuestionID] = &a.Scores[i]
+	}
+	questions := attemptQuestions(a, e)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Informe de evaluación: %s\n\n", mdInline(e.Title))
+	fmt.Fprintf(&b, "- **Participante:** %s\n", mdInline(reportParticipant(a, user)))
+	fmt.Fprintf(&b, "- **Intento:** %d\n", a.AttemptNumber)
+	if a.SubmittedAt != nil {
+		fmt.Fprintf(&b, "- **Enviado:** %s\n", a.SubmittedAt.UTC().Format(reportTimeLayout))
+	}
+	if a.GradedAt != nil {
+		fmt.Fprintf(&b, "- **Calificado:** %s\n", a.GradedAt.UTC().Format(reportTimeLayout))
+	}
+	fmt.Fprintf(&b, "- **Puntaje:** %.2f / %.2f (%.2f %%)\n", a.Score, a.MaxScore, a.Percentage)
+	fmt.Fprintf(&b, "- **Nota mínima:** %.2f %%\n", e.PassingScore)
+	fmt.Fprintf(&b, "- **Resultado:** %s\n", reportOutcome(a))
+
+	b.WriteString("\n## Respuestas\n")
+	var correct, partial, wrong, pending int
+	for i := range questions {
+		q := &questions[i]
+		fmt.Fprintf(&b, "\n### Pregunta %d (%s)\n\n", q.QuestionNumber, questionTypeLabel(q.QuestionType))
+		b.WriteString(mdQuote(q.QuestionText))
+		b.WriteString("\n\n")
+
+		response := responses[q.ID]
+		switch {
+		case response == "":
+			b.WriteString("- **Respuesta:** _sin respuesta_\n")
+		case q.QuestionType == dto.QuestionTypeEssay:
+			b.WriteString("- **Respuesta:**\n\n")
+			b.WriteString(mdQuote(response))
+			b.WriteString("\n\n")
+		default:
+			fmt.Fprintf(&b, "- **Respuesta:** %s\n", mdInline(answerLabel(q, response)))
+		}
+		if key := answerKeyLabel(q); key != "" {
+			fmt.Fprintf(&b, "- **Respuesta esperada:** %s\n", mdInline(key))
+		}
+
+		sc, ok := scores[q.ID]
+		if !ok {
+			b.WriteString("- **Resultado:** sin calificar\n")
+			continue
+		}
+		fmt.Fprintf(&b, "- **Resultado:** %s, %.2f / %.2f\n", verdictLabel(sc.AdminVerdict), sc.Score, q.MaxScore)
+		if sc.Remarks != nil && strings.TrimSpace(*sc.Remarks) != "" {
+			fmt.Fprintf(&b, "- **Observaciones:** %s\n", mdInline(*sc.Remarks))
+		}
+
+		switch sc.AdminVerdict {
+		case dto.VerdictCorrect, dto.VerdictApproved:
+			correct++
+		case dto.VerdictPartial:
+			partial++
+		case dto.VerdictPending:
+			pending++
+		default:
+			wrong++
+		}
+	}
+
+	b.WriteString("\n## Totales\n\n")
+	b.WriteString("| # | Tipo | Resultado | Puntaje | Máximo |\n")
+	b.WriteString("|---:|---|---|---:|---:|\n")
+	for _, q := range questions {
+		verdict, points := "sin calificar", 0.0
+		if sc, ok := scores[q.ID]; ok {
+			verdict, points = verdictLabel(sc.AdminVerdict), sc.Score
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %.2f | %.2f |\n", q.QuestionNumber, questionTypeLabel(q.QuestionType), verdict, points, q.MaxScore)
+	}
+	fmt.Fprintf(&b, "| **Total** | | | **%.2f** | **%.2f** |\n\n", a.Score, a.MaxScore)
+
+	fmt.Fprintf(&b, "- **Correctas o aprobadas:** %d\n", correct)
+	fmt.Fprintf(&b, "- **Parciales:** %d\n", partial)
+	fmt.Fprintf(&b, "- **Incorrectas o rechazadas:** %d\n", wrong)
+	if pending > 0 {
+		fmt.Fprintf(&b, "- **Pendientes de revisión:** %d\n", pending)
+	}
+	fmt.Fprintf(&b, "- **Porcentaje:** %.2f %% (mínimo %.2f %%)\n", a.Percentage, e.PassingScore)
+
+	return b.String()
+}
+
+func reportParticipant(a *models.EvaluationAttempt, user *models.User) string {
+	if user == nil {
+		return a.UserID.String()
+	}
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		return user.Email
+	}
+	return fmt.Sprintf("%s (%s)", name, user.Email)
+}
+
+func reportOutcome(a *models.EvaluationAttempt) string {
+	if a.Passed != nil && *a.Passed {
+		return "Aprobado"
+	}
+	return "No aprobado"
+}
+
+// answerLabel shows the selected options of choice questions with their text
+func answerLabel(q *models.EvaluationQuestion, response string) string {
+	if q.QuestionType != dto.QuestionTypeMultipleChoice && q.QuestionType != dto.QuestionTypeTrueFalse {
+		return response
+	}
+	labels := make([]string, 0)
+	for _, key := range strings.Split(response, ",") {
+		labels = append(labels, optionLabel(q, key))
+	}
+	return strings.Join(labels, "; ")
+}
+
+// answerKeyLabel lists the correct options or the accepted answers; essays have none
+func answerKeyLabel(q *models.EvaluationQuestion) string {
+	labels := make([]string, 0)
+	for _, o := range q.Options {
+		if !o.IsCorrect {
+			continue
+		}
+		if q.QuestionType == dto.QuestionTypeShortAnswer {
+			labels = append(labels, o.OptionText)
+			continue
+		}
+		labels = append(labels, optionLabel(q, o.OptionKey))
+	}
+	return strings.Join(labels, "; ")
+}
+
+func optionLabel(q *models.EvaluationQuestion, key string) string {
+	if q.QuestionType == dto.QuestionTypeTrueFalse {
+		if key == dto.TrueFalseKeyTrue {
+			return "Verdadero"
+		}
+		return "Falso"
+	}
+	for _, o := range q.Options {
+		if o.OptionKey == key {
+			return fmt.Sprintf("%s) %s", o.OptionKey, o.OptionText)
+		}
+	}
+	return key
+}
+
+func questionTypeLabel(questionType string) string {
+	switch questionType {
+	case dto.QuestionTypeMultipleChoice:
+		return "opción múltiple"
+	case dto.QuestionTypeTrueFalse:
+		return "verdadero o falso"
+	case dto.QuestionTypeShortAnswer:
+		return "respuesta corta"
+	case dto.QuestionTypeEssay:
+		return "desarrollo"
+	default:
+		return questionType
+	}
+}
+
+func verdictLabel(verdict string) string {
+	switch verdict {
+	case dto.VerdictCorrect:
+		return "correcta"
+	case dto.VerdictIncorrect:
+		return "incorrecta"
+	case dto.VerdictPending:
+		return "pendiente de revisión"
+	case dto.VerdictApproved:
+		return "aprobada"
+	case dto.VerdictPartial:
+		return "parcial"
+	case dto.VerdictRejected:
+		return "rechazada"
+	default:
+		return verdict
+	}
+}
+
+// mdInline keeps free text on one line and out of table syntax
+func mdInline(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+}
+
+// mdQuote renders multi-line free text as a block quote
+func mdQuote(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+strings.TrimRight(line, "\r"), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func toEvaluationReportResponse(doc *models.EvaluationDoc) *dto.EvaluationReportResponse {
+	resp := &dto.EvaluationReportResponse{
+		ID:              doc.ID,
+		EvaluationID:    doc.EvaluationID,
+		GeneratedAt:     doc.GeneratedAt,
+		PDFStatus:       doc.PdfStatus,
+		PDFJobID:        doc.PdfJobID,
+		MarkdownContent: doc.MarkdownContent,
+	}
+	if doc.AttemptID != nil {
+		resp.AttemptID = *doc.AttemptID
+	}
+	return resp
+}
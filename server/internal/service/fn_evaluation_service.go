@@ -0,0 +1,1093 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// submissionGrace tolerates network latency on submissions sent right at the time limit
+const submissionGrace = 30 * time.Second
+
+// FNEvaluationService defines the interface for evaluation authoring, attempts and grading
+type FNEvaluationService interface {
+	// authoring
+	Create(ctx context.Context, userID uuid.UUID, req dto.EvaluationCreateRequest) (*dto.EvaluationResponse, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.EvaluationResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req dto.EvaluationUpdateRequest) (*dto.EvaluationResponse, error)
+	// ReplaceQuestions replaces all questions (dropping any draw rules); blocked once the evaluation has attempts
+	ReplaceQuestions(ctx context.Context, id uuid.UUID, req dto.EvaluationQuestionsRequest) (*dto.EvaluationResponse, error)
+	// ReplaceDrawRules makes every attempt draw its own shuffled questions from the bank.
+	// Fixed questions are dropped, which is blocked once the evaluation has attempts.
+	ReplaceDrawRules(ctx context.Context, id uuid.UUID, req dto.EvaluationDrawRulesRequest) (*dto.EvaluationResponse, error)
+	ListAttempts(ctx context.Context, evaluationID uuid.UUID) ([]dto.AttemptResponse, error)
+
+	// taking
+	// Start opens a new attempt, or resumes the user's attempt still in progress.
+	// Evaluations with draw rules assemble the attempt's questions from the bank.
+	Start(ctx context.Context, evaluationID, userID uuid.UUID) (*dto.AttemptResponse, error)
+	GetAttempt(ctx context.Context, attemptID, userID uuid.UUID) (*dto.AttemptResponse, error)
+	// Submit grades objective questions and sends essays to the review queue
+	Submit(ctx context.Context, attemptID, userID uuid.UUID, req dto.AttemptSubmitRequest) (*dto.AttemptResponse, error)
+
+	// manual review
+	ListReviewQueue(ctx context.Context, params dto.ReviewQueueQuery) ([]dto.ReviewQueueItem, int64, error)
+	// Review grades a pending answer; the attempt is finalized with its last pending answer
+	Review(ctx context.Context, scoreID, reviewerID uuid.UUID, req dto.ScoreReviewRequest) (*dto.AttemptResponse, error)
+}
+
+type fnEvaluationService struct {
+	repo          repository.FNEvaluationRepository
+	bankRepo      repository.FNQuestionBankRepository
+	certification FNEventCertificationService
+	reports       FNEvaluationReportService
+}
+
+// NewFNEvaluationService creates a new FN evaluation service. Graded attempts are
+// handed to certification (event eligibility and certificate issuing) and to
+// reports (the attempt's Markdown report).
+func NewFNEvaluationService(
+	repo repository.FNEvaluationRepository,
+	bankRepo repository.FNQuestionBankRepository,
+	certification FNEventCertificationService,
+	reports FNEvaluationReportService,
+) FNEvaluationService {
+	return &fnEvaluationService{
+		repo:          repo,
+		bankRepo:      bankRepo,
+		certification: certification,
+		reports:       reports,
+	}
+}
+
+func (s *fnEvaluationService) Create(ctx context.Context, userID uuid.UUID, req dto.EvaluationCreateRequest) (*dto.EvaluationResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	evaluation := &models.Evaluation{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Title:        title,
+		Description:  req.Description,
+		Status:       dto.EvaluationStatusPending,
+		PassingScore: 60,
+		MaxAttempts:  1,
+	}
+	if req.DocumentID != nil && *req.DocumentID != "" {
+		documentID, err := uuid.Parse(*req.DocumentID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document_id")
+		}
+		evaluation.DocumentID = &documentID
+	}
+	if err := applyEvaluationSettings(evaluation, req.Status, req.PassingScore, req.TimeLimitMinutes, req.MaxAttempts); err != nil {
+		return nil, err
+	}
+
+	if len(req.Questions) > 0 && len(req.DrawRules) > 0 {
+		return nil, fmt.Errorf("invalid request: questions and draw_rules are mutually exclusive")
+	}
+	questions, err := buildEvaluationQuestions(evaluation.ID, req.Questions)
+	if err != nil {
+		return nil, err
+	}
+	evaluation.Questions = questions
+	rules, err := s.buildDrawRules(ctx, evaluation.ID, req.DrawRules)
+	if err != nil {
+		return nil, err
+	}
+	evaluation.DrawRules = rules
+	if evaluation.Status == dto.EvaluationStatusActive && !hasQuestionSource(evaluation) {
+		return nil, fmt.Errorf("questions are required to activate an evaluation")
+	}
+
+	if err := s.repo.Create(ctx, evaluation); err != nil {
+		return nil, fmt.Errorf("error creating evaluation: %w", err)
+	}
+
+	return s.GetByID(ctx, evaluation.ID)
+}
+
+func (s *fnEvaluationService) GetByID(ctx context.Context, id uuid.UUID) (*dto.EvaluationResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEvaluationResponse(evaluation), nil
+}
+
+func (s *fnEvaluationService) Update(ctx context.Context, id uuid.UUID, req dto.EvaluationUpdateRequest) (*dto.EvaluationResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, fmt.Errorf("title is required")
+		}
+		evaluation.Title = title
+	}
+	if req.Description != nil {
+		evaluation.Description = req.Description
+	}
+	if err := applyEvaluationSettings(evaluation, req.Status, req.PassingScore, req.TimeLimitMinutes, req.MaxAttempts); err != nil {
+		return nil, err
+	}
+	if evaluation.Status == dto.EvaluationStatusActive && !hasQuestionSource(evaluation) {
+		return nil, fmt.Errorf("questions are required to activate an evaluation")
+	}
+
+	evaluation.UpdatedAt = time.Now()
+	if err := s.repo.UpdateSettings(ctx, evaluation); err != nil {
+		return nil, fmt.Errorf("error updating evaluation: %w", err)
+	}
+
+	return toEvaluationResponse(evaluation), nil
+}
+
+func (s *fnEvaluationService) ReplaceQuestions(ctx context.Context, id uuid.UUID, req dto.EvaluationQuestionsRequest) (*dto.EvaluationResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Questions) == 0 {
+		return nil, fmt.Errorf("questions are required")
+	}
+
+	// answers and scores point to the questions: changing them would corrupt past results
+	if err := s.ensureNoAttempts(ctx, id, "questions update"); err != nil {
+		return nil, err
+	}
+
+	questions, err := buildEvaluationQuestions(id, req.Questions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceQuestions(ctx, id, questions); err != nil {
+		return nil, fmt.Errorf("error replacing questions: %w", err)
+	}
+	if len(evaluation.DrawRules) > 0 {
+		if err := s.repo.ReplaceDrawRules(ctx, id, nil); err != nil {
+			return nil, fmt.Errorf("error removing draw rules: %w", err)
+		}
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *fnEvaluationService) ReplaceDrawRules(ctx context.Context, id uuid.UUID, req dto.EvaluationDrawRulesRequest) (*dto.EvaluationResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Rules) == 0 && len(evaluation.Questions) == 0 && evaluation.Status == dto.EvaluationStatusActive {
+		return nil, fmt.Errorf("rules are required while the evaluation is active")
+	}
+
+	// drawn questions are copied into each attempt, so only fixed questions are tied to past results
+	if len(req.Rules) > 0 && len(evaluation.Questions) > 0 {
+		if err := s.ensureNoAttempts(ctx, id, "draw rules update"); err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := s.buildDrawRules(ctx, id, req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 && len(evaluation.Questions) > 0 {
+		if err := s.repo.ReplaceQuestions(ctx, id, nil); err != nil {
+			return nil, fmt.Errorf("error removing questions: %w", err)
+		}
+	}
+	if err := s.repo.ReplaceDrawRules(ctx, id, rules); err != nil {
+		return nil, fmt.Errorf("error replacing draw rules: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *fnEvaluationService) ListAttempts(ctx context.Context, evaluationID uuid.UUID) ([]dto.AttemptResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, evaluationID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListAttempts(ctx, evaluationID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attempts: %w", err)
+	}
+
+	items := make([]dto.AttemptResponse, 0, len(attempts))
+	for i := range attempts {
+		items = append(items, *toAttemptResponse(&attempts[i], evaluation, false))
+	}
+	return items, nil
+}
+
+func (s *fnEvaluationService) Start(ctx context.Context, evaluationID, userID uuid.UUID) (*dto.AttemptResponse, error) {
+	evaluation, err := s.getEvaluation(ctx, evaluationID)
+	if err != nil {
+		return nil, err
+	}
+	if evaluation.Status != dto.EvaluationStatusActive {
+		return nil, fmt.Errorf("evaluation blocked: status is %s", evaluation.Status)
+	}
+	if !hasQuestionSource(evaluation) {
+		return nil, fmt.Errorf("evaluation blocked: it has no questions")
+	}
+
+	now := time.Now()
+	latest, err := s.repo.GetLatestAttempt(ctx, evaluationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attempts: %w", err)
+	}
+
+	attemptNumber := 1
+	if latest != nil {
+		if latest.Status == dto.AttemptStatusInProgress {
+			if !attemptTimedOut(latest, now) {
+				return toAttemptResponse(latest, evaluation, true), nil
+			}
+			if _, err := s.repo.ExpireAttempt(ctx, latest.ID, now); err != nil {
+				return nil, fmt.Errorf("error expiring attempt: %w", err)
+			}
+		}
+		switch {
+		case latest.Status == dto.AttemptStatusUnderReview:
+			return nil, fmt.Errorf("attempt blocked: the previous attempt is under review")
+		case latest.Passed != nil && *latest.Passed:
+			return nil, fmt.Errorf("attempt blocked: evaluation already passed")
+		case evaluation.MaxAttempts > 0 && latest.AttemptNumber >= evaluation.MaxAttempts:
+			return nil, fmt.Errorf("attempt blocked: maximum of %d attempts reached", evaluation.MaxAttempts)
+		}
+		attemptNumber = latest.AttemptNumber + 1
+	}
+
+	// an attempt already in progress is resumed above; new ones need the study material done
+	if err := s.certification.EnsureStudyCompleted(ctx, evaluationID, userID); err != nil {
+		return nil, err
+	}
+
+	attempt := &models.EvaluationAttempt{
+		ID:            uuid.New(),
+		EvaluationID:  evaluationID,
+		UserID:        userID,
+		AttemptNumber: attemptNumber,
+		Status:        dto.AttemptStatusInProgress,
+		StartedAt:     now,
+		MaxScore:      evaluationMaxScore(evaluation),
+	}
+	if len(evaluation.DrawRules) > 0 {
+		questions, err := s.assembleQuestions(ctx, evaluation, attempt.ID)
+		if err != nil {
+			return nil, err
+		}
+		attempt.Questions = questions
+		attempt.MaxScore = questionsMaxScore(questions)
+	}
+	if evaluation.TimeLimitMinutes != nil {
+		expiresAt := now.Add(time.Duration(*evaluation.TimeLimitMinutes) * time.Minute)
+		attempt.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateAttempt(ctx, attempt); err != nil {
+		return nil, fmt.Errorf("error creating attempt: %w", err)
+	}
+
+	return toAttemptResponse(attempt, evaluation, true), nil
+}
+
+func (s *fnEvaluationService) GetAttempt(ctx context.Context, attemptID, userID uuid.UUID) (*dto.AttemptResponse, error) {
+	attempt, err := s.getOwnAttempt(ctx, attemptID, userID)
+	if err != nil {
+		return nil, err
+	}
+	evaluation, err := s.getEvaluation(ctx, attempt.EvaluationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.Status == dto.AttemptStatusInProgress && attemptTimedOut(attempt, time.Now()) {
+		if err := s.expire(ctx, attempt); err != nil {
+			return nil, err
+		}
+	}
+
+	return toAttemptResponse(attempt, evaluation, attempt.Status == dto.AttemptStatusInProgress), nil
+}
+
+func (s *fnEvaluationService) Submit(ctx context.Context, attemptID, userID uuid.UUID, req dto.AttemptSubmitRequest) (*dto.AttemptResponse, error) {
+	attempt, err := s.getOwnAttempt(ctx, attemptID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != dto.AttemptStatusInProgress {
+		return nil, fmt.Errorf("submission blocked: attempt is %s", attempt.Status)
+	}
+
+	now := time.Now()
+	if attemptTimedOut(attempt, now) {
+		if err := s.expire(ctx, attempt); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("submission blocked: time limit exceeded")
+	}
+
+	evaluation, err := s.getEvaluation(ctx, attempt.EvaluationID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := attemptQuestions(attempt, evaluation)
+	responses, err := indexAttemptAnswers(questions, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]models.EvaluationAnswer, 0, len(questions))
+	scores := make([]models.EvaluationScore, 0, len(questions))
+	for i := range questions {
+		q := &questions[i]
+		responseText, verdict, points, err := gradeAnswer(q, responses[q.ID])
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, models.EvaluationAnswer{
+			ID:           uuid.New(),
+			EvaluationID: evaluation.ID,
+			QuestionID:   q.ID,
+			AttemptID:    &attempt.ID,
+			ResponseText: responseText,
+			CreatedAt:    now,
+		})
+		scores = append(scores, models.EvaluationScore{
+			ID:           uuid.New(),
+			EvaluationID: evaluation.ID,
+			QuestionID:   q.ID,
+			AttemptID:    &attempt.ID,
+			AdminVerdict: verdict,
+			Score:        points,
+			ReviewedAt:   now,
+		})
+	}
+
+	attempt.SubmittedAt = &now
+	attempt.MaxScore = questionsMaxScore(questions)
+	attempt.Status = dto.AttemptStatusUnderReview
+	attempt.UpdatedAt = now
+	gradeAttempt(attempt, evaluation, scores, now)
+
+	ok, err := s.repo.SaveSubmission(ctx, attempt, answers, scores)
+	if err != nil {
+		return nil, fmt.Errorf("error saving submission: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("submission blocked: attempt was already submitted")
+	}
+	attempt.Answers = answers
+	attempt.Scores = scores
+	if attempt.Status == dto.AttemptStatusGraded {
+		s.attemptGraded(ctx, attempt)
+	}
+
+	return toAttemptResponse(attempt, evaluation, false), nil
+}
+
+func (s *fnEvaluationService) ListReviewQueue(ctx context.Context, params dto.ReviewQueueQuery) ([]dto.ReviewQueueItem, int64, error) {
+	scores, total, err := s.repo.ListPendingReviews(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching review queue: %w", err)
+	}
+
+	attemptIDs := make([]uuid.UUID, 0, len(scores))
+	for _, sc := range scores {
+		attemptIDs = append(attemptIDs, *sc.AttemptID)
+	}
+	answers, err := s.repo.GetAnswers(ctx, attemptIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching answers: %w", err)
+	}
+	responses := make(map[string]string, len(answers))
+	for _, a := range answers {
+		responses[a.AttemptID.String()+a.QuestionID.String()] = a.ResponseText
+	}
+
+	items := make([]dto.ReviewQueueItem, 0, len(scores))
+	for _, sc := range scores {
+		item := dto.ReviewQueueItem{
+			ScoreID:         sc.ID,
+			AttemptID:       *sc.AttemptID,
+			EvaluationID:    sc.EvaluationID,
+			EvaluationTitle: sc.Evaluation.Title,
+			QuestionID:      sc.QuestionID,
+			QuestionNumber:  sc.Question.QuestionNumber,
+			QuestionText:    sc.Question.QuestionText,
+			MaxScore:        sc.Question.MaxScore,
+			ResponseText:    responses[sc.AttemptID.String()+sc.QuestionID.String()],
+			SubmittedAt:     sc.ReviewedAt,
+		}
+		if sc.Attempt != nil {
+			item.UserID = sc.Attempt.UserID
+			if sc.Attempt.SubmittedAt != nil {
+				item.SubmittedAt = *sc.Attempt.SubmittedAt
+			}
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+func (s *fnEvaluationService) Review(ctx context.Context, scoreID, reviewerID uuid.UUID, req dto.ScoreReviewRequest) (*dto.AttemptResponse, error) {
+	score, err := s.repo.GetScore(ctx, scoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching score: %w", err)
+	}
+	if score == nil || score.AttemptID == nil {
+		return nil, fmt.Errorf("score not found")
+	}
+	if score.AdminVerdict != dto.VerdictPending {
+		return nil, fmt.Errorf("review blocked: answer already reviewed (%s)", score.AdminVerdict)
+	}
+
+	points, err := reviewPoints(req, score.Question.MaxScore)
+	if err != nil {
+		return nil, err
+	}
+
+	score.AdminVerdict = req.Verdict
+	score.Score = points
+	score.Remarks = req.Remarks
+	score.ReviewedBy = &reviewerID
+	score.ReviewedAt = time.Now()
+
+	ok, err := s.repo.SaveReview(ctx, score)
+	if err != nil {
+		return nil, fmt.Errorf("error saving review: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("review blocked: answer already reviewed")
+	}
+
+	attempt, err := s.repo.GetAttempt(ctx, *score.AttemptID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attempt: %w", err)
+	}
+	if attempt == nil {
+		return nil, fmt.Errorf("attempt not found")
+	}
+	evaluation, err := s.getEvaluation(ctx, attempt.EvaluationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.finalizeAttempt(ctx, attempt, evaluation); err != nil {
+		return nil, err
+	}
+
+	return toAttemptResponse(attempt, evaluation, false), nil
+}
+
+// finalizeAttempt grades an attempt under review once no answer is pending
+func (s *fnEvaluationService) finalizeAttempt(ctx context.Context, attempt *models.EvaluationAttempt, evaluation *models.Evaluation) error {
+	if attempt.Status != dto.AttemptStatusUnderReview {
+		return nil
+	}
+
+	now := time.Now()
+	gradeAttempt(attempt, evaluation, attempt.Scores, now)
+	if attempt.Status != dto.AttemptStatusGraded {
+		return nil
+	}
+	attempt.UpdatedAt = now
+
+	finalized, err := s.repo.FinalizeAttempt(ctx, attempt)
+	if err != nil {
+		return fmt.Errorf("error finalizing attempt: %w", err)
+	}
+	// a concurrent review finalized it first and already handed it over
+	if finalized {
+		s.attemptGraded(ctx, attempt)
+	}
+	return nil
+}
+
+// attemptGraded hands a newly graded attempt over to certification and reports
+func (s *fnEvaluationService) attemptGraded(ctx context.Context, attempt *models.EvaluationAttempt) {
+	s.certification.AttemptGraded(ctx, attempt)
+	s.reports.AttemptGraded(ctx, attempt)
+}
+
+// expire closes an attempt that ran out of time without a submission
+func (s *fnEvaluationService) expire(ctx context.Context, attempt *models.EvaluationAttempt) error {
+	now := time.Now()
+	if _, err := s.repo.ExpireAttempt(ctx, attempt.ID, now); err != nil {
+		return fmt.Errorf("error expiring attempt: %w", err)
+	}
+	passed := false
+	attempt.Status = dto.AttemptStatusExpired
+	attempt.Passed = &passed
+	attempt.UpdatedAt = now
+	return nil
+}
+
+func (s *fnEvaluationService) getEvaluation(ctx context.Context, id uuid.UUID) (*models.Evaluation, error) {
+	evaluation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching evaluation: %w", err)
+	}
+	if evaluation == nil {
+		return nil, fmt.Errorf("evaluation not found")
+	}
+	return evaluation, nil
+}
+
+func (s *fnEvaluationService) getOwnAttempt(ctx context.Context, attemptID, userID uuid.UUID) (*models.EvaluationAttempt, error) {
+	attempt, err := s.repo.GetAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching attempt: %w", err)
+	}
+	if attempt == nil {
+		return nil, fmt.Errorf("attempt not found")
+	}
+	if attempt.UserID != userID {
+		return nil, fmt.Errorf("access denied: attempt belongs to another user")
+	}
+	return attempt, nil
+}
+
+func (s *fnEvaluationService) ensureNoAttempts(ctx context.Context, id uuid.UUID, action string) error {
+	attempts, err := s.repo.CountAttempts(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error counting attempts: %w", err)
+	}
+	if attempts > 0 {
+		return fmt.Errorf("%s blocked: the evaluation already has %d attempts", action, attempts)
+	}
+	return nil
+}
+
+// buildDrawRules validates draw rules against the active questions of the bank
+func (s *fnEvaluationService) buildDrawRules(ctx context.Context, evaluationID uuid.UUID, inputs []dto.EvaluationDrawRuleInput) ([]models.EvaluationDrawRule, error) {
+	rules := make([]models.EvaluationDrawRule, 0, len(inputs))
+	for i, in := range inputs {
+		number := i + 1
+		topic := strings.TrimSpace(in.Topic)
+		if topic == "" {
+			return nil, fmt.Errorf("draw rule %d: topic is required", number)
+		}
+		var difficulty *string
+		if in.Difficulty != nil && strings.TrimSpace(*in.Difficulty) != "" {
+			value := strings.ToUpper(strings.TrimSpace(*in.Difficulty))
+			if !slices.Contains(dto.Difficulties, value) {
+				return nil, fmt.Errorf("draw rule %d: invalid difficulty %q", number, *in.Difficulty)
+			}
+			difficulty = &value
+		}
+		if in.Count < 1 {
+			return nil, fmt.Errorf("draw rule %d: invalid count: must be at least 1", number)
+		}
+
+		available, err := s.bankRepo.CountActive(ctx, topic, difficulty)
+		if err != nil {
+			return nil, fmt.Errorf("error counting bank questions: %w", err)
+		}
+		if available < int64(in.Count) {
+			return nil, fmt.Errorf("draw rule %d: invalid count: the bank has %d active questions for %s", number, available, drawRuleLabel(topic, difficulty))
+		}
+
+		rules = append(rules, models.EvaluationDrawRule{
+			ID:           uuid.New(),
+			EvaluationID: evaluationID,
+			Topic:        topic,
+			Difficulty:   difficulty,
+			Count:        in.Count,
+			OrderIndex:   i,
+		})
+	}
+	return rules, nil
+}
+
+// assembleQuestions draws the questions of a new attempt, rule by rule without repeating
+// a question, and numbers the copies in shuffled order
+func (s *fnEvaluationService) assembleQuestions(ctx context.Context, evaluation *models.Evaluation, attemptID uuid.UUID) ([]models.EvaluationQuestion, error) {
+	var drawn []models.BankQuestion
+	exclude := make([]uuid.UUID, 0)
+	for _, rule := range evaluation.DrawRules {
+		questions, err := s.bankRepo.Draw(ctx, rule.Topic, rule.Difficulty, rule.Count, exclude)
+		if err != nil {
+			return nil, fmt.Errorf("error drawing questions: %w", err)
+		}
+		// the bank may have shrunk since the rule was saved
+		if len(questions) < rule.Count {
+			return nil, fmt.Errorf("evaluation blocked: the bank has only %d active questions left for %s", len(questions), drawRuleLabel(rule.Topic, rule.Difficulty))
+		}
+		for _, q := range questions {
+			exclude = append(exclude, q.ID)
+		}
+		drawn = append(drawn, questions...)
+	}
+
+	rand.Shuffle(len(drawn), func(i, j int) {
+		drawn[i], drawn[j] = drawn[j], drawn[i]
+	})
+
+	questions := make([]models.EvaluationQuestion, 0, len(drawn))
+	for i := range drawn {
+		questions = append(questions, instanceQuestion(&drawn[i], evaluation.ID, attemptID, i+1))
+	}
+	return questions, nil
+}
+
+// applyEvaluationSettings validates and applies the optional settings of a create or update request
+func applyEvaluationSettings(e *models.Evaluation, status *string, passingScore *float64, timeLimit, maxAttempts *int) error {
+	if status != nil {
+		switch *status {
+		case dto.EvaluationStatusPending, dto.EvaluationStatusActive, dto.EvaluationStatusClosed:
+			e.Status = *status
+		default:
+			return fmt.Errorf("invalid status: %s", *status)
+		}
+	}
+	if passingScore != nil {
+		if *passingScore < 0 || *passingScore > 100 {
+			return fmt.Errorf("invalid passing_score: must be between 0 and 100")
+		}
+		e.PassingScore = *passingScore
+	}
+	if timeLimit != nil {
+		// 0 removes the limit
+		if *timeLimit < 0 {
+			return fmt.Errorf("invalid time_limit_minutes: must not be negative")
+		}
+		e.TimeLimitMinutes = timeLimit
+		if *timeLimit == 0 {
+			e.TimeLimitMinutes = nil
+		}
+	}
+	if maxAttempts != nil {
+		if *maxAttempts < 0 {
+			return fmt.Errorf("invalid max_attempts: must not be negative")
+		}
+		e.MaxAttempts = *maxAttempts
+	}
+	return nil
+}
+
+// buildEvaluationQuestions validates authored questions and builds them with their options
+func buildEvaluationQuestions(evaluationID uuid.UUID, inputs []dto.EvaluationQuestionInput) ([]models.EvaluationQuestion, error) {
+	questions := make([]models.EvaluationQuestion, 0, len(inputs))
+	for i, in := range inputs {
+		number := i + 1
+		question, err := buildEvaluationQuestion(in)
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", number, err)
+		}
+		question.ID = uuid.New()
+		question.EvaluationID = evaluationID
+		question.QuestionNumber = number
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// buildEvaluationQuestion validates one question and builds it with its options;
+// the caller sets ID, owner and number
+func buildEvaluationQuestion(in dto.EvaluationQuestionInput) (models.EvaluationQuestion, error) {
+	text := strings.TrimSpace(in.QuestionText)
+	if text == "" {
+		return models.EvaluationQuestion{}, fmt.Errorf("question_text is required")
+	}
+	if !slices.Contains(dto.QuestionTypes, in.QuestionType) {
+		return models.EvaluationQuestion{}, fmt.Errorf("invalid question_type %q", in.QuestionType)
+	}
+	maxScore := 1.0
+	if in.MaxScore != nil {
+		if *in.MaxScore <= 0 {
+			return models.EvaluationQuestion{}, fmt.Errorf("invalid max_score: must be greater than 0")
+		}
+		maxScore = *in.MaxScore
+	}
+
+	options, err := buildQuestionOptions(in)
+	if err != nil {
+		return models.EvaluationQuestion{}, err
+	}
+
+	return models.EvaluationQuestion{
+		QuestionText: text,
+		QuestionType: in.QuestionType,
+		MaxScore:     maxScore,
+		Options:      options,
+	}, nil
+}
+
+func buildQuestionOptions(in dto.EvaluationQuestionInput) ([]models.EvaluationQuestionOption, error) {
+	switch in.QuestionType {
+	case dto.QuestionTypeTrueFalse:
+		if in.CorrectAnswer == nil {
+			return nil, fmt.Errorf("correct_answer is required for TRUE_FALSE")
+		}
+		return []models.EvaluationQuestionOption{
+			{OptionKey: dto.TrueFalseKeyTrue, OptionText: "True", IsCorrect: *in.CorrectAnswer, OrderIndex: 0},
+			{OptionKey: dto.TrueFalseKeyFalse, OptionText: "False", IsCorrect: !*in.CorrectAnswer, OrderIndex: 1},
+		}, nil
+
+	case dto.QuestionTypeEssay:
+		if len(in.Options) > 0 {
+			return nil, fmt.Errorf("invalid options: ESSAY questions have no options")
+		}
+		return nil, nil
+
+	case dto.QuestionTypeShortAnswer:
+		if len(in.Options) == 0 {
+			return nil, fmt.Errorf("options are required: list the accepted answers")
+		}
+		options := make([]models.EvaluationQuestionOption, 0, len(in.Options))
+		for i, o := range in.Options {
+			if normalizeAnswer(o.Text) == "" {
+				return nil, fmt.Errorf("option %d: text is required", i+1)
+			}
+			options = append(options, models.EvaluationQuestionOption{
+				OptionKey:  fmt.Sprintf("%d", i+1),
+				OptionText: strings.TrimSpace(o.Text),
+				IsCorrect:  true,
+				OrderIndex: i,
+			})
+		}
+		return options, nil
+
+	default: // MULTIPLE_CHOICE
+		if len(in.Options) < 2 {
+			return nil, fmt.Errorf("at least 2 options are required for MULTIPLE_CHOICE")
+		}
+		options := make([]models.EvaluationQuestionOption, 0, len(in.Options))
+		seen := make(map[string]bool, len(in.Options))
+		correct := 0
+		for i, o := range in.Options {
+			key := strings.ToLower(strings.TrimSpace(o.Key))
+			if key == "" {
+				key = string(rune('a' + i))
+			}
+			if seen[key] {
+				return nil, fmt.Errorf("invalid options: duplicate key %q", key)
+			}
+			seen[key] = true
+			if strings.TrimSpace(o.Text) == "" {
+				return nil, fmt.Errorf("option %q: text is required", key)
+			}
+			if o.IsCorrect {
+				correct++
+			}
+			options = append(options, models.EvaluationQuestionOption{
+				OptionKey:  key,
+				OptionText: strings.TrimSpace(o.Text),
+				IsCorrect:  o.IsCorrect,
+				OrderIndex: i,
+			})
+		}
+		if correct == 0 {
+			return nil, fmt.Errorf("invalid options: at least one option must be correct")
+		}
+		return options, nil
+	}
+}
+
+// indexAttemptAnswers maps the submitted answers by question; the last answer to a question wins
+func indexAttemptAnswers(questions []models.EvaluationQuestion, inputs []dto.AttemptAnswerInput) (map[uuid.UUID]dto.AttemptAnswerInput, error) {
+	questionIDs := make(map[uuid.UUID]bool, len(questions))
+	for _, q := range questions {
+		questionIDs[q.ID] = true
+	}
+
+	responses := make(map[uuid.UUID]dto.AttemptAnswerInput, len(inputs))
+	for _, in := range inputs {
+		id, err := uuid.Parse(in.QuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question_id: %s", in.QuestionID)
+		}
+		if !questionIDs[id] {
+			return nil, fmt.Errorf("invalid question_id: %s does not belong to the evaluation", in.QuestionID)
+		}
+		responses[id] = in
+	}
+	return responses, nil
+}
+
+// gradeAnswer returns the stored response, the verdict and the points of an answer.
+// Unanswered questions are INCORRECT; non-blank essays wait for review.
+func gradeAnswer(q *models.EvaluationQuestion, in dto.AttemptAnswerInput) (string, string, float64, error) {
+	switch q.QuestionType {
+	case dto.QuestionTypeMultipleChoice, dto.QuestionTypeTrueFalse:
+		valid := make(map[string]bool, len(q.Options))
+		var correct []string
+		for _, o := range q.Options {
+			valid[o.OptionKey] = true
+			if o.IsCorrect {
+				correct = append(correct, o.OptionKey)
+			}
+		}
+
+		var selected []string
+		for _, key := range in.SelectedOptions {
+			key = strings.ToLower(strings.TrimSpace(key))
+			if !valid[key] {
+				return "", "", 0, fmt.Errorf("invalid option %q for question %d", key, q.QuestionNumber)
+			}
+			if !slices.Contains(selected, key) {
+				selected = append(selected, key)
+			}
+		}
+		sort.Strings(selected)
+		sort.Strings(correct)
+
+		response := strings.Join(selected, ",")
+		if len(selected) > 0 && slices.Equal(selected, correct) {
+			return response, dto.VerdictCorrect, q.MaxScore, nil
+		}
+		return response, dto.VerdictIncorrect, 0, nil
+
+	case dto.QuestionTypeShortAnswer:
+		response := strings.TrimSpace(in.ResponseText)
+		normalized := normalizeAnswer(response)
+		for _, o := range q.Options {
+			if normalized != "" && normalizeAnswer(o.OptionText) == normalized {
+				return response, dto.VerdictCorrect, q.MaxScore, nil
+			}
+		}
+		return response, dto.VerdictIncorrect, 0, nil
+
+	default: // ESSAY
+		response := strings.TrimSpace(in.ResponseText)
+		if response == "" {
+			return response, dto.VerdictIncorrect, 0, nil
+		}
+		return response, dto.VerdictPending, 0, nil
+	}
+}
+
+// normalizeAnswer lowercases and collapses whitespace for short answer comparison
+func normalizeAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// reviewPoints validates a reviewer's verdict and returns the points it awards
+func reviewPoints(req dto.ScoreReviewRequest, maxScore float64) (float64, error) {
+	switch req.Verdict {
+	case dto.VerdictApproved:
+		if req.Score == nil {
+			return maxScore, nil
+		}
+		if *req.Score <= 0 || *req.Score > maxScore {
+			return 0, fmt.Errorf("invalid score: must be greater than 0 and at most %.2f", maxScore)
+		}
+		return *req.Score, nil
+	case dto.VerdictPartial:
+		if req.Score == nil {
+			return 0, fmt.Errorf("score is required for a PARTIAL verdict")
+		}
+		if *req.Score <= 0 || *req.Score >= maxScore {
+			return 0, fmt.Errorf("invalid score: a PARTIAL verdict must be between 0 and %.2f", maxScore)
+		}
+		return *req.Score, nil
+	case dto.VerdictRejected:
+		if req.Score != nil && *req.Score != 0 {
+			return 0, fmt.Errorf("invalid score: a REJECTED verdict scores 0")
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("invalid verdict: must be APPROVED, PARTIAL or REJECTED")
+	}
+}
+
+// gradeAttempt totals the scores of an attempt. With no pending answer it becomes
+// GRADED and passes when its percentage reaches the evaluation's passing score.
+func gradeAttempt(attempt *models.EvaluationAttempt, evaluation *models.Evaluation, scores []models.EvaluationScore, now time.Time) {
+	total := 0.0
+	for _, sc := range scores {
+		if sc.AdminVerdict == dto.VerdictPending {
+			return
+		}
+		total += sc.Score
+	}
+
+	attempt.Score = roundScore(total)
+	if attempt.MaxScore > 0 {
+		attempt.Percentage = roundScore(total / attempt.MaxScore * 100)
+	}
+	passed := attempt.Percentage >= evaluation.PassingScore
+	attempt.Passed = &passed
+	attempt.Status = dto.AttemptStatusGraded
+	attempt.GradedAt = &now
+}
+
+func attemptTimedOut(attempt *models.EvaluationAttempt, now time.Time) bool {
+	return attempt.ExpiresAt != nil && now.After(attempt.ExpiresAt.Add(submissionGrace))
+}
+
+func evaluationMaxScore(evaluation *models.Evaluation) float64 {
+	return questionsMaxScore(evaluation.Questions)
+}
+
+func questionsMaxScore(questions []models.EvaluationQuestion) float64 {
+	total := 0.0
+	for _, q := range questions {
+		total += q.MaxScore
+	}
+	return roundScore(total)
+}
+
+// hasQuestionSource reports whether attempts can get questions: fixed ones or draw rules
+func hasQuestionSource(evaluation *models.Evaluation) bool {
+	return len(evaluation.Questions) > 0 || len(evaluation.DrawRules) > 0
+}
+
+// attemptQuestions returns the questions drawn for an attempt, or the evaluation's fixed ones
+func attemptQuestions(a *models.EvaluationAttempt, e *models.Evaluation) []models.EvaluationQuestion {
+	if len(a.Questions) > 0 {
+		return a.Questions
+	}
+	return e.Questions
+}
+
+func drawRuleLabel(topic string, difficulty *string) string {
+	if difficulty == nil {
+		return fmt.Sprintf("topic %q", topic)
+	}
+	return fmt.Sprintf("topic %q (%s)", topic, *difficulty)
+}
+
+func roundScore(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func toEvaluationResponse(e *models.Evaluation) *dto.EvaluationResponse {
+	return &dto.EvaluationResponse{
+		ID:               e.ID,
+		Title:            e.Title,
+		Description:      e.Description,
+		DocumentID:       e.DocumentID,
+		Status:           e.Status,
+		PassingScore:     e.PassingScore,
+		TimeLimitMinutes: e.TimeLimitMinutes,
+		MaxAttempts:      e.MaxAttempts,
+		MaxScore:         evaluationMaxScore(e),
+		CreatedBy:        e.UserID,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+		Questions:        toQuestionResponses(e.Questions, true),
+		DrawRules:        toDrawRuleResponses(e.DrawRules),
+	}
+}
+
+func toDrawRuleResponses(rules []models.EvaluationDrawRule) []dto.EvaluationDrawRuleResponse {
+	if len(rules) == 0 {
+		return nil
+	}
+	items := make([]dto.EvaluationDrawRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		items = append(items, dto.EvaluationDrawRuleResponse{
+			Topic:      r.Topic,
+			Difficulty: r.Difficulty,
+			Count:      r.Count,
+		})
+	}
+	return items
+}
+
+// toQuestionResponses maps questions; withKey includes which options are correct
+func toQuestionResponses(questions []models.EvaluationQuestion, withKey bool) []dto.EvaluationQuestionResponse {
+	items := make([]dto.EvaluationQuestionResponse, 0, len(questions))
+	for _, q := range questions {
+		item := dto.EvaluationQuestionResponse{
+			ID:             q.ID,
+			QuestionNumber: q.QuestionNumber,
+			QuestionText:   q.QuestionText,
+			QuestionType:   q.QuestionType,
+			MaxScore:       q.MaxScore,
+		}
+		// accepted short answers are the key itself: takers only get a text box
+		if withKey || q.QuestionType != dto.QuestionTypeShortAnswer {
+			for _, o := range q.Options {
+				option := dto.EvaluationOptionResponse{Key: o.OptionKey, Text: o.OptionText}
+				if withKey {
+					isCorrect := o.IsCorrect
+					option.IsCorrect = &isCorrect
+				}
+				item.Options = append(item.Options, option)
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// toAttemptResponse maps an attempt; withQuestions sends the questions without the
+// answer key (attempt in progress), otherwise the graded answers are included
+func toAttemptResponse(a *models.EvaluationAttempt, e *models.Evaluation, withQuestions bool) *dto.AttemptResponse {
+	resp := &dto.AttemptResponse{
+		ID:            a.ID,
+		EvaluationID:  a.EvaluationID,
+		UserID:        a.UserID,
+		AttemptNumber: a.AttemptNumber,
+		Status:        a.Status,
+		StartedAt:     a.StartedAt,
+		ExpiresAt:     a.ExpiresAt,
+		SubmittedAt:   a.SubmittedAt,
+		GradedAt:      a.GradedAt,
+		Score:         a.Score,
+		MaxScore:      a.MaxScore,
+		Percentage:    a.Percentage,
+		PassingScore:  e.PassingScore,
+		Passed:        a.Passed,
+	}
+	questions := attemptQuestions(a, e)
+	if withQuestions {
+		resp.Questions = toQuestionResponses(questions, false)
+		return resp
+	}
+	if len(a.Scores) == 0 {
+		return resp
+	}
+
+	responses := make(map[uuid.UUID]string, len(a.Answers))
+	for _, ans := range a.Answers {
+		responses[ans.QuestionID] = ans.ResponseText
+	}
+	scores := make(map[uuid.UUID]*models.EvaluationScore, len(a.Scores))
+	for i := range a.Scores {
+		scores[a.Scores[i].QuestionID] = &a.Scores[i]
+	}
+	for _, q := range questions {
+		sc, ok := scores[q.ID]
+		if !ok {
+			continue
+		}
+		resp.Results = append(resp.Results, dto.AttemptAnswerResult{
+			QuestionID:     q.ID,
+			QuestionNumber: q.QuestionNumber,
+			QuestionType:   q.QuestionType,
+			ResponseText:   responses[q.ID],
+			Verdict:        sc.AdminVerdict,
+			Score:          sc.Score,
+			MaxScore:       q.MaxScore,
+			Remarks:        sc.Remarks,
+		})
+	}
+	return resp
+}
\ No newline at end of file
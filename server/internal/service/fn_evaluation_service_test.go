@@ -0,0 +1,197 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+)
+
+func choiceQuestion(qtype string, correct ...string) *models.EvaluationQuestion {
+	q := &models.EvaluationQuestion{QuestionType: qtype, QuestionNumber: 1, MaxScore: 2}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		q.Options = append(q.Options, models.EvaluationQuestionOption{OptionKey: key, IsCorrect: slices.Contains(correct, key)})
+	}
+	return q
+}
+
+func TestGradeAnswer(t *testing.T) {
+	shortAnswer := &models.EvaluationQuestion{
+		QuestionType: dto.QuestionTypeShortAnswer,
+		MaxScore:     3,
+		Options: []models.EvaluationQuestionOption{
+			{OptionText: "Gobierno Regional"},
+			{OptionText: "GRA"},
+		},
+	}
+	essay := &models.EvaluationQuestion{QuestionType: dto.QuestionTypeEssay, MaxScore: 5}
+
+	cases := []struct {
+		name         string
+		question     *models.EvaluationQuestion
+		in           dto.AttemptAnswerInput
+		wantResponse string
+		wantVerdict  string
+		wantPoints   float64
+		wantErr      string
+	}{
+		{"single choice correct", choiceQuestion(dto.QuestionTypeMultipleChoice, "b"), dto.AttemptAnswerInput{SelectedOptions: []string{"b"}}, "b", dto.VerdictCorrect, 2, ""},
+		{"single choice wrong", choiceQuestion(dto.QuestionTypeMultipleChoice, "b"), dto.AttemptAnswerInput{SelectedOptions: []string{"c"}}, "c", dto.VerdictIncorrect, 0, ""},
+		{"true/false", choiceQuestion(dto.QuestionTypeTrueFalse, "a"), dto.AttemptAnswerInput{SelectedOptions: []string{"a"}}, "a", dto.VerdictCorrect, 2, ""},
+		{"multi-select in any order", choiceQuestion(dto.QuestionTypeMultipleChoice, "a", "c"), dto.AttemptAnswerInput{SelectedOptions: []string{"C", " a"}}, "a,c", dto.VerdictCorrect, 2, ""},
+		{"multi-select missing an option", choiceQuestion(dto.QuestionTypeMultipleChoice, "a", "c"), dto.AttemptAnswerInput{SelectedOptions: []string{"a"}}, "a", dto.VerdictIncorrect, 0, ""},
+		{"multi-select with an extra option", choiceQuestion(dto.QuestionTypeMultipleChoice, "a", "c"), dto.AttemptAnswerInput{SelectedOptions: []string{"a", "b", "c"}}, "a,b,c", dto.VerdictIncorrect, 0, ""},
+		{"duplicate keys count once", choiceQuestion(dto.QuestionTypeMultipleChoice, "a", "c"), dto.AttemptAnswerInput{SelectedOptions: []string{"a", "A", "c", "c"}}, "a,c", dto.VerdictCorrect, 2, ""},
+		{"duplicates do not fake a multi-select", choiceQuestion(dto.QuestionTypeMultipleChoice, "a", "c"), dto.AttemptAnswerInput{SelectedOptions: []string{"a", "a"}}, "a", dto.VerdictIncorrect, 0, ""},
+		{"no selection", choiceQuestion(dto.QuestionTypeMultipleChoice, "a"), dto.AttemptAnswerInput{}, "", dto.VerdictIncorrect, 0, ""},
+		{"invalid option key", choiceQuestion(dto.QuestionTypeMultipleChoice, "a"), dto.AttemptAnswerInput{SelectedOptions: []string{"a", "z"}}, "", "", 0, `invalid option "z"`},
+		{"short answer normalized", shortAnswer, dto.AttemptAnswerInput{ResponseText: "  gobierno   REGIONAL "}, "gobierno   REGIONAL", dto.VerdictCorrect, 3, ""},
+		{"short answer alternative", shortAnswer, dto.AttemptAnswerInput{ResponseText: "gra"}, "gra", dto.VerdictCorrect, 3, ""},
+		{"short answer wrong", shortAnswer, dto.AttemptAnswerInput{ResponseText: "Municipalidad"}, "Municipalidad", dto.VerdictIncorrect, 0, ""},
+		{"short answer blank", shortAnswer, dto.AttemptAnswerInput{ResponseText: "   "}, "", dto.VerdictIncorrect, 0, ""},
+		{"essay waits for review", essay, dto.AttemptAnswerInput{ResponseText: " Mi respuesta "}, "Mi respuesta", dto.VerdictPending, 0, ""},
+		{"blank essay", essay, dto.AttemptAnswerInput{ResponseText: " "}, "", dto.VerdictIncorrect, 0, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, verdict, points, err := gradeAnswer(tc.question, tc.in)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("gradeAnswer: %v", err)
+			}
+			if response != tc.wantResponse || verdict != tc.wantVerdict || points != tc.wantPoints {
+				t.Fatalf("got (%q, %s, %v), want (%q, %s, %v)", response, verdict, points, tc.wantResponse, tc.wantVerdict, tc.wantPoints)
+			}
+		})
+	}
+}
+
+func TestReviewPoints(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+
+	cases := []struct {
+		name    string
+		req     dto.ScoreReviewRequest
+		want    float64
+		wantErr string
+	}{
+		{"approved without score awards the maximum", dto.ScoreReviewRequest{Verdict: dto.VerdictApproved}, 5, ""},
+		{"approved with a score", dto.ScoreReviewRequest{Verdict: dto.VerdictApproved, Score: score(4.5)}, 4.5, ""},
+		{"approved above the maximum", dto.ScoreReviewRequest{Verdict: dto.VerdictApproved, Score: score(5.5)}, 0, "invalid score"},
+		{"approved with zero", dto.ScoreReviewRequest{Verdict: dto.VerdictApproved, Score: score(0)}, 0, "invalid score"},
+		{"partial inside the bounds", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial, Score: score(2.5)}, 2.5, ""},
+		{"partial just above zero", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial, Score: score(0.01)}, 0.01, ""},
+		{"partial at zero", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial, Score: score(0)}, 0, "PARTIAL verdict must be between"},
+		{"partial at the maximum", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial, Score: score(5)}, 0, "PARTIAL verdict must be between"},
+		{"partial negative", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial, Score: score(-1)}, 0, "PARTIAL verdict must be between"},
+		{"partial without score", dto.ScoreReviewRequest{Verdict: dto.VerdictPartial}, 0, "score is required"},
+		{"rejected", dto.ScoreReviewRequest{Verdict: dto.VerdictRejected}, 0, ""},
+		{"rejected with explicit zero", dto.ScoreReviewRequest{Verdict: dto.VerdictRejected, Score: score(0)}, 0, ""},
+		{"rejected with points", dto.ScoreReviewRequest{Verdict: dto.VerdictRejected, Score: score(1)}, 0, "REJECTED verdict scores 0"},
+		{"unknown verdict", dto.ScoreReviewRequest{Verdict: dto.VerdictCorrect}, 0, "invalid verdict"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := reviewPoints(tc.req, 5)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Fatalf("got (%v, %v), want %v", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGradeAttempt(t *testing.T) {
+	now := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	evaluation := &models.Evaluation{PassingScore: 60}
+	scores := func(verdicts ...string) []models.EvaluationScore {
+		out := make([]models.EvaluationScore, 0, len(verdicts))
+		for _, v := range verdicts {
+			points := 0.0
+			switch v {
+			case dto.VerdictCorrect, dto.VerdictApproved:
+				points = 2
+			case dto.VerdictPartial:
+				points = 1
+			}
+			out = append(out, models.EvaluationScore{AdminVerdict: v, Score: points})
+		}
+		return out
+	}
+
+	cases := []struct {
+		name        string
+		scores      []models.EvaluationScore
+		wantStatus  string
+		wantScore   float64
+		wantPercent float64
+		wantPassed  bool
+	}{
+		{"pending essay keeps the attempt under review", scores(dto.VerdictCorrect, dto.VerdictPending, dto.VerdictCorrect), dto.AttemptStatusUnderReview, 0, 0, false},
+		{"fails below the passing score", scores(dto.VerdictCorrect, dto.VerdictPartial, dto.VerdictIncorrect), dto.AttemptStatusGraded, 3, 50, false},
+		{"reviewed essays count", scores(dto.VerdictCorrect, dto.VerdictApproved, dto.VerdictPartial), dto.AttemptStatusGraded, 5, 83.33, true},
+		{"exactly the passing score", scores(dto.VerdictCorrect, dto.VerdictPartial, dto.VerdictRejected, dto.VerdictApproved, dto.VerdictPartial), dto.AttemptStatusGraded, 6, 60, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempt := &models.EvaluationAttempt{Status: dto.AttemptStatusUnderReview, MaxScore: float64(2 * len(tc.scores))}
+			gradeAttempt(attempt, evaluation, tc.scores, now)
+
+			if attempt.Status != tc.wantStatus {
+				t.Fatalf("status = %s, want %s", attempt.Status, tc.wantStatus)
+			}
+			if tc.wantStatus != dto.AttemptStatusGraded {
+				if attempt.Passed != nil || attempt.GradedAt != nil {
+					t.Fatal("attempt with a pending answer was graded")
+				}
+				return
+			}
+			if attempt.Score != tc.wantScore || attempt.Percentage != tc.wantPercent {
+				t.Fatalf("score %v (%v%%), want %v (%v%%)", attempt.Score, attempt.Percentage, tc.wantScore, tc.wantPercent)
+			}
+			if attempt.Passed == nil || *attempt.Passed != tc.wantPassed || attempt.GradedAt == nil || !attempt.GradedAt.Equal(now) {
+				t.Fatalf("passed = %v, graded at %v", attempt.Passed, attempt.GradedAt)
+			}
+		})
+	}
+}
+
+func TestAttemptTimedOut(t *testing.T) {
+	expires := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		expires *time.Time
+		now     time.Time
+		want    bool
+	}{
+		{"untimed attempt", nil, expires.Add(24 * time.Hour), false},
+		{"before expiry", &expires, expires.Add(-time.Minute), false},
+		{"inside the grace period", &expires, expires.Add(submissionGrace), false},
+		{"after the grace period", &expires, expires.Add(submissionGrace + time.Second), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := attemptTimedOut(&models.EvaluationAttempt{ExpiresAt: tc.expires}, tc.now); got != tc.want {
+				t.Fatalf("attemptTimedOut = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
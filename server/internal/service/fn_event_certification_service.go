@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// CertificationConfig holds the QR placement used when certificates are issued
+// automatically. Without QR only reg_doc runs and gen_doc is left to an issuer.
+type CertificationConfig struct {
+	QR *dto.QRConfigRequest
+}
+
+// FNEventCertificationService links graded evaluation attempts to the events that require them
+type FNEventCertificationService interface {
+	// AttemptGraded marks the user eligible in every event requiring the evaluation and,
+	// where the event enables it, registers and generates the certificate (reg_doc + gen_doc)
+	AttemptGraded(ctx context.Context, attempt *models.EvaluationAttempt)
+	// StudyProgressed issues the certificates that were waiting on the study material once
+	// the user's progress crosses an event's minimum and the evaluation is already passed
+	StudyProgressed(ctx context.Context, userID uuid.UUID, previous, current *dto.StudyProgressResponse)
+	// EnsureStudyCompleted blocks a user from starting the evaluation while an event
+	// they take part in requires study material they have not completed
+	EnsureStudyCompleted(ctx context.Context, evaluationID, userID uuid.UUID) error
+}
+
+type fnEventCertificationService struct {
+	eventRepo       repository.FNEventRepository
+	participantRepo repository.FNEventParticipantRepository
+	userRepo        repository.FNUserRepository
+	userDetailRepo  repository.FNUserDetailRepository
+	studyRepo       repository.FNStudyMaterialRepository
+	docActionSvc    FNDocumentActionService
+	cfg             CertificationConfig
+}
+
+// NewFNEventCertificationService creates a new FN event certification service
+func NewFNEventCertificationService(
+	eventRepo repository.FNEventRepository,
+	participantRepo repository.FNEventParticipantRepository,
+	userRepo repository.FNUserRepository,
+	userDetailRepo repository.FNUserDetailRepository,
+	studyRepo repository.FNStudyMaterialRepository,
+	docActionSvc FNDocumentActionService,
+	cfg CertificationConfig,
+) FNEventCertificationService {
+	return &fnEventCertificationService{
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		userDetailRepo:  userDetailRepo,
+		studyRepo:       studyRepo,
+		docActionSvc:    docActionSvc,
+		cfg:             cfg,
+	}
+}
+
+// AttemptGraded is best effort: the attempt result is already stored, so failures are
+// logged and an issuer can still run the document actions by hand
+func (s *fnEventCertificationService) AttemptGraded(ctx context.Context, attempt *models.EvaluationAttempt) {
+	if attempt.Passed == nil || !*attempt.Passed {
+		return
+	}
+
+	// the taker is outside the organizers' units: events are looked up unscoped
+	ctx = orgunit.WithScope(context.WithoutCancel(ctx), orgunit.Unrestricted)
+	logger := log.With().
+		Str("attempt_id", attempt.ID.String()).
+		Str("evaluation_id", attempt.EvaluationID.String()).
+		Logger()
+
+	events, err := s.eventRepo.ListByEvaluationID(ctx, attempt.EvaluationID)
+	if err != nil {
+		logger.Error().Err(err).Msg("error fetching events requiring the evaluation")
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	userDetail, err := s.userDetailOf(ctx, attempt.UserID)
+	if err != nil {
+		logger.Error().Err(err).Msg("error fetching the attempt's beneficiary")
+		return
+	}
+	if userDetail == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	for i := range events {
+		event := &events[i]
+
+		participant, err := s.participantRepo.GetByEventAndUserDetail(ctx, event.ID, userDetail.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("error fetching participant")
+			continue
+		}
+		if participant == nil {
+			continue
+		}
+
+		marked, err := s.participantRepo.MarkEligible(ctx, participant.ID, attempt.ID, attempt.Percentage, now)
+		if err != nil {
+			logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("error marking participant eligible")
+			continue
+		}
+		// already eligible through an earlier attempt: the certificate was handled then
+		if !marked || !event.AutoIssueCertificates {
+			continue
+		}
+
+		// the evaluation pass is recorded, but gen_doc would refuse the certificate;
+		// StudyProgressed issues it once the study material is completed
+		if event.StudyMaterialID != nil {
+			progress, met, err := studyRequirement(ctx, s.studyRepo, event, attempt.UserID)
+			if err != nil {
+				logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("error checking study progress")
+				continue
+			}
+			if !met {
+				logger.Info().
+					Str("event_id", event.ID.String()).
+					Float64("study_percentage", progress.Percentage).
+					Msg("certificate deferred: study material not completed")
+				continue
+			}
+		}
+
+		s.issueCertificate(ctx, event, userDetail.ID)
+	}
+}
+
+// StudyProgressed is best effort like AttemptGraded: the progress is already stored
+func (s *fnEventCertificationService) StudyProgressed(ctx context.Context, userID uuid.UUID, previous, current *dto.StudyProgressResponse) {
+	ctx = orgunit.WithScope(context.WithoutCancel(ctx), orgunit.Unrestricted)
+	logger := log.With().
+		Str("user_id", userID.String()).
+		Str("study_material_id", current.MaterialID.String()).
+		Logger()
+
+	events, err := s.eventRepo.ListByStudyMaterialID(ctx, current.MaterialID)
+	if err != nil {
+		logger.Error().Err(err).Msg("error fetching events requiring the study material")
+		return
+	}
+
+	var userDetail *models.UserDetail
+	for i := range events {
+		event := &events[i]
+		// events without an evaluation are never issued automatically; below the minimum
+		// before, or still below it now, nothing changed for this event
+		if !event.AutoIssueCertificates || event.EvaluationID == nil ||
+			studyRequirementMet(previous, event.StudyMinPercentage) ||
+			!studyRequirementMet(current, event.StudyMinPercentage) {
+			continue
+		}
+
+		if userDetail == nil {
+			userDetail, err = s.userDetailOf(ctx, userID)
+			if err != nil {
+				logger.Error().Err(err).Msg("error fetching the learner's beneficiary")
+				return
+			}
+			if userDetail == nil {
+				return
+			}
+		}
+
+		participant, err := s.participantRepo.GetByEventAndUserDetail(ctx, event.ID, userDetail.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("event_id", event.ID.String()).Msg("error fetching participant")
+			continue
+		}
+		// not passed yet: AttemptGraded issues the certificate when the evaluation is passed
+		if participant == nil || !participant.CertificateEligible {
+			continue
+		}
+
+		s.issueCertificate(ctx, event, userDetail.ID)
+	}
+}
+
+func (s *fnEventCertificationService) EnsureStudyCompleted(ctx context.Context, evaluationID, userID uuid.UUID) error {
+	ctx = orgunit.WithScope(ctx, orgunit.Unrestricted)
+
+	events, err := s.eventRepo.ListByEvaluationID(ctx, evaluationID)
+	if err != nil {
+		return fmt.Errorf("error fetching events requiring the evaluation: %w", err)
+	}
+
+	var userDetail *models.UserDetail
+	for i := range events {
+		event := &events[i]
+		if event.StudyMaterialID == nil {
+			continue
+		}
+
+		if userDetail == nil {
+			userDetail, err = s.userDetailOf(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("error fetching beneficiary: %w", err)
+			}
+			// not a beneficiary, so not a participant of any event
+			if userDetail == nil {
+				return nil
+			}
+		}
+
+		participant, err := s.participantRepo.GetByEventAndUserDetail(ctx, event.ID, userDetail.ID)
+		if err != nil {
+			return fmt.Errorf("error fetching participant: %w", err)
+		}
+		if participant == nil {
+			continue
+		}
+
+		progress, met, err := studyRequirement(ctx, s.studyRepo, event, userID)
+		if err != nil {
+			return err
+		}
+		if !met {
+			return fmt.Errorf("evaluation blocked: event %s requires %.2f%% of its study material to be completed, you have %.2f%%",
+				event.Code, event.StudyMinPercentage, progress.Percentage)
+		}
+	}
+	return nil
+}
+
+// userDetailOf maps an account to its beneficiary: attempts belong to accounts,
+// participants to beneficiaries, and both share the national ID. Returns nil when
+// the account has no beneficiary record.
+func (s *fnEventCertificationService) userDetailOf(ctx context.Context, userID uuid.UUID) (*models.UserDetail, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return s.userDetailRepo.GetByNationalID(ctx, user.NationalID)
+}
+
+// issueCertificate runs reg_doc and gen_doc for one participant on behalf of the event's creator
+func (s *fnEventCertificationService) issueCertificate(ctx context.Context, event *models.Event, userDetailID uuid.UUID) {
+	logger := log.With().
+		Str("event_id", event.ID.String()).
+		Str("user_detail_id", userDetailID.String()).
+		Logger()
+	participants := []dto.DocumentActionParticipant{{UserDetailID: userDetailID.String()}}
+
+	// an existing document is reported as a failed item and generation goes on with it
+	if _, err := s.docActionSvc.ExecuteAction(ctx, event.CreatedBy, dto.DocumentActionRequest{
+		Action:       "reg_doc",
+		EventID:      event.ID.String(),
+		Participants: participants,
+	}); err != nil {
+		logger.Error().Err(err).Msg("error registering certificate")
+		return
+	}
+
+	if event.TemplateID == nil || s.cfg.QR == nil {
+		logger.Info().Msg("certificate registered, generation left to an issuer (no template or QR config)")
+		return
+	}
+
+	resp, err := s.docActionSvc.ExecuteAction(ctx, event.CreatedBy, dto.DocumentActionRequest{
+		Action:       "gen_doc",
+		EventID:      event.ID.String(),
+		Participants: participants,
+		QRConfig:     s.cfg.QR,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error generating certificate")
+		return
+	}
+	for _, item := range resp.Results {
+		if item.Error != nil {
+			logger.Warn().Str("error", *item.Error).Msg("certificate generation skipped")
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type stubCertEventRepo struct {
+	repository.FNEventRepository
+	event *models.Event
+}
+
+func (r *stubCertEventRepo) ListByEvaluationID(context.Context, uuid.UUID) ([]models.Event, error) {
+	return []models.Event{*r.event}, nil
+}
+
+func (r *stubCertEventRepo) ListByStudyMaterialID(context.Context, uuid.UUID) ([]models.Event, error) {
+	return []models.Event{*r.event}, nil
+}
+
+type stubCertParticipantRepo struct {
+	repository.FNEventParticipantRepository
+	participant *models.EventParticipant
+}
+
+func (r *stubCertParticipantRepo) GetByEventAndUserDetail(context.Context, uuid.UUID, uuid.UUID) (*models.EventParticipant, error) {
+	p := *r.participant
+	return &p, nil
+}
+
+func (r *stubCertParticipantRepo) MarkEligible(_ context.Context, _, attemptID uuid.UUID, finalScore float64, at time.Time) (bool, error) {
+	if r.participant.CertificateEligible {
+		return false, nil
+	}
+	r.participant.CertificateEligible = true
+	r.participant.FinalScore = &finalScore
+	r.participant.EligibleAt = &at
+	return true, nil
+}
+
+type stubCertUserRepo struct {
+	repository.FNUserRepository
+	user *models.User
+}
+
+func (r *stubCertUserRepo) GetByID(context.Context, uuid.UUID) (*models.User, error) {
+	return r.user, nil
+}
+
+type stubCertUserDetailRepo struct {
+	repository.FNUserDetailRepository
+	userDetail *models.UserDetail
+}
+
+func (r *stubCertUserDetailRepo) GetByNationalID(context.Context, string) (*models.UserDetail, error) {
+	return r.userDetail, nil
+}
+
+type stubCertStudyRepo struct {
+	repository.FNStudyMaterialRepository
+	total, completed int64
+}
+
+func (r *stubCertStudyRepo) CountSubsections(context.Context, uuid.UUID) (int64, error) {
+	return r.total, nil
+}
+
+func (r *stubCertStudyRepo) CountCompleted(context.Context, uuid.UUID, uuid.UUID) (int64, error) {
+	return r.completed, nil
+}
+
+type recordingDocActionSvc struct {
+	FNDocumentActionService
+	actions []string
+}
+
+func (s *recordingDocActionSvc) ExecuteAction(_ context.Context, _ uuid.UUID, req dto.DocumentActionRequest) (*dto.DocumentActionResponse, error) {
+	s.actions = append(s.actions, req.Action)
+	return &dto.DocumentActionResponse{Action: req.Action}, nil
+}
+
+func TestCertificateWaitsForStudyMaterial(t *testing.T) {
+	userID, evaluationID, materialID, templateID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	userDetail := &models.UserDetail{ID: uuid.New(), NationalID: "12345678"}
+	event := &models.Event{
+		ID:                    uuid.New(),
+		TemplateID:            &templateID,
+		EvaluationID:          &evaluationID,
+		AutoIssueCertificates: true,
+		StudyMaterialID:       &materialID,
+		StudyMinPercentage:    100,
+		CreatedBy:             uuid.New(),
+	}
+
+	studyRepo := &stubCertStudyRepo{total: 2, completed: 1}
+	docActions := &recordingDocActionSvc{}
+	svc := NewFNEventCertificationService(
+		&stubCertEventRepo{event: event},
+		&stubCertParticipantRepo{participant: &models.EventParticipant{ID: uuid.New(), EventID: event.ID, UserDetailID: userDetail.ID}},
+		&stubCertUserRepo{user: &models.User{ID: userID, NationalID: userDetail.NationalID}},
+		&stubCertUserDetailRepo{userDetail: userDetail},
+		studyRepo,
+		docActions,
+		CertificationConfig{QR: &dto.QRConfigRequest{}},
+	)
+	ctx := context.Background()
+
+	passed := true
+	svc.AttemptGraded(ctx, &models.EvaluationAttempt{ID: uuid.New(), EvaluationID: evaluationID, UserID: userID, Passed: &passed, Percentage: 90})
+	if len(docActions.actions) != 0 {
+		t.Fatalf("certificate issued before the study material was completed: %v", docActions.actions)
+	}
+
+	progress := func() *dto.StudyProgressResponse {
+		p, err := studyProgress(ctx, studyRepo, materialID, userID)
+		if err != nil {
+			t.Fatalf("studyProgress: %v", err)
+		}
+		return p
+	}
+
+	previous := progress()
+	studyRepo.completed = 2
+	current := progress()
+	svc.StudyProgressed(ctx, userID, previous, current)
+	if want := []string{"reg_doc", "gen_doc"}; len(docActions.actions) != 2 || docActions.actions[0] != want[0] || docActions.actions[1] != want[1] {
+		t.Fatalf("actions after completing the material = %v, want %v", docActions.actions, want)
+	}
+
+	// already above the minimum: completing more does not issue again
+	svc.StudyProgressed(ctx, userID, current, current)
+	if len(docActions.actions) != 2 {
+		t.Fatalf("certificate issued again: %v", docActions.actions)
+	}
+}
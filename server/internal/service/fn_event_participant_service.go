@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// FNEventParticipantService defines the interface for event participant business logic
+type FNEventParticipantService interface {
+	List(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]dto.EventParticipantListItem, int64, error)
+	Add(ctx context.Context, eventID uuid.UUID, req dto.EventParticipantAddRequest) (*dto.EventParticipantAddResponse, error)
+	Patch(ctx context.Context, eventID, participantID uuid.UUID, req dto.EventParticipantPatchRequest) (*dto.EventParticipantListItem, error)
+	Remove(ctx context.Context, eventID, participantID, userID uuid.UUID, params dto.EventParticipantRemoveQuery) error
+	BulkUpdateStatus(ctx context.Context, eventID uuid.UUID, req dto.EventParticipantBulkStatusRequest) (*dto.EventParticipantBulkStatusResponse, error)
+	Progress(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]dto.EventParticipantProgressItem, int64, error)
+}
+
+type fnEventParticipantService struct {
+	participantRepo repository.FNEventParticipantRepository
+	eventRepo       repository.FNEventRepository
+	userDetailRepo  repository.FNUserDetailRepository
+	docRepo         repository.FNDocumentRepository
+	userRepo        repository.FNUserRepository
+	studyRepo       repository.FNStudyMaterialRepository
+}
+
+// NewFNEventParticipantService creates a new FN event participant service
+func NewFNEventParticipantService(
+	participantRepo repository.FNEventParticipantRepository,
+	eventRepo repository.FNEventRepository,
+	userDetailRepo repository.FNUserDetailRepository,
+	docRepo repository.FNDocumentRepository,
+	userRepo repository.FNUserRepository,
+	studyRepo repository.FNStudyMaterialRepository,
+) FNEventParticipantService {
+	return &fnEventParticipantService{
+		participantRepo: participantRepo,
+		eventRepo:       eventRepo,
+		userDetailRepo:  userDetailRepo,
+		docRepo:         docRepo,
+		userRepo:        userRepo,
+		studyRepo:       studyRepo,
+	}
+}
+
+func (s *fnEventParticipantService) List(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]dto.EventParticipantListItem, int64, error) {
+	if err := s.ensureEventExists(ctx, eventID); err != nil {
+		return nil, 0, err
+	}
+
+	participants, total, err := s.participantRepo.List(ctx, eventID, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing participants: %w", err)
+	}
+
+	items := make([]dto.EventParticipantListItem, 0, len(participants))
+	for i := range participants {
+		items = append(items, s.toListItem(&participants[i]))
+	}
+
+	return items, total, nil
+}
+
+func (s *fnEventParticipantService) Add(ctx context.Context, eventID uuid.UUID, req dto.EventParticipantAddRequest) (*dto.EventParticipantAddResponse, error) {
+	if len(req.Participants) == 0 {
+		return nil, fmt.Errorf("at least one participant is required")
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	currentCount, err := s.eventRepo.CountParticipantsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting participants: %w", err)
+	}
+
+	now := time.Now().UTC()
+	resp := &dto.EventParticipantAddResponse{
+		EventID: eventID,
+		Results: make([]dto.EventParticipantAddResultItem, 0, len(req.Participants)),
+	}
+
+	for _, p := range req.Participants {
+		nationalID := strings.TrimSpace(p.NationalID)
+		result := dto.EventParticipantAddResultItem{NationalID: nationalID}
+
+		fail := func(msg string) {
+			result.Error = &msg
+			resp.Results = append(resp.Results, result)
+			resp.SkippedCount++
+		}
+
+		if nationalID == "" {
+			fail("participant national_id is required")
+			continue
+		}
+
+		if event.MaxParticipants != nil && currentCount >= int64(*event.MaxParticipants) {
+			fail(fmt.Sprintf("event has reached its maximum of %d participants", *event.MaxParticipants))
+			continue
+		}
+
+		registrationStatus, err := parseParticipantStatus(p.RegistrationStatus, dto.ParticipantRegistrationRegistered, dto.ParticipantRegistrationStatuses, "registration_status")
+		if err != nil {
+			fail(err.Error())
+			continue
+		}
+		attendanceStatus, err := parseParticipantStatus(p.AttendanceStatus, dto.ParticipantAttendancePending, dto.ParticipantAttendanceStatuses, "attendance_status")
+		if err != nil {
+			fail(err.Error())
+			continue
+		}
+
+		userDetail, err := s.userDetailRepo.GetByNationalID(ctx, nationalID)
+		if err != nil {
+			fail(fmt.Sprintf("error checking user detail: %v", err))
+			continue
+		}
+
+		// a new beneficiary is only stored together with its participation
+		var newUserDetail *models.UserDetail
+		if userDetail == nil {
+			firstName := strings.TrimSpace(p.FirstName)
+			lastName := strings.TrimSpace(p.LastName)
+			if firstName == "" || lastName == "" {
+				fail("first_name and last_name are required for a new beneficiary")
+				continue
+			}
+
+			newUserDetail = &models.UserDetail{
+				ID:         uuid.New(),
+				NationalID: nationalID,
+				FirstName:  firstName,
+				LastName:   lastName,
+				Email:      p.Email,
+				Phone:      p.Phone,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			userDetail = newUserDetail
+		} else {
+			result.UserDetailID = &userDetail.ID
+			existing, err := s.participantRepo.GetByEventAndUserDetail(ctx, eventID, userDetail.ID)
+			if err != nil {
+				fail(fmt.Sprintf("error checking participant: %v", err))
+				continue
+			}
+			if existing != nil {
+				result.ParticipantID = &existing.ID
+				fail("participant already exists in this event")
+				continue
+			}
+		}
+
+		participant := &models.EventParticipant{
+			ID:                 uuid.New(),
+			EventID:            eventID,
+			UserDetailID:       userDetail.ID,
+			RegistrationSource: p.RegistrationSource,
+			RegistrationStatus: registrationStatus,
+			AttendanceStatus:   attendanceStatus,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+
+		if err := s.participantRepo.Create(ctx, participant, newUserDetail); err != nil {
+			fail(fmt.Sprintf("error creating participant: %v", err))
+			continue
+		}
+
+		result.UserDetailID = &userDetail.ID
+		result.ParticipantID = &participant.ID
+		resp.Results = append(resp.Results, result)
+		resp.AddedCount++
+		currentCount++
+	}
+
+	return resp, nil
+}
+
+func (s *fnEventParticipantService) Patch(ctx context.Context, eventID, participantID uuid.UUID, req dto.EventParticipantPatchRequest) (*dto.EventParticipantListItem, error) {
+	participant, err := s.getParticipant(ctx, eventID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrationStatus, err := parseParticipantStatus(req.RegistrationStatus, participant.RegistrationStatus, dto.ParticipantRegistrationStatuses, "registration_status")
+	if err != nil {
+		return nil, err
+	}
+	attendanceStatus, err := parseParticipantStatus(req.AttendanceStatus, participant.AttendanceStatus, dto.ParticipantAttendanceStatuses, "attendance_status")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	// beneficiary data lives in user_details and is shared across events
+	userDetail := participant.UserDetail
+	detailChanged := false
+	if req.FirstName != nil && strings.TrimSpace(*req.FirstName) != "" {
+		userDetail.FirstName = strings.TrimSpace(*req.FirstName)
+		detailChanged = true
+	}
+	if req.LastName != nil && strings.TrimSpace(*req.LastName) != "" {
+		userDetail.LastName = strings.TrimSpace(*req.LastName)
+		detailChanged = true
+	}
+	if req.Email != nil {
+		userDetail.Email = req.Email
+		detailChanged = true
+	}
+	if req.Phone != nil {
+		userDetail.Phone = req.Phone
+		detailChanged = true
+	}
+	if detailChanged {
+		userDetail.UpdatedAt = now
+		if err := s.userDetailRepo.Update(ctx, &userDetail); err != nil {
+			return nil, fmt.Errorf("error updating user detail: %w", err)
+		}
+	}
+
+	if req.RegistrationSource != nil {
+		participant.RegistrationSource = req.RegistrationSource
+	}
+	participant.RegistrationStatus = registrationStatus
+	participant.AttendanceStatus = attendanceStatus
+	participant.UpdatedAt = now
+
+	if err := s.participantRepo.Update(ctx, participant); err != nil {
+		return nil, fmt.Errorf("error updating participant: %w", err)
+	}
+
+	updated, err := s.participantRepo.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching updated participant: %w", err)
+	}
+
+	doc, err := s.docRepo.GetByEventAndUserDetail(ctx, eventID, updated.UserDetailID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching participant document: %w", err)
+	}
+	if doc != nil {
+		updated.DocumentID = &doc.ID
+		updated.DocumentStatus = &doc.Status
+		updated.DocumentSerialCode = &doc.SerialCode
+	}
+
+	item := s.toListItem(updated)
+	return &item, nil
+}
+
+func (s *fnEventParticipantService) Remove(ctx context.Context, eventID, participantID, userID uuid.UUID, params dto.EventParticipantRemoveQuery) error {
+	reasonCode, reason, err := parseRevocationReason(params.ReasonCode, params.Reason, dto.RevocationReasonWithdrawn)
+	if err != nil {
+		return err
+	}
+
+	participant, err := s.getParticipant(ctx, eventID, participantID)
+	if err != nil {
+		return err
+	}
+
+	doc, err := s.docRepo.GetByEventAndUserDetail(ctx, eventID, participant.UserDetailID)
+	if err != nil {
+		return fmt.Errorf("error checking participant document: %w", err)
+	}
+
+	var draftDocumentID *uuid.UUID
+	var revocation *models.DocumentRevocation
+	if doc != nil {
+		switch {
+		case doc.Status == dto.DocStatusCreated:
+			// nothing was generated yet, the draft document goes away with the participant
+			draftDocumentID = &doc.ID
+		case doc.Status == dto.DocStatusRejected:
+			// already revoked, keep it as history
+		case !params.Force:
+			return fmt.Errorf("participant removal blocked: document '%s' has already been generated, use force=true to revoke it", doc.SerialCode)
+		default:
+			revocation = &models.DocumentRevocation{
+				DocumentID: doc.ID,
+				ReasonCode: reasonCode,
+				Reason:     reason,
+				RevokedBy:  userID,
+				RevokedAt:  time.Now().UTC(),
+			}
+		}
+	}
+
+	if err := s.participantRepo.Remove(ctx, participant.ID, draftDocumentID, revocation); err != nil {
+		return fmt.Errorf("error removing participant: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fnEventParticipantService) BulkUpdateStatus(ctx context.Context, eventID uuid.UUID, req dto.EventParticipantBulkStatusRequest) (*dto.EventParticipantBulkStatusResponse, error) {
+	if len(req.ParticipantIDs) == 0 {
+		return nil, fmt.Errorf("participant_ids is required")
+	}
+
+	registrationStatus, err := parseParticipantStatus(req.RegistrationStatus, "", dto.ParticipantRegistrationStatuses, "registration_status")
+	if err != nil {
+		return nil, err
+	}
+	attendanceStatus, err := parseParticipantStatus(req.AttendanceStatus, "", dto.ParticipantAttendanceStatuses, "attendance_status")
+	if err != nil {
+		return nil, err
+	}
+	if registrationStatus == "" && attendanceStatus == "" {
+		return nil, fmt.Errorf("registration_status or attendance_status is required")
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.ParticipantIDs))
+	for _, raw := range req.ParticipantIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid participant id: %s", raw)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := s.ensureEventExists(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.participantRepo.BulkUpdateStatus(ctx, eventID, ids, optionalString(registrationStatus), optionalString(attendanceStatus))
+	if err != nil {
+		return nil, fmt.Errorf("error updating participants: %w", err)
+	}
+
+	return &dto.EventParticipantBulkStatusResponse{
+		EventID:      eventID,
+		UpdatedCount: updated,
+	}, nil
+}
+
+// Progress reports, per participant, the study material completion and evaluation
+// result the event requires and the first stage still blocking the certificate
+func (s *fnEventParticipantService) Progress(ctx context.Context, eventID uuid.UUID, params dto.EventParticipantListQuery) ([]dto.EventParticipantProgressItem, int64, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil, 0, fmt.Errorf("event not found")
+	}
+
+	participants, total, err := s.participantRepo.List(ctx, eventID, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing participants: %w", err)
+	}
+
+	items := make([]dto.EventParticipantProgressItem, 0, len(participants))
+	for i := range participants {
+		item, err := s.toProgressItem(ctx, event, &participants[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+
+	return items, total, nil
+}
+
+// -- helper methods
+
+func (s *fnEventParticipantService) ensureEventExists(ctx context.Context, eventID uuid.UUID) error {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return fmt.Errorf("event not found")
+	}
+	return nil
+}
+
+func (s *fnEventParticipantService) getParticipant(ctx context.Context, eventID, participantID uuid.UUID) (*models.EventParticipant, error) {
+	participant, err := s.participantRepo.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching participant: %w", err)
+	}
+	if participant == nil || participant.EventID != eventID {
+		return nil, fmt.Errorf("participant not found")
+	}
+	return participant, nil
+}
+
+// parseParticipantStatus returns the requested status, or current when none was sent
+func parseParticipantStatus(value *string, current string, allowed map[string]bool, field string) (string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return current, nil
+	}
+	status := strings.ToUpper(strings.TrimSpace(*value))
+	if !allowed[status] {
+		return "", fmt.Errorf("invalid %s: %s", field, status)
+	}
+	return status, nil
+}
+
+// optionalString returns nil for an empty value
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func (s *fnEventParticipantService) toListItem(p *models.EventParticipant) dto.EventParticipantListItem {
+	item := dto.EventParticipantListItem{
+		ID:                  p.ID,
+		EventID:             p.EventID,
+		RegistrationSource:  p.RegistrationSource,
+		RegistrationStatus:  p.RegistrationStatus,
+		AttendanceStatus:    p.AttendanceStatus,
+		CertificateEligible: p.CertificateEligible,
+		EligibleAt:          p.EligibleAt,
+		FinalScore:          p.FinalScore,
+		CreatedAt:           p.CreatedAt,
+		UpdatedAt:           p.UpdatedAt,
+		UserDetail: dto.UserDetailEmbedded{
+			ID:         p.UserDetail.ID,
+			NationalID: p.UserDetail.NationalID,
+			FirstName:  p.UserDetail.FirstName,
+			LastName:   p.UserDetail.LastName,
+			Email:      p.UserDetail.Email,
+			Phone:      p.UserDetail.Phone,
+		},
+	}
+
+	if p.DocumentID != nil {
+		item.DocumentID = p.DocumentID
+		item.DocumentStatus = p.DocumentStatus
+		item.SerialCode = p.DocumentSerialCode
+	}
+
+	return item
+}
+
+// toProgressItem checks the participant against the event's requirements. Study
+// progress and evaluation attempts belong to the account sharing the beneficiary's
+// national ID, so without one neither can be completed.
+func (s *fnEventParticipantService) toProgressItem(ctx context.Context, event *models.Event, p *models.EventParticipant) (dto.EventParticipantProgressItem, error) {
+	item := dto.EventParticipantProgressItem{
+		ParticipantID: p.ID,
+		UserDetail: dto.UserDetailEmbedded{
+			ID:         p.UserDetail.ID,
+			NationalID: p.UserDetail.NationalID,
+			FirstName:  p.UserDetail.FirstName,
+			LastName:   p.UserDetail.LastName,
+			Email:      p.UserDetail.Email,
+			Phone:      p.UserDetail.Phone,
+		},
+		StudyCompleted:   event.StudyMaterialID == nil,
+		EvaluationPassed: p.CertificateEligible,
+		FinalScore:       p.FinalScore,
+	}
+
+	user, err := s.userRepo.GetByNationalID(ctx, p.UserDetail.NationalID)
+	if err != nil {
+		return item, fmt.Errorf("error fetching user account: %w", err)
+	}
+	item.HasAccount = user != nil
+
+	if event.StudyMaterialID != nil && user != nil {
+		progress, met, err := studyRequirement(ctx, s.studyRepo, event, user.ID)
+		if err != nil {
+			return item, err
+		}
+		item.Study = progress
+		item.StudyCompleted = met
+	}
+
+	blockedAt := ""
+	switch {
+	case !item.HasAccount && (event.StudyMaterialID != nil || event.EvaluationID != nil):
+		blockedAt = dto.ProgressBlockedAccount
+	case !item.StudyCompleted:
+		blockedAt = dto.ProgressBlockedStudy
+	case event.EvaluationID != nil && !p.CertificateEligible:
+		blockedAt = dto.ProgressBlockedEvaluation
+	}
+	if blockedAt != "" {
+		item.BlockedAt = &blockedAt
+	} else {
+		item.Eligible = true
+	}
+
+	return item, nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type removeParticipantRepo struct {
+	repository.FNEventParticipantRepository
+	participant *models.EventParticipant
+
+	removedID  uuid.UUID
+	draftID    *uuid.UUID
+	revocation *models.DocumentRevocation
+}
+
+func (r *removeParticipantRepo) GetByID(_ context.Context, id uuid.UUID) (*models.EventParticipant, error) {
+	if r.participant == nil || r.participant.ID != id {
+		return nil, nil
+	}
+	return r.participant, nil
+}
+
+func (r *removeParticipantRepo) Remove(_ context.Context, id uuid.UUID, draftDocumentID *uuid.UUID, revocation *models.DocumentRevocation) error {
+	r.removedID = id
+	r.draftID = draftDocumentID
+	r.revocation = revocation
+	return nil
+}
+
+type stubParticipantDocRepo struct {
+	repository.FNDocumentRepository
+	doc *models.Document
+}
+
+func (r *stubParticipantDocRepo) GetByEventAndUserDetail(context.Context, uuid.UUID, uuid.UUID) (*models.Document, error) {
+	return r.doc, nil
+}
+
+func TestRemoveParticipantRevokesGeneratedDocument(t *testing.T) {
+	eventID, userID := uuid.New(), uuid.New()
+	participant := &models.EventParticipant{ID: uuid.New(), EventID: eventID, UserDetailID: uuid.New()}
+	doc := &models.Document{ID: uuid.New(), SerialCode: "CERT-1", Status: dto.DocStatusPDFCompleted}
+
+	newService := func() (*removeParticipantRepo, FNEventParticipantService) {
+		repo := &removeParticipantRepo{participant: participant}
+		return repo, NewFNEventParticipantService(repo, nil, nil, &stubParticipantDocRepo{doc: doc}, nil, nil)
+	}
+	ctx := context.Background()
+
+	repo, svc := newService()
+	err := svc.Remove(ctx, eventID, participant.ID, userID, dto.EventParticipantRemoveQuery{})
+	if err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Fatalf("removal without force: got %v, want blocked", err)
+	}
+	if repo.removedID != uuid.Nil {
+		t.Fatal("participant removed without force")
+	}
+
+	repo, svc = newService()
+	if err := svc.Remove(ctx, eventID, participant.ID, userID, dto.EventParticipantRemoveQuery{Force: true}); err != nil {
+		t.Fatalf("forced removal: %v", err)
+	}
+	rev := repo.revocation
+	if rev == nil {
+		t.Fatal("forced removal recorded no revocation")
+	}
+	if rev.DocumentID != doc.ID || rev.RevokedBy != userID || rev.ReasonCode != dto.RevocationReasonWithdrawn {
+		t.Fatalf("unexpected revocation: doc=%s by=%s reason=%s", rev.DocumentID, rev.RevokedBy, rev.ReasonCode)
+	}
+	if repo.draftID != nil {
+		t.Fatal("generated document was deleted as a draft")
+	}
+
+	fraud, note := "fraud", "  duplicated enrolment  "
+	repo, svc = newService()
+	params := dto.EventParticipantRemoveQuery{Force: true, ReasonCode: &fraud, Reason: &note}
+	if err := svc.Remove(ctx, eventID, participant.ID, userID, params); err != nil {
+		t.Fatalf("forced removal with reason: %v", err)
+	}
+	if rev := repo.revocation; rev.ReasonCode != dto.RevocationReasonFraud || rev.Reason == nil || *rev.Reason != "duplicated enrolment" {
+		t.Fatalf("reason not recorded: %+v", rev)
+	}
+
+	bogus := "BECAUSE"
+	_, svc = newService()
+	params = dto.EventParticipantRemoveQuery{Force: true, ReasonCode: &bogus}
+	if err := svc.Remove(ctx, eventID, participant.ID, userID, params); err == nil || !strings.Contains(err.Error(), "invalid reason_code") {
+		t.Fatalf("unknown reason code: got %v", err)
+	}
+}
+
+type stubParticipantEventRepo struct {
+	repository.FNEventRepository
+	event *models.Event
+}
+
+func (r *stubParticipantEventRepo) GetByID(context.Context, uuid.UUID) (*models.Event, error) {
+	return r.event, nil
+}
+
+func (r *stubParticipantEventRepo) CountParticipantsByEventID(context.Context, uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+type stubParticipantUserDetailRepo struct {
+	repository.FNUserDetailRepository
+	existing *models.UserDetail
+}
+
+func (r *stubParticipantUserDetailRepo) GetByNationalID(_ context.Context, nationalID string) (*models.UserDetail, error) {
+	if r.existing != nil && r.existing.NationalID == nationalID {
+		return r.existing, nil
+	}
+	return nil, nil
+}
+
+type addParticipantRepo struct {
+	repository.FNEventParticipantRepository
+	participant *models.EventParticipant
+
+	created        []*models.EventParticipant
+	newUserDetails []*models.UserDetail
+	updated        *models.EventParticipant
+}
+
+func (r *addParticipantRepo) GetByEventAndUserDetail(context.Context, uuid.UUID, uuid.UUID) (*models.EventParticipant, error) {
+	return nil, nil
+}
+
+func (r *addParticipantRepo) Create(_ context.Context, participant *models.EventParticipant, newUserDetail *models.UserDetail) error {
+	r.created = append(r.created, participant)
+	if newUserDetail != nil {
+		r.newUserDetails = append(r.newUserDetails, newUserDetail)
+	}
+	return nil
+}
+
+func (r *addParticipantRepo) GetByID(context.Context, uuid.UUID) (*models.EventParticipant, error) {
+	return r.participant, nil
+}
+
+func (r *addParticipantRepo) Update(_ context.Context, participant *models.EventParticipant) error {
+	r.updated = participant
+	return nil
+}
+
+func TestAddParticipantsValidatesStatusesAndCreatesBeneficiaryWithParticipation(t *testing.T) {
+	existing := &models.UserDetail{ID: uuid.New(), NationalID: "11111111"}
+	repo := &addParticipantRepo{}
+	svc := NewFNEventParticipantService(
+		repo,
+		&stubParticipantEventRepo{event: &models.Event{ID: uuid.New()}},
+		&stubParticipantUserDetailRepo{existing: existing},
+		nil, nil, nil,
+	)
+	status := func(s string) *string { return &s }
+
+	resp, err := svc.Add(context.Background(), uuid.New(), dto.EventParticipantAddRequest{
+		Participants: []dto.EventParticipantCreateRequest{
+			{NationalID: "11111111", AttendanceStatus: status("attended")},
+			{NationalID: "22222222", FirstName: "Ana", LastName: "Pérez"},
+			{NationalID: "33333333", FirstName: "Luis", LastName: "Díaz", RegistrationStatus: status("PAID")},
+			{NationalID: "44444444", FirstName: "Eva", LastName: "Ruiz", AttendanceStatus: status("LATE")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if resp.AddedCount != 2 || resp.SkippedCount != 2 {
+		t.Fatalf("added %d, skipped %d, want 2 and 2", resp.AddedCount, resp.SkippedCount)
+	}
+	for i, want := range []string{"invalid registration_status", "invalid attendance_status"} {
+		if got := resp.Results[2+i].Error; got == nil || !strings.Contains(*got, want) {
+			t.Errorf("result %d error = %v, want %q", 2+i, got, want)
+		}
+	}
+
+	if len(repo.created) != 2 || repo.created[0].AttendanceStatus != dto.ParticipantAttendanceAttended ||
+		repo.created[1].RegistrationStatus != dto.ParticipantRegistrationRegistered {
+		t.Fatalf("created participants = %+v", repo.created)
+	}
+	// only the new beneficiary travels with its participation; rejected rows create nothing
+	if len(repo.newUserDetails) != 1 || repo.newUserDetails[0].NationalID != "22222222" ||
+		repo.created[1].UserDetailID != repo.newUserDetails[0].ID {
+		t.Fatalf("new user details = %+v", repo.newUserDetails)
+	}
+}
+
+func TestPatchParticipantRejectsUnknownStatus(t *testing.T) {
+	eventID := uuid.New()
+	repo := &addParticipantRepo{participant: &models.EventParticipant{
+		ID:                 uuid.New(),
+		EventID:            eventID,
+		RegistrationStatus: dto.ParticipantRegistrationRegistered,
+		AttendanceStatus:   dto.ParticipantAttendancePending,
+	}}
+	svc := NewFNEventParticipantService(repo, nil, nil, nil, nil, nil)
+	status := "MAYBE"
+
+	_, err := svc.Patch(context.Background(), eventID, repo.participant.ID, dto.EventParticipantPatchRequest{AttendanceStatus: &status})
+	if err == nil || !strings.Contains(err.Error(), "invalid attendance_status") {
+		t.Fatalf("got %v, want invalid attendance_status", err)
+	}
+	if repo.updated != nil {
+		t.Fatal("participant updated with an unknown status")
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
 
 	"server/internal/domain/models"
+	"server/internal/domain/orgunit"
 	"server/internal/dto"
 	"server/internal/repository"
 )
@@ -21,18 +23,32 @@ type FNEventService interface {
 	List(ctx context.Context, params dto.EventListQuery) ([]dto.EventListItem, int64, error)
 	Update(ctx context.Context, id uuid.UUID, req dto.EventUpdateRequest) (*dto.EventResponse, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) (*dto.EventResponse, error)
+	Purge(ctx context.Context, id uuid.UUID) error
 }
 
 type fnEventService struct {
 	eventRepo      repository.FNEventRepository
 	userDetailRepo repository.FNUserDetailRepository
+	evaluationRepo repository.FNEvaluationRepository
+	studyRepo      repository.FNStudyMaterialRepository
+	natsConn       *nats.Conn
 }
 
 // NewFNEventService creates a new FN event service
-func NewFNEventService(eventRepo repository.FNEventRepository, userDetailRepo repository.FNUserDetailRepository) FNEventService {
+func NewFNEventService(
+	eventRepo repository.FNEventRepository,
+	userDetailRepo repository.FNUserDetailRepository,
+	evaluationRepo repository.FNEvaluationRepository,
+	studyRepo repository.FNStudyMaterialRepository,
+	natsConn *nats.Conn,
+) FNEventService {
 	return &fnEventService{
 		eventRepo:      eventRepo,
 		userDetailRepo: userDetailRepo,
+		evaluationRepo: evaluationRepo,
+		studyRepo:      studyRepo,
+		natsConn:       natsConn,
 	}
 }
 
@@ -71,6 +87,10 @@ func (s *fnEventService) Create(ctx context.Context, userID uuid.UUID, req dto.E
 	if req.OrganizationalUnitsPath != nil {
 		organizationalUnitsPath = *req.OrganizationalUnitsPath
 	}
+	organizationalUnitsPath, err = resolveOrgUnitPath(ctx, organizationalUnitsPath, false)
+	if err != nil {
+		return nil, err
+	}
 
 	var templateID *uuid.UUID
 	if req.TemplateID != nil && *req.TemplateID != "" {
@@ -81,6 +101,35 @@ func (s *fnEventService) Create(ctx context.Context, userID uuid.UUID, req dto.E
 		templateID = &tid
 	}
 
+	var evaluationID *uuid.UUID
+	if req.EvaluationID != nil && *req.EvaluationID != "" {
+		evaluationID, err = s.resolveEvaluation(ctx, *req.EvaluationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	autoIssue := req.AutoIssueCertificates != nil && *req.AutoIssueCertificates
+	if autoIssue && evaluationID == nil {
+		return nil, fmt.Errorf("invalid auto_issue_certificates: the event requires an evaluation")
+	}
+
+	var studyMaterialID *uuid.UUID
+	if req.StudyMaterialID != nil && *req.StudyMaterialID != "" {
+		studyMaterialID, err = s.resolveStudyMaterial(ctx, *req.StudyMaterialID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	studyMinPercentage := 100.0
+	if req.StudyMinPercentage != nil {
+		studyMinPercentage = *req.StudyMinPercentage
+	}
+	if err := validateStudyMinPercentage(studyMinPercentage); err != nil {
+		return nil, err
+	}
+
 	event := &models.Event{
 		ID:                      uuid.New(),
 		Code:                    code,
@@ -91,6 +140,10 @@ func (s *fnEventService) Create(ctx context.Context, userID uuid.UUID, req dto.E
 		CertificateSeries:       certificateSeries,
 		OrganizationalUnitsPath: organizationalUnitsPath,
 		TemplateID:              templateID,
+		EvaluationID:            evaluationID,
+		AutoIssueCertificates:   autoIssue,
+		StudyMaterialID:         studyMaterialID,
+		StudyMinPercentage:      studyMinPercentage,
 		MaxParticipants:         req.MaxParticipants,
 		RegistrationOpenAt:      req.RegistrationOpenAt,
 		RegistrationCloseAt:     req.RegistrationCloseAt,
@@ -293,7 +346,11 @@ func (s *fnEventService) Update(ctx context.Context, id uuid.UUID, req dto.Event
 	}
 
 	if req.OrganizationalUnitsPath != nil {
-		event.OrganizationalUnitsPath = *req.OrganizationalUnitsPath
+		path, err := resolveOrgUnitPath(ctx, *req.OrganizationalUnitsPath, false)
+		if err != nil {
+			return nil, err
+		}
+		event.OrganizationalUnitsPath = path
 	}
 
 	if req.TemplateID != nil {
@@ -308,6 +365,44 @@ func (s *fnEventService) Update(ctx context.Context, id uuid.UUID, req dto.Event
 		}
 	}
 
+	if req.EvaluationID != nil {
+		if *req.EvaluationID == "" {
+			event.EvaluationID = nil
+		} else {
+			evaluationID, err := s.resolveEvaluation(ctx, *req.EvaluationID)
+			if err != nil {
+				return nil, err
+			}
+			event.EvaluationID = evaluationID
+		}
+	}
+
+	if req.AutoIssueCertificates != nil {
+		event.AutoIssueCertificates = *req.AutoIssueCertificates
+	}
+	if event.AutoIssueCertificates && event.EvaluationID == nil {
+		return nil, fmt.Errorf("invalid auto_issue_certificates: the event requires an evaluation")
+	}
+
+	if req.StudyMaterialID != nil {
+		if *req.StudyMaterialID == "" {
+			event.StudyMaterialID = nil
+		} else {
+			studyMaterialID, err := s.resolveStudyMaterial(ctx, *req.StudyMaterialID)
+			if err != nil {
+				return nil, err
+			}
+			event.StudyMaterialID = studyMaterialID
+		}
+	}
+
+	if req.StudyMinPercentage != nil {
+		if err := validateStudyMinPercentage(*req.StudyMinPercentage); err != nil {
+			return nil, err
+		}
+		event.StudyMinPercentage = *req.StudyMinPercentage
+	}
+
 	if req.MaxParticipants != nil {
 		event.MaxParticipants = req.MaxParticipants
 	}
@@ -320,6 +415,7 @@ func (s *fnEventService) Update(ctx context.Context, id uuid.UUID, req dto.Event
 		event.RegistrationCloseAt = req.RegistrationCloseAt
 	}
 
+	previousStatus := event.Status
 	if req.Status != nil {
 		event.Status = strings.TrimSpace(*req.Status)
 	}
@@ -330,6 +426,14 @@ func (s *fnEventService) Update(ctx context.Context, id uuid.UUID, req dto.Event
 		return nil, fmt.Errorf("error updating event: %w", err)
 	}
 
+	if event.Status != previousStatus {
+		publishDomainEvent(s.natsConn, dto.DomainEventEventStatusChanged, dto.EventStatusChangedPayload{
+			EventID:        event.ID,
+			PreviousStatus: previousStatus,
+			Status:         event.Status,
+		})
+	}
+
 	updated, err := s.eventRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching updated event: %w", err)
@@ -350,6 +454,60 @@ func (s *fnEventService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.eventRepo.Delete(ctx, id)
 }
 
+func (s *fnEventService) Restore(ctx context.Context, id uuid.UUID) (*dto.EventResponse, error) {
+	event, err := s.eventRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("deleted event not found")
+	}
+
+	// the code may have been reused while the event was deleted
+	if event.Code != "" {
+		exists, err := s.eventRepo.ExistsByCodeExcludingID(ctx, event.Code, id)
+		if err != nil {
+			return nil, fmt.Errorf("error checking code: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("event with code '%s' already exists", event.Code)
+		}
+	}
+
+	if err := s.eventRepo.Restore(ctx, id); err != nil {
+		return nil, fmt.Errorf("error restoring event: %w", err)
+	}
+
+	restored, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching restored event: %w", err)
+	}
+
+	return s.toResponse(restored), nil
+}
+
+// Purge permanently removes a deleted event with its schedules, participants and
+// draft documents. Events with issued documents cannot be purged.
+func (s *fnEventService) Purge(ctx context.Context, id uuid.UUID) error {
+	event, err := s.eventRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return fmt.Errorf("deleted event not found")
+	}
+
+	issued, err := s.eventRepo.CountIssuedDocuments(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error counting event documents: %w", err)
+	}
+	if issued > 0 {
+		return fmt.Errorf("event purge blocked: %d documents have already been issued", issued)
+	}
+
+	return s.eventRepo.Purge(ctx, id)
+}
+
 func (s *fnEventService) toResponse(e *models.Event) *dto.EventResponse {
 	if e == nil {
 		return nil
@@ -367,6 +525,10 @@ func (s *fnEventService) toResponse(e *models.Event) *dto.EventResponse {
 		MaxParticipants:         e.MaxParticipants,
 		RegistrationOpenAt:      e.RegistrationOpenAt,
 		RegistrationCloseAt:     e.RegistrationCloseAt,
+		EvaluationID:            e.EvaluationID,
+		AutoIssueCertificates:   e.AutoIssueCertificates,
+		StudyMaterialID:         e.StudyMaterialID,
+		StudyMinPercentage:      e.StudyMinPercentage,
 		Status:                  e.Status,
 		CreatedBy:               e.CreatedBy,
 		CreatedAt:               e.CreatedAt,
@@ -397,11 +559,13 @@ func (s *fnEventService) toResponse(e *models.Event) *dto.EventResponse {
 		resp.Participants = make([]dto.EventParticipantResponse, 0, len(e.EventParticipants))
 		for _, p := range e.EventParticipants {
 			resp.Participants = append(resp.Participants, dto.EventParticipantResponse{
-				ID:                 p.ID,
-				RegistrationSource: p.RegistrationSource,
-				RegistrationStatus: p.RegistrationStatus,
-				AttendanceStatus:   p.AttendanceStatus,
-				CreatedAt:          p.CreatedAt,
+				ID:                  p.ID,
+				RegistrationSource:  p.RegistrationSource,
+				RegistrationStatus:  p.RegistrationStatus,
+				AttendanceStatus:    p.AttendanceStatus,
+				CertificateEligible: p.CertificateEligible,
+				FinalScore:          p.FinalScore,
+				CreatedAt:           p.CreatedAt,
 				UserDetail: dto.UserDetailEmbedded{
 					ID:         p.UserDetail.ID,
 					NationalID: p.UserDetail.NationalID,
@@ -415,4 +579,64 @@ func (s *fnEventService) toResponse(e *models.Event) *dto.EventResponse {
 	}
 
 	return resp
+}
+
+// resolveEvaluation parses the evaluation required by an event and checks it exists
+func (s *fnEventService) resolveEvaluation(ctx context.Context, raw string) (*uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evaluation_id: must be a valid UUID")
+	}
+	evaluation, err := s.evaluationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching evaluation: %w", err)
+	}
+	if evaluation == nil {
+		return nil, fmt.Errorf("evaluation not found")
+	}
+	return &id, nil
+}
+
+// resolveStudyMaterial parses the study material required by an event and checks it exists
+func (s *fnEventService) resolveStudyMaterial(ctx context.Context, raw string) (*uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid study_material_id: must be a valid UUID")
+	}
+	material, err := s.studyRepo.GetMaterial(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching study material: %w", err)
+	}
+	if material == nil {
+		return nil, fmt.Errorf("study material not found")
+	}
+	return &id, nil
+}
+
+// validateStudyMinPercentage checks the share of subsections a participant must complete
+func validateStudyMinPercentage(pct float64) error {
+	if pct <= 0 || pct > 100 {
+		return fmt.Errorf("invalid study_min_percentage: must be greater than 0 and at most 100")
+	}
+	return nil
+}
+
+// resolveOrgUnitPath normalizes the unit path of a new or updated row and checks it
+// against the caller's scope. Empty paths default to the caller's unit; when
+// allowShared is set an empty path is kept (rows shared by every unit).
+func resolveOrgUnitPath(ctx context.Context, path string, allowShared bool) (string, error) {
+	scope := orgunit.FromContext(ctx)
+	path = orgunit.Normalize(path)
+
+	if path == "" {
+		if allowShared || scope.Bypass {
+			return "", nil
+		}
+		path = scope.Default()
+	}
+
+	if !scope.Allows(path) {
+		return "", fmt.Errorf("access denied: organizational unit outside your scope")
+	}
+	return path, nil
 }
\ No newline at end of file
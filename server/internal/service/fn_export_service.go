@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/repository"
+	"server/pkg/shared/export"
+)
+
+// register columns (padrón de certificados)
+var registerColumns = []string{
+	"N°",
+	"DNI",
+	"Apellidos",
+	"Nombres",
+	"Correo",
+	"Inscripción",
+	"Asistencia",
+	"Código de serie",
+	"Código de verificación",
+	"Estado",
+	"Estado de firma",
+	"Fecha de emisión",
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FNExportService defines the interface for roster and certificate register exports
+type FNExportService interface {
+	PrepareEventRegister(ctx context.Context, eventID uuid.UUID, query dto.DocumentRegisterExportQuery) (*dto.DocumentRegisterExportInfo, error)
+	WriteEventRegister(ctx context.Context, eventID uuid.UUID, query dto.DocumentRegisterExportQuery, info *dto.DocumentRegisterExportInfo, w io.Writer) error
+}
+
+type fnExportService struct {
+	eventRepo       repository.FNEventRepository
+	participantRepo repository.FNEventParticipantRepository
+}
+
+// NewFNExportService creates a new FN export service
+func NewFNExportService(eventRepo repository.FNEventRepository, participantRepo repository.FNEventParticipantRepository) FNExportService {
+	return &fnExportService{
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+	}
+}
+
+// PrepareEventRegister validates the export request and resolves the file metadata
+// so headers can be sent before the body is streamed
+func (s *fnExportService) PrepareEventRegister(ctx context.Context, eventID uuid.UUID, query dto.DocumentRegisterExportQuery) (*dto.DocumentRegisterExportInfo, error) {
+	if !export.IsSupportedFormat(query.Format) {
+		return nil, fmt.Errorf("invalid format: must be one of csv, xlsx, pdf")
+	}
+	if query.Scope != dto.RegisterScopeRoster && query.Scope != dto.RegisterScopeRegister {
+		return nil, fmt.Errorf("invalid scope: must be one of roster, register")
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	base := event.Code
+	if base == "" {
+		base = event.ID.String()
+	}
+	base = unsafeFileNameChars.ReplaceAllString(base, "_")
+	format := strings.ToLower(query.Format)
+
+	return &dto.DocumentRegisterExportInfo{
+		FileName:    fmt.Sprintf("%s_%s_%s.%s", query.Scope, base, time.Now().Format("20060102"), format),
+		ContentType: export.ContentType(format),
+		EventTitle:  event.Title,
+		EventCode:   event.Code,
+	}, nil
+}
+
+// WriteEventRegister streams the register rows into w in the requested format
+func (s *fnExportService) WriteEventRegister(ctx context.Context, eventID uuid.UUID, query dto.DocumentRegisterExportQuery, info *dto.DocumentRegisterExportInfo, w io.Writer) error {
+	title := "Padrón de certificados"
+	if query.Scope == dto.RegisterScopeRoster {
+		title = "Padrón de participantes"
+	}
+
+	tw, err := export.NewTableWriter(query.Format, w, export.Options{
+		Title:    fmt.Sprintf("%s - %s", title, info.EventTitle),
+		Subtitle: fmt.Sprintf("Evento %s · generado el %s", info.EventCode, time.Now().Format("02/01/2006 15:04")),
+		Sheet:    title,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(registerColumns); err != nil {
+		return err
+	}
+
+	n := 0
+	err = s.participantRepo.StreamRegister(ctx, eventID, query.Filters, query.Scope == dto.RegisterScopeRegister, func(row dto.DocumentRegisterRow) error {
+		n++
+		return tw.WriteRow([]string{
+			strconv.Itoa(n),
+			row.NationalID,
+			row.LastName,
+			row.FirstName,
+			derefString(row.Email),
+			row.RegistrationStatus,
+			row.AttendanceStatus,
+			derefString(row.SerialCode),
+			derefString(row.VerificationCode),
+			derefString(row.Status),
+			derefString(row.DigitalSignatureStatus),
+			formatDate(row.IssueDate),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error streaming register: %w", err)
+	}
+
+	return tw.Close()
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type stubExportEventRepo struct {
+	repository.FNEventRepository
+	event *models.Event
+}
+
+func (r *stubExportEventRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Event, error) {
+	if r.event == nil || r.event.ID != id {
+		return nil, nil
+	}
+	return r.event, nil
+}
+
+type stubRegisterRepo struct {
+	repository.FNEventParticipantRepository
+	rows             []dto.DocumentRegisterRow
+	onlyWithDocument bool
+}
+
+func (r *stubRegisterRepo) StreamRegister(_ context.Context, _ uuid.UUID, _ dto.DocumentListQuery, onlyWithDocument bool, fn func(row dto.DocumentRegisterRow) error) error {
+	r.onlyWithDocument = onlyWithDocument
+	for _, row := range r.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPrepareEventRegister(t *testing.T) {
+	event := &models.Event{ID: uuid.New(), Code: "EV 2026/01", Title: "Curso"}
+	svc := NewFNExportService(&stubExportEventRepo{event: event}, &stubRegisterRepo{})
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		eventID uuid.UUID
+		query   dto.DocumentRegisterExportQuery
+		wantErr string
+	}{
+		{"unknown format", event.ID, dto.DocumentRegisterExportQuery{Format: "ods", Scope: dto.RegisterScopeRoster}, "invalid format"},
+		{"unknown scope", event.ID, dto.DocumentRegisterExportQuery{Format: "csv", Scope: "all"}, "invalid scope"},
+		{"unknown event", uuid.New(), dto.DocumentRegisterExportQuery{Format: "csv", Scope: dto.RegisterScopeRoster}, "event not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.PrepareEventRegister(ctx, tc.eventID, tc.query); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	info, err := svc.PrepareEventRegister(ctx, event.ID, dto.DocumentRegisterExportQuery{Format: "XLSX", Scope: dto.RegisterScopeRegister})
+	if err != nil {
+		t.Fatalf("PrepareEventRegister: %v", err)
+	}
+	want := "register_EV_2026_01_" + time.Now().Format("20060102") + ".xlsx"
+	if info.FileName != want || !strings.Contains(info.ContentType, "spreadsheetml") {
+		t.Fatalf("info = %+v, want file %s", info, want)
+	}
+}
+
+func TestWriteEventRegisterCSV(t *testing.T) {
+	serial, issued := "CERT-1", time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+	repo := &stubRegisterRepo{rows: []dto.DocumentRegisterRow{
+		{NationalID: "12345678", LastName: "Pérez", FirstName: "Ana", RegistrationStatus: "REGISTERED", AttendanceStatus: "ATTENDED", SerialCode: &serial, IssueDate: &issued},
+		{NationalID: "87654321", LastName: "=HYPERLINK(\"http://x\")", FirstName: "Luis"},
+	}}
+	svc := NewFNExportService(&stubExportEventRepo{}, repo)
+	query := dto.DocumentRegisterExportQuery{Format: "csv", Scope: dto.RegisterScopeRegister}
+
+	var buf bytes.Buffer
+	if err := svc.WriteEventRegister(context.Background(), uuid.New(), query, &dto.DocumentRegisterExportInfo{}, &buf); err != nil {
+		t.Fatalf("WriteEventRegister: %v", err)
+	}
+	if !repo.onlyWithDocument {
+		t.Fatal("register scope must only stream participants with a document")
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(buf.Bytes(), []byte{0xEF, 0xBB, 0xBF}))).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != len(registerColumns) {
+		t.Fatalf("records = %q", records)
+	}
+	if got := records[1]; got[0] != "1" || got[2] != "Pérez" || got[7] != serial || got[11] != "09/03/2026" {
+		t.Fatalf("first row = %q", got)
+	}
+	if got := records[2][2]; got != "'=HYPERLINK(\"http://x\")" {
+		t.Fatalf("formula not neutralized: %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// FNMeService defines the interface for the beneficiary portal ("my certificates")
+type FNMeService interface {
+	ListDocuments(ctx context.Context, nationalID string, params dto.MyDocumentListQuery) ([]dto.MyDocumentItem, int64, error)
+	ListEvents(ctx context.Context, nationalID string, params dto.MyEventListQuery) ([]dto.MyEventItem, int64, error)
+}
+
+type fnMeService struct {
+	userDetailRepo  repository.FNUserDetailRepository
+	docRepo         repository.FNDocumentRepository
+	participantRepo repository.FNEventParticipantRepository
+}
+
+// NewFNMeService creates a new FN me service
+func NewFNMeService(
+	userDetailRepo repository.FNUserDetailRepository,
+	docRepo repository.FNDocumentRepository,
+	participantRepo repository.FNEventParticipantRepository,
+) FNMeService {
+	return &fnMeService{
+		userDetailRepo:  userDetailRepo,
+		docRepo:         docRepo,
+		participantRepo: participantRepo,
+	}
+}
+
+func (s *fnMeService) ListDocuments(ctx context.Context, nationalID string, params dto.MyDocumentListQuery) ([]dto.MyDocumentItem, int64, error) {
+	userDetail, err := s.resolveUserDetail(ctx, nationalID)
+	if err != nil || userDetail == nil {
+		return []dto.MyDocumentItem{}, 0, err
+	}
+
+	docs, total, err := s.docRepo.ListByUserDetailID(ctx, userDetail.ID, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing documents: %w", err)
+	}
+
+	items := make([]dto.MyDocumentItem, 0, len(docs))
+	for i := range docs {
+		items = append(items, *toMyDocumentItem(&docs[i]))
+	}
+	return items, total, nil
+}
+
+func (s *fnMeService) ListEvents(ctx context.Context, nationalID string, params dto.MyEventListQuery) ([]dto.MyEventItem, int64, error) {
+	userDetail, err := s.resolveUserDetail(ctx, nationalID)
+	if err != nil || userDetail == nil {
+		return []dto.MyEventItem{}, 0, err
+	}
+
+	participants, total, err := s.participantRepo.ListByUserDetailID(ctx, userDetail.ID, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing events: %w", err)
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(participants))
+	for _, p := range participants {
+		eventIDs = append(eventIDs, p.EventID)
+	}
+
+	docs, err := s.docRepo.GetByUserDetailAndEventIDs(ctx, userDetail.ID, eventIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching documents: %w", err)
+	}
+	docsByEvent := make(map[uuid.UUID]*models.Document, len(docs))
+	for i := range docs {
+		if docs[i].EventID != nil && (params.IncludeAll || docs[i].Status == dto.DocStatusPDFCompleted) {
+			docsByEvent[*docs[i].EventID] = &docs[i]
+		}
+	}
+
+	items := make([]dto.MyEventItem, 0, len(participants))
+	for _, p := range participants {
+		item := dto.MyEventItem{
+			EventID:            p.EventID,
+			Code:               p.Event.Code,
+			Title:              p.Event.Title,
+			Location:           p.Event.Location,
+			Status:             p.Event.Status,
+			RegistrationStatus: p.RegistrationStatus,
+			AttendanceStatus:   p.AttendanceStatus,
+			RegisteredAt:       p.CreatedAt,
+		}
+		if doc, ok := docsByEvent[p.EventID]; ok {
+			doc.Event = &p.Event
+			item.Document = toMyDocumentItem(doc)
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// resolveUserDetail finds the beneficiary record of the caller; nil when the caller
+// has never been registered as a participant
+func (s *fnMeService) resolveUserDetail(ctx context.Context, nationalID string) (*models.UserDetail, error) {
+	if nationalID == "" {
+		return nil, fmt.Errorf("invalid token: national_id claim is required")
+	}
+
+	userDetail, err := s.userDetailRepo.GetByNationalID(ctx, nationalID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user detail: %w", err)
+	}
+	return userDetail, nil
+}
+
+func toMyDocumentItem(doc *models.Document) *dto.MyDocumentItem {
+	item := &dto.MyDocumentItem{
+		ID:               doc.ID,
+		SerialCode:       doc.SerialCode,
+		VerificationCode: doc.VerificationCode,
+		Status:           doc.Status,
+		IssueDate:        doc.IssueDate,
+		EventID:          doc.EventID,
+	}
+	if doc.Event != nil {
+		item.EventCode = doc.Event.Code
+		item.EventTitle = doc.Event.Title
+	}
+	if doc.Template != nil {
+		item.TemplateName = doc.Template.Name
+	}
+
+	// PDFs are preloaded newest first
+	if len(doc.PDFs) > 0 && doc.Status == dto.DocStatusPDFCompleted {
+		version := doc.PDFs[0].Version
+		url := fmt.Sprintf("/api/v1/fn/documents/%s/pdf", doc.ID)
+		item.PDFAvailable = true
+		item.PDFVersion = &version
+		item.DownloadURL = &url
+	}
+	return item
+}
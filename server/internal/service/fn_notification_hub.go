@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// Notification stream messages are published on "notifications.user.<user_id>"
+const (
+	SubjectNotificationStreamPrefix = "notifications.user."
+	SubjectNotificationStream       = "notifications.user.*"
+)
+
+// notificationSubscriberBuffer is how many messages a slow stream may fall behind
+// before it is closed; the client reconnects and replays from its cursor
+const notificationSubscriberBuffer = 32
+
+// NotificationHub fans in-app notifications and unread counts out to open streams.
+// With NATS every replica subscribes to the stream subject, so a notification
+// created on one replica reaches streams held by any other; without NATS it
+// delivers in process only. A nil hub is a no-op.
+type NotificationHub struct {
+	nc   *nats.Conn
+	repo repository.FNNotificationRepository
+
+	mu     sync.RWMutex
+	subs   map[uuid.UUID]map[*notificationSubscriber]struct{}
+	sub    *nats.Subscription
+	closed bool
+}
+
+type notificationSubscriber struct {
+	ch   chan dto.NotificationStreamMessage
+	once sync.Once
+}
+
+func (s *notificationSubscriber) close() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+// NewNotificationHub creates the notification fan-out hub
+func NewNotificationHub(nc *nats.Conn, repo repository.FNNotificationRepository) *NotificationHub {
+	return &NotificationHub{
+		nc:   nc,
+		repo: repo,
+		subs: make(map[uuid.UUID]map[*notificationSubscriber]struct{}),
+	}
+}
+
+// Start subscribes to the stream subject (every replica, no queue group)
+func (h *NotificationHub) Start() error {
+	if h == nil || h.nc == nil {
+		return nil
+	}
+
+	sub, err := h.nc.Subscribe(SubjectNotificationStream, func(msg *nats.Msg) {
+		var m dto.NotificationStreamMessage
+		if err := json.Unmarshal(msg.Data, &m); err != nil {
+			log.Error().Err(err).Str("subject", msg.Subject).Msg("error unmarshaling notification stream message")
+			return
+		}
+		h.deliver(m)
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.sub = sub
+	h.mu.Unlock()
+
+	log.Info().Str("subject", SubjectNotificationStream).Msg("Notification hub subscribed")
+	return nil
+}
+
+// Stop unsubscribes and closes every open stream so long-lived responses end
+func (h *NotificationHub) Stop() {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.sub != nil {
+		if err := h.sub.Unsubscribe(); err != nil {
+			log.Warn().Err(err).Msg("failed to unsubscribe notification hub")
+		}
+		h.sub = nil
+	}
+	for userID, subs := range h.subs {
+		for s := range subs {
+			s.close()
+		}
+		delete(h.subs, userID)
+	}
+	h.closed = true
+}
+
+// Subscribe opens a stream for a user. The channel is closed when the hub stops
+// or the subscriber falls behind; cancel must be called when the client leaves.
+func (h *NotificationHub) Subscribe(userID uuid.UUID) (<-chan dto.NotificationStreamMessage, func()) {
+	s := &notificationSubscriber{ch: make(chan dto.NotificationStreamMessage, notificationSubscriberBuffer)}
+	if h == nil {
+		s.close()
+		return s.ch, func() {}
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		s.close()
+		return s.ch, func() {}
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*notificationSubscriber]struct{})
+	}
+	h.subs[userID][s] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		if subs, ok := h.subs[userID]; ok {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(h.subs, userID)
+			}
+		}
+		h.mu.Unlock()
+		s.close()
+	}
+	return s.ch, cancel
+}
+
+// NotifyCreated pushes a new notification with the owner's unread count
+func (h *NotificationHub) NotifyCreated(ctx context.Context, n *models.Notification) {
+	if h == nil || n == nil {
+		return
+	}
+	h.publish(ctx, n.UserID, toNotificationItem(n))
+}
+
+// NotifyUnreadChanged pushes the user's unread count (read, read-all, delete)
+func (h *NotificationHub) NotifyUnreadChanged(ctx context.Context, userID uuid.UUID) {
+	if h == nil {
+		return
+	}
+	h.publish(ctx, userID, nil)
+}
+
+// publish is best effort: a missed push is recovered by the client's cursor replay
+func (h *NotificationHub) publish(ctx context.Context, userID uuid.UUID, item *dto.NotificationItem) {
+	count, err := h.repo.CountUnread(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("error counting unread notifications")
+		return
+	}
+	m := dto.NotificationStreamMessage{UserID: userID, Notification: item, UnreadCount: count}
+
+	if h.nc == nil {
+		h.deliver(m)
+		return
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		log.Error().Err(err).Msg("error marshaling notification stream message")
+		return
+	}
+	if err := h.nc.Publish(SubjectNotificationStreamPrefix+userID.String(), data); err != nil {
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("error publishing notification stream message")
+	}
+}
+
+// deliver forwards a message to the user's local streams
+func (h *NotificationHub) deliver(m dto.NotificationStreamMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs[m.UserID] {
+		select {
+		case s.ch <- m:
+		default:
+			// too slow: drop the stream, the client replays from its cursor on reconnect
+			delete(h.subs[m.UserID], s)
+			s.close()
+		}
+	}
+	if len(h.subs[m.UserID]) == 0 {
+		delete(h.subs, m.UserID)
+	}
+}
+
+func toNotificationItem(n *models.Notification) *dto.NotificationItem {
+	return &dto.NotificationItem{
+		ID:               n.ID,
+		Title:            n.Title,
+		Body:             n.Body,
+		NotificationType: n.NotificationType,
+		IsRead:           n.IsRead,
+		ReadAt:           n.ReadAt,
+		CreatedAt:        n.CreatedAt,
+	}
+}
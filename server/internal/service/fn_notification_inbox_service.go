@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// notificationReplayLimit caps how many missed notifications a reconnect replays
+const notificationReplayLimit = 100
+
+// maxBulkNotificationIDs caps the IDs accepted by a bulk mark-read
+const maxBulkNotificationIDs = 500
+
+// FNNotificationInboxService defines the interface for the caller's own in-app notifications
+type FNNotificationInboxService interface {
+	// List returns a cursor page of the user's notifications, newest first
+	List(ctx context.Context, userID uuid.UUID, params dto.MyNotificationListQuery) (*dto.MyNotificationListResponse, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, req dto.MarkNotificationsReadRequest) (*dto.NotificationBulkResult, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.NotificationBulkResult, error)
+	DeleteOlderThan(ctx context.Context, userID uuid.UUID, before time.Time) (*dto.NotificationBulkResult, error)
+
+	// Subscribe opens a live stream for the user; see NotificationHub.Subscribe
+	Subscribe(userID uuid.UUID) (<-chan dto.NotificationStreamMessage, func())
+	// Replay returns the notifications created after the cursor, oldest first. The cursor
+	// is the last notification ID the client saw or an RFC3339 timestamp; empty replays nothing.
+	Replay(ctx context.Context, userID uuid.UUID, cursor string) ([]dto.NotificationItem, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type fnNotificationInboxService struct {
+	repo repository.FNNotificationRepository
+	hub  *NotificationHub
+}
+
+// NewFNNotificationInboxService creates a new FN notification inbox service
+func NewFNNotificationInboxService(repo repository.FNNotificationRepository, hub *NotificationHub) FNNotificationInboxService {
+	return &fnNotificationInboxService{repo: repo, hub: hub}
+}
+
+func (s *fnNotificationInboxService) Subscribe(userID uuid.UUID) (<-chan dto.NotificationStreamMessage, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+func (s *fnNotificationInboxService) Replay(ctx context.Context, userID uuid.UUID, cursor string) ([]dto.NotificationItem, error) {
+	cursor = strings.TrimSpace(cursor)
+	if cursor == "" {
+		return nil, nil
+	}
+
+	var (
+		afterTime time.Time
+		afterID   uuid.UUID
+	)
+	if id, err := uuid.Parse(cursor); err == nil {
+		last, err := s.repo.GetUserNotification(ctx, userID, id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching cursor notification: %w", err)
+		}
+		// a deleted (or foreign) cursor cannot be placed; the client still gets the unread count
+		if last == nil {
+			return nil, nil
+		}
+		afterTime, afterID = last.CreatedAt, last.ID
+	} else if t, err := time.Parse(time.RFC3339Nano, cursor); err == nil {
+		afterTime = t
+	} else {
+		return nil, fmt.Errorf("invalid cursor: expected a notification ID or an RFC3339 timestamp")
+	}
+
+	notifications, err := s.repo.GetUserNotificationsAfter(ctx, userID, afterTime, afterID, notificationReplayLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching missed notifications: %w", err)
+	}
+
+	items := make([]dto.NotificationItem, 0, len(notifications))
+	for i := range notifications {
+		items = append(items, *toNotificationItem(&notifications[i]))
+	}
+	return items, nil
+}
+
+func (s *fnNotificationInboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (s *fnNotificationInboxService) List(ctx context.Context, userID uuid.UUID, params dto.MyNotificationListQuery) (*dto.MyNotificationListResponse, error) {
+	filter := dto.UserNotificationFilter{
+		UnreadOnly: params.UnreadOnly,
+		Limit:      params.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	for _, t := range params.Types {
+		for _, v := range strings.Split(t, ",") {
+			if v = strings.TrimSpace(v); v != "" && !slices.Contains(filter.Types, v) {
+				filter.Types = append(filter.Types, v)
+			}
+		}
+	}
+
+	if params.Cursor != "" {
+		createdAt, id, err := decodeNotificationCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.BeforeCreatedAt, filter.BeforeID = &createdAt, id
+	}
+
+	// one extra row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	notifications, err := s.repo.ListUserNotifications(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing notifications: %w", err)
+	}
+
+	resp := &dto.MyNotificationListResponse{Items: make([]dto.NotificationItem, 0, limit)}
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		last := notifications[limit-1]
+		resp.NextCursor = encodeNotificationCursor(last.CreatedAt, last.ID)
+	}
+	for i := range notifications {
+		resp.Items = append(resp.Items, *toNotificationItem(&notifications[i]))
+	}
+
+	if resp.UnreadCount, err = s.UnreadCount(ctx, userID); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *fnNotificationInboxService) MarkRead(ctx context.Context, userID uuid.UUID, req dto.MarkNotificationsReadRequest) (*dto.NotificationBulkResult, error) {
+	if len(req.IDs) == 0 {
+		return nil, fmt.Errorf("ids are required")
+	}
+	if len(req.IDs) > maxBulkNotificationIDs {
+		return nil, fmt.Errorf("invalid ids: at most %d per request", maxBulkNotificationIDs)
+	}
+
+	// only the caller's own notifications are touched; foreign IDs are ignored
+	affected, err := s.repo.MarkUserNotificationsRead(ctx, userID, req.IDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error marking notifications as read: %w", err)
+	}
+	return s.bulkResult(ctx, userID, affected)
+}
+
+func (s *fnNotificationInboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.NotificationBulkResult, error) {
+	affected, err := s.repo.MarkUserNotificationsRead(ctx, userID, nil, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error marking notifications as read: %w", err)
+	}
+	return s.bulkResult(ctx, userID, affected)
+}
+
+func (s *fnNotificationInboxService) DeleteOlderThan(ctx context.Context, userID uuid.UUID, before time.Time) (*dto.NotificationBulkResult, error) {
+	if before.IsZero() {
+		return nil, fmt.Errorf("older_than is required")
+	}
+
+	affected, err := s.repo.DeleteUserNotificationsBefore(ctx, userID, before)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting notifications: %w", err)
+	}
+	return s.bulkResult(ctx, userID, affected)
+}
+
+// bulkResult reports a bulk change and pushes the new unread count to open streams
+func (s *fnNotificationInboxService) bulkResult(ctx context.Context, userID uuid.UUID, affected int64) (*dto.NotificationBulkResult, error) {
+	if affected > 0 {
+		s.hub.NotifyUnreadChanged(ctx, userID)
+	}
+	count, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NotificationBulkResult{Affected: affected, UnreadCount: count}, nil
+}
+
+// encodeNotificationCursor builds the opaque cursor pointing at a notification
+func encodeNotificationCursor(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()))
+}
+
+func decodeNotificationCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	ts, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	return createdAt, id, nil
+}
@@ -0,0 +1,831 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"server/internal/client/mailer"
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+//go:embed notification_templates.yml
+var defaultNotificationTemplates []byte
+
+// documentStatusTemplates maps the document statuses that notify the beneficiary to a template key
+var documentStatusTemplates = map[string]string{
+	dto.DocStatusPDFCompleted: dto.NotificationTypeDocumentGenerated,
+	dto.DocStatusRejected:     dto.NotificationTypeDocumentRevoked,
+	dto.DocStatusRenew:        dto.NotificationTypeDocumentRenewed,
+}
+
+// digestModes lists the digest modes in the order they are built
+var digestModes = []string{dto.NotificationModeHourly, dto.NotificationModeDaily}
+
+// preferenceSkipReason is stored on deliveries the user opted out of
+const preferenceSkipReason = "disabled by user preference"
+
+// deliveryLease keeps a claimed delivery out of the queue while it is being sent
+const deliveryLease = 5 * time.Minute
+
+// maxRetryBackoff caps the exponential backoff between email attempts
+const maxRetryBackoff = 6 * time.Hour
+
+// NotificationSender delivers email; *mailer.Client implements it
+type NotificationSender interface {
+	Enabled() bool
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+// NotificationConfig holds notification dispatch settings
+type NotificationConfig struct {
+	Templates     *NotificationTemplates
+	DefaultLocale string
+	// VerifyBaseURL is prepended to the verification code in certificate emails
+	VerifyBaseURL string
+	MaxAttempts   int
+	RetryBackoff  time.Duration
+	BatchSize     int
+	// PollInterval is how often the worker checks the email retry queue
+	PollInterval time.Duration
+	// DigestInterval is how often closed hourly/daily digest windows are checked
+	DigestInterval time.Duration
+}
+
+// NotificationTemplates holds the parsed templates by key and locale
+type NotificationTemplates struct {
+	entries map[string]map[string]notificationTemplate
+}
+
+type notificationTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// LoadNotificationTemplates parses the notification templates (embedded or from file)
+func LoadNotificationTemplates(path string) (*NotificationTemplates, error) {
+	data := defaultNotificationTemplates
+	if path != "" {
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading notification templates file: %w", err)
+		}
+		data = fileData
+	}
+
+	var raw map[string]map[string]struct {
+		Title string `yaml:"title"`
+		Body  string `yaml:"body"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing notification templates: %w", err)
+	}
+
+	t := &NotificationTemplates{entries: make(map[string]map[string]notificationTemplate, len(raw))}
+	for key, locales := range raw {
+		t.entries[key] = make(map[string]notificationTemplate, len(locales))
+		for locale, entry := range locales {
+			name := key + "." + locale
+			title, err := template.New(name + ".title").Option("missingkey=zero").Parse(entry.Title)
+			if err != nil {
+				return nil, fmt.Errorf("invalid notification template %s title: %w", name, err)
+			}
+			body, err := template.New(name + ".body").Option("missingkey=zero").Parse(entry.Body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid notification template %s body: %w", name, err)
+			}
+			t.entries[key][strings.ToLower(locale)] = notificationTemplate{title: title, body: body}
+		}
+	}
+	return t, nil
+}
+
+// Render renders a template in the requested locale, falling back to the default one
+func (t *NotificationTemplates) Render(key, locale, defaultLocale string, data map[string]interface{}) (string, string, string, error) {
+	locales, ok := t.entries[key]
+	if !ok {
+		return "", "", "", fmt.Errorf("notification template '%s' not found", key)
+	}
+
+	used := strings.ToLower(locale)
+	tmpl, ok := locales[used]
+	if !ok {
+		used = strings.ToLower(defaultLocale)
+		tmpl, ok = locales[used]
+	}
+	if !ok {
+		return "", "", "", fmt.Errorf("notification template '%s' has no locale '%s'", key, locale)
+	}
+
+	var title, body bytes.Buffer
+	if err := tmpl.title.Execute(&title, data); err != nil {
+		return "", "", "", fmt.Errorf("error rendering %s title: %w", key, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", "", fmt.Errorf("error rendering %s body: %w", key, err)
+	}
+	return strings.TrimSpace(title.String()), strings.TrimSpace(body.String()), used, nil
+}
+
+// FNNotificationService defines the interface for the notification dispatcher
+type FNNotificationService interface {
+	// Dispatch turns a domain event into in-app notifications and queued emails
+	Dispatch(ctx context.Context, event dto.DomainEvent) error
+	// DeliverDue sends the queued emails whose next attempt is due
+	DeliverDue(ctx context.Context) (int, error)
+	ListDeliveries(ctx context.Context, params dto.NotificationDeliveryListQuery) ([]dto.NotificationDeliveryResponse, int64, error)
+	RetryDelivery(ctx context.Context, id uuid.UUID) (*dto.NotificationDeliveryResponse, error)
+
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.NotificationPreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error)
+	// BuildDigests folds the notifications waiting for an hourly/daily digest whose
+	// window has closed into one summary per user and channel
+	BuildDigests(ctx context.Context, now time.Time) (int, error)
+}
+
+type fnNotificationService struct {
+	notificationRepo repository.FNNotificationRepository
+	docRepo          repository.FNDocumentRepository
+	eventRepo        repository.FNEventRepository
+	sender           NotificationSender
+	hub              *NotificationHub
+	cfg              NotificationConfig
+}
+
+// NewFNNotificationService creates a new FN notification service
+func NewFNNotificationService(
+	notificationRepo repository.FNNotificationRepository,
+	docRepo repository.FNDocumentRepository,
+	eventRepo repository.FNEventRepository,
+	sender NotificationSender,
+	hub *NotificationHub,
+	cfg NotificationConfig,
+) FNNotificationService {
+	if cfg.Templates == nil {
+		// the embedded templates ship with the binary; failing to parse them is a build bug
+		templates, err := LoadNotificationTemplates("")
+		if err != nil {
+			panic(err)
+		}
+		cfg.Templates = templates
+	}
+	if cfg.DefaultLocale == "" {
+		cfg.DefaultLocale = "es"
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+
+	return &fnNotificationService{
+		notificationRepo: notificationRepo,
+		docRepo:          docRepo,
+		eventRepo:        eventRepo,
+		sender:           sender,
+		hub:              hub,
+		cfg:              cfg,
+	}
+}
+
+func (s *fnNotificationService) Dispatch(ctx context.Context, event dto.DomainEvent) error {
+	switch event.EventType {
+	case dto.DomainEventDocumentStatusChanged:
+		var payload dto.DocumentStatusChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		return s.dispatchDocumentStatus(ctx, event.ID, payload)
+	case dto.DomainEventEventStatusChanged:
+		var payload dto.EventStatusChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		return s.dispatchEventStatus(ctx, event.ID, payload)
+	case dto.DomainEventPDFBatchFinished:
+		var payload dto.PDFBatchFinishedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		return s.dispatchPDFBatch(ctx, event.ID, payload)
+	default:
+		return nil
+	}
+}
+
+func (s *fnNotificationService) dispatchDocumentStatus(ctx context.Context, eventID uuid.UUID, payload dto.DocumentStatusChangedPayload) error {
+	key, ok := documentStatusTemplates[payload.Status]
+	if !ok {
+		return nil
+	}
+
+	doc, err := s.docRepo.GetByID(ctx, payload.DocumentID)
+	if err != nil {
+		return fmt.Errorf("error fetching document: %w", err)
+	}
+	if doc == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"SerialCode":       doc.SerialCode,
+		"VerificationCode": doc.VerificationCode,
+		"Status":           payload.Status,
+		"PreviousStatus":   payload.PreviousStatus,
+	}
+	if s.cfg.VerifyBaseURL != "" {
+		data["VerifyURL"] = strings.TrimRight(s.cfg.VerifyBaseURL, "/") + "/" + doc.VerificationCode
+	}
+	if doc.Event != nil {
+		data["EventTitle"] = doc.Event.Title
+		data["EventCode"] = doc.Event.Code
+	}
+	if len(doc.Revocations) > 0 {
+		rev := doc.Revocations[0]
+		data["ReasonCode"] = rev.ReasonCode
+		if rev.SupersededBy != nil {
+			data["ReplacementSerial"] = rev.SupersededBy.SerialCode
+		}
+	}
+
+	recipients, err := s.notificationRepo.GetRecipientsByUserDetailIDs(ctx, []uuid.UUID{doc.UserDetailID})
+	if err != nil {
+		return fmt.Errorf("error fetching recipients: %w", err)
+	}
+	return s.notifyAll(ctx, eventID, key, recipients, data)
+}
+
+func (s *fnNotificationService) dispatchEventStatus(ctx context.Context, eventID uuid.UUID, payload dto.EventStatusChangedPayload) error {
+	event, err := s.eventRepo.GetByID(ctx, payload.EventID)
+	if err != nil {
+		return fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"EventTitle":     event.Title,
+		"EventCode":      event.Code,
+		"Status":         payload.Status,
+		"PreviousStatus": payload.PreviousStatus,
+	}
+
+	recipients, err := s.notificationRepo.GetRecipientsByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("error fetching recipients: %w", err)
+	}
+	return s.notifyAll(ctx, eventID, dto.NotificationTypeEventStatusChanged, recipients, data)
+}
+
+func (s *fnNotificationService) dispatchPDFBatch(ctx context.Context, eventID uuid.UUID, payload dto.PDFBatchFinishedPayload) error {
+	// the event organizer is told how the batch went
+	if payload.EventID == nil {
+		return nil
+	}
+	event, err := s.eventRepo.GetByID(ctx, *payload.EventID)
+	if err != nil {
+		return fmt.Errorf("error fetching event: %w", err)
+	}
+	if event == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"EventTitle":   event.Title,
+		"EventCode":    event.Code,
+		"TotalItems":   payload.TotalItems,
+		"SuccessCount": payload.SuccessCount,
+		"FailedCount":  payload.FailedCount,
+	}
+	if payload.Error != nil {
+		data["Error"] = *payload.Error
+	}
+
+	organizer, err := s.notificationRepo.GetRecipientByUserID(ctx, event.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("error fetching recipient: %w", err)
+	}
+	if organizer == nil {
+		return nil
+	}
+	return s.notifyAll(ctx, eventID, dto.NotificationTypePDFBatchFinished, []dto.NotificationRecipient{*organizer}, data)
+}
+
+func (s *fnNotificationService) notifyAll(ctx context.Context, eventID uuid.UUID, key string, recipients []dto.NotificationRecipient, data map[string]interface{}) error {
+	modes, err := s.preferenceModes(ctx, key, recipients)
+	if err != nil {
+		return fmt.Errorf("error fetching notification preferences: %w", err)
+	}
+
+	failed := 0
+	for _, r := range recipients {
+		var userModes map[string]string
+		if r.UserID != nil {
+			userModes = modes[*r.UserID]
+		}
+		if err := s.notify(ctx, eventID, key, r, data, userModes); err != nil {
+			log.Error().Err(err).Str("template", key).Str("event_id", eventID.String()).Msg("error dispatching notification")
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d notifications failed", failed, len(recipients))
+	}
+	return nil
+}
+
+// preferenceModes loads the recipients' delivery mode per channel for a notification type
+func (s *fnNotificationService) preferenceModes(ctx context.Context, key string, recipients []dto.NotificationRecipient) (map[uuid.UUID]map[string]string, error) {
+	userIDs := make([]uuid.UUID, 0, len(recipients))
+	for _, r := range recipients {
+		if r.UserID != nil {
+			userIDs = append(userIDs, *r.UserID)
+		}
+	}
+
+	prefs, err := s.notificationRepo.GetPreferencesByType(ctx, userIDs, key)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := make(map[uuid.UUID]map[string]string, len(prefs))
+	for _, p := range prefs {
+		if modes[p.UserID] == nil {
+			modes[p.UserID] = make(map[string]string, len(dto.NotificationChannels))
+		}
+		modes[p.UserID][p.Channel] = p.Mode
+	}
+	return modes, nil
+}
+
+// notify writes the in-app notification (recipients with an account) and queues the email,
+// following the recipient's preference for each channel (IMMEDIATE when not set)
+func (s *fnNotificationService) notify(ctx context.Context, eventID uuid.UUID, key string, r dto.NotificationRecipient, data map[string]interface{}, modes map[string]string) error {
+	vars := make(map[string]interface{}, len(data)+2)
+	for k, v := range data {
+		vars[k] = v
+	}
+	vars["FirstName"] = r.FirstName
+	vars["LastName"] = r.LastName
+
+	title, body, locale, err := s.cfg.Templates.Render(key, s.cfg.DefaultLocale, s.cfg.DefaultLocale, vars)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var notification *models.Notification
+	deliveries := make([]models.NotificationDelivery, 0, 2)
+
+	newDelivery := func(channel, recipient string) models.NotificationDelivery {
+		return models.NotificationDelivery{
+			UserDetailID:  r.UserDetailID,
+			UserID:        r.UserID,
+			Channel:       channel,
+			Recipient:     recipient,
+			TemplateKey:   key,
+			Locale:        locale,
+			Subject:       truncate(title, 255),
+			Body:          body,
+			Status:        dto.DeliveryStatusPending,
+			MaxAttempts:   s.cfg.MaxAttempts,
+			NextAttemptAt: now,
+			DedupKey:      dedupKey(eventID, channel, strings.ToLower(recipient)),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+
+	if r.UserID != nil {
+		delivery := newDelivery(dto.NotificationChannelInApp, r.UserID.String())
+		switch mode := preferenceMode(modes, dto.NotificationChannelInApp); mode {
+		case dto.NotificationModeImmediate:
+			notificationType := key
+			notification = &models.Notification{
+				ID:               uuid.New(),
+				UserID:           *r.UserID,
+				Title:            truncate(title, 200),
+				Body:             body,
+				NotificationType: &notificationType,
+				CreatedAt:        now,
+			}
+			delivery.Status = dto.DeliveryStatusSent
+			delivery.Attempts = 1
+			delivery.MaxAttempts = 1
+			delivery.SentAt = &now
+		default:
+			applyPreferenceMode(&delivery, mode)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if r.Email != nil && strings.TrimSpace(*r.Email) != "" {
+		delivery := newDelivery(dto.NotificationChannelEmail, strings.TrimSpace(*r.Email))
+		mode := preferenceMode(modes, dto.NotificationChannelEmail)
+		if !s.sender.Enabled() && mode != dto.NotificationModeNone {
+			reason := mailer.ErrDisabled.Error()
+			delivery.Status = dto.DeliveryStatusSkipped
+			delivery.LastError = &reason
+		} else {
+			applyPreferenceMode(&delivery, mode)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	created, err := s.notificationRepo.Create(ctx, notification, deliveries)
+	if err != nil {
+		return fmt.Errorf("error storing notification: %w", err)
+	}
+	if created && notification != nil {
+		s.hub.NotifyCreated(ctx, notification)
+	}
+	return nil
+}
+
+// preferenceMode returns the mode set for a channel, IMMEDIATE by default
+func preferenceMode(modes map[string]string, channel string) string {
+	if mode, ok := modes[channel]; ok {
+		return mode
+	}
+	return dto.NotificationModeImmediate
+}
+
+// applyPreferenceMode parks a delivery for a digest or skips it; IMMEDIATE leaves it as is
+func applyPreferenceMode(d *models.NotificationDelivery, mode string) {
+	switch mode {
+	case dto.NotificationModeNone:
+		reason := preferenceSkipReason
+		d.Status = dto.DeliveryStatusSkipped
+		d.LastError = &reason
+	case dto.NotificationModeHourly, dto.NotificationModeDaily:
+		digestMode := mode
+		d.Status = dto.DeliveryStatusDigestPending
+		d.DigestMode = &digestMode
+	}
+}
+
+func (s *fnNotificationService) DeliverDue(ctx context.Context) (int, error) {
+	if !s.sender.Enabled() {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	due, err := s.notificationRepo.ClaimDueDeliveries(ctx, now, s.cfg.BatchSize, deliveryLease)
+	if err != nil {
+		return 0, fmt.Errorf("error claiming deliveries: %w", err)
+	}
+
+	sent := 0
+	for _, d := range due {
+		attempts := d.Attempts + 1
+		sendErr := s.sender.Send(ctx, mailer.Message{To: d.Recipient, Subject: d.Subject, Body: d.Body})
+		at := time.Now().UTC()
+
+		if sendErr == nil {
+			if err := s.notificationRepo.MarkDeliverySent(ctx, d.ID, attempts, at); err != nil {
+				log.Error().Err(err).Str("delivery_id", d.ID.String()).Msg("error marking delivery as sent")
+			}
+			sent++
+			continue
+		}
+
+		var next *time.Time
+		if attempts < d.MaxAttempts {
+			retryAt := at.Add(s.backoff(attempts))
+			next = &retryAt
+		}
+		if err := s.notificationRepo.MarkDeliveryFailed(ctx, d.ID, attempts, truncate(sendErr.Error(), 1000), next, at); err != nil {
+			log.Error().Err(err).Str("delivery_id", d.ID.String()).Msg("error marking delivery as failed")
+		}
+		log.Warn().Err(sendErr).
+			Str("delivery_id", d.ID.String()).
+			Int("attempts", attempts).
+			Bool("will_retry", next != nil).
+			Msg("email delivery failed")
+	}
+
+	return sent, nil
+}
+
+func (s *fnNotificationService) ListDeliveries(ctx context.Context, params dto.NotificationDeliveryListQuery) ([]dto.NotificationDeliveryResponse, int64, error) {
+	deliveries, total, err := s.notificationRepo.ListDeliveries(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing deliveries: %w", err)
+	}
+
+	items := make([]dto.NotificationDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		items = append(items, *toNotificationDeliveryResponse(&deliveries[i]))
+	}
+	return items, total, nil
+}
+
+func (s *fnNotificationService) RetryDelivery(ctx context.Context, id uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	delivery, err := s.notificationRepo.GetDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching delivery: %w", err)
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("delivery not found")
+	}
+	if delivery.Channel != dto.NotificationChannelEmail {
+		return nil, fmt.Errorf("invalid delivery: only email deliveries can be retried")
+	}
+	if delivery.Status == dto.DeliveryStatusSent || delivery.Status == dto.DeliveryStatusPending {
+		return nil, fmt.Errorf("invalid delivery: status is %s", delivery.Status)
+	}
+
+	if err := s.notificationRepo.RequeueDelivery(ctx, id, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("error requeuing delivery: %w", err)
+	}
+
+	updated, err := s.notificationRepo.GetDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching delivery: %w", err)
+	}
+	return toNotificationDeliveryResponse(updated), nil
+}
+
+func (s *fnNotificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.NotificationPreferencesResponse, error) {
+	prefs, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notification preferences: %w", err)
+	}
+
+	configured := make(map[string]string, len(prefs))
+	for _, p := range prefs {
+		configured[p.NotificationType+"|"+p.Channel] = p.Mode
+	}
+
+	items := make([]dto.NotificationPreferenceItem, 0, len(dto.NotificationTypes)*len(dto.NotificationChannels))
+	for _, notificationType := range dto.NotificationTypes {
+		for _, channel := range dto.NotificationChannels {
+			mode, ok := configured[notificationType+"|"+channel]
+			if !ok {
+				mode = dto.NotificationModeImmediate
+			}
+			items = append(items, dto.NotificationPreferenceItem{
+				NotificationType: notificationType,
+				Channel:          channel,
+				Mode:             mode,
+			})
+		}
+	}
+
+	return &dto.NotificationPreferencesResponse{
+		Preferences: items,
+		Types:       dto.NotificationTypes,
+		Channels:    dto.NotificationChannels,
+		Modes: []string{
+			dto.NotificationModeImmediate,
+			dto.NotificationModeHourly,
+			dto.NotificationModeDaily,
+			dto.NotificationModeNone,
+		},
+	}, nil
+}
+
+func (s *fnNotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	if len(req.Preferences) == 0 {
+		return nil, fmt.Errorf("preferences are required")
+	}
+
+	now := time.Now().UTC()
+	prefs := make([]models.NotificationPreference, 0, len(req.Preferences))
+	index := make(map[string]int, len(req.Preferences))
+	for _, item := range req.Preferences {
+		notificationType := strings.TrimSpace(item.NotificationType)
+		channel := strings.ToUpper(strings.TrimSpace(item.Channel))
+		mode := strings.ToUpper(strings.TrimSpace(item.Mode))
+
+		if !slices.Contains(dto.NotificationTypes, notificationType) {
+			return nil, fmt.Errorf("invalid notification type '%s'", item.NotificationType)
+		}
+		if !slices.Contains(dto.NotificationChannels, channel) {
+			return nil, fmt.Errorf("invalid channel '%s'", item.Channel)
+		}
+		switch mode {
+		case dto.NotificationModeImmediate, dto.NotificationModeHourly, dto.NotificationModeDaily, dto.NotificationModeNone:
+		default:
+			return nil, fmt.Errorf("invalid mode '%s'", item.Mode)
+		}
+
+		// the last entry for a type and channel wins
+		k := notificationType + "|" + channel
+		if i, ok := index[k]; ok {
+			prefs[i].Mode = mode
+			continue
+		}
+		index[k] = len(prefs)
+
+		prefs = append(prefs, models.NotificationPreference{
+			UserID:           userID,
+			NotificationType: notificationType,
+			Channel:          channel,
+			Mode:             mode,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+	}
+
+	if err := s.notificationRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("error saving notification preferences: %w", err)
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
+
+func (s *fnNotificationService) BuildDigests(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	built := 0
+	for _, mode := range digestModes {
+		// notifications are digested once the hour (or UTC day) they arrived in has closed
+		windowEnd := now.Truncate(time.Hour)
+		if mode == dto.NotificationModeDaily {
+			windowEnd = now.Truncate(24 * time.Hour)
+		}
+
+		groups, err := s.notificationRepo.ListDigestGroups(ctx, mode, windowEnd)
+		if err != nil {
+			return built, fmt.Errorf("error listing pending digests: %w", err)
+		}
+
+		for _, group := range groups {
+			ok, err := s.buildDigest(ctx, group, mode, windowEnd)
+			if err != nil {
+				log.Error().Err(err).
+					Str("user_id", group.UserID.String()).
+					Str("channel", group.Channel).
+					Str("mode", mode).
+					Msg("error building notification digest")
+				continue
+			}
+			if ok {
+				built++
+			}
+		}
+	}
+	return built, nil
+}
+
+// buildDigest renders one summary for a user's channel and marks the items as digested
+func (s *fnNotificationService) buildDigest(ctx context.Context, group dto.NotificationDigestGroup, mode string, windowEnd time.Time) (bool, error) {
+	items, err := s.notificationRepo.GetDigestItems(ctx, group, mode, windowEnd)
+	if err != nil {
+		return false, fmt.Errorf("error fetching digest items: %w", err)
+	}
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	last := items[len(items)-1]
+	vars := map[string]interface{}{
+		"Mode":  mode,
+		"Count": len(items),
+	}
+	entries := make([]map[string]interface{}, 0, len(items))
+	itemIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, map[string]interface{}{
+			"Title":     item.Subject,
+			"Body":      item.Body,
+			"CreatedAt": item.CreatedAt,
+		})
+		itemIDs = append(itemIDs, item.ID)
+	}
+	vars["Items"] = entries
+
+	recipient, err := s.notificationRepo.GetRecipientByUserID(ctx, group.UserID)
+	if err != nil {
+		return false, fmt.Errorf("error fetching recipient: %w", err)
+	}
+	if recipient != nil {
+		vars["FirstName"] = recipient.FirstName
+		vars["LastName"] = recipient.LastName
+	}
+
+	title, body, locale, err := s.cfg.Templates.Render(dto.NotificationTypeDigest, last.Locale, s.cfg.DefaultLocale, vars)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	digestMode := mode
+	digest := &models.NotificationDelivery{
+		ID:            uuid.New(),
+		UserDetailID:  last.UserDetailID,
+		UserID:        &group.UserID,
+		Channel:       group.Channel,
+		Recipient:     last.Recipient,
+		TemplateKey:   dto.NotificationTypeDigest,
+		Locale:        locale,
+		Subject:       truncate(title, 255),
+		Body:          body,
+		Status:        dto.DeliveryStatusPending,
+		MaxAttempts:   s.cfg.MaxAttempts,
+		NextAttemptAt: now,
+		DigestMode:    &digestMode,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	digest.DedupKey = truncate(dto.NotificationTypeDigest+":"+digest.ID.String(), 255)
+
+	var notification *models.Notification
+	switch group.Channel {
+	case dto.NotificationChannelInApp:
+		notificationType := dto.NotificationTypeDigest
+		notification = &models.Notification{
+			ID:               uuid.New(),
+			UserID:           group.UserID,
+			Title:            truncate(title, 200),
+			Body:             body,
+			NotificationType: &notificationType,
+			CreatedAt:        now,
+		}
+		digest.Status = dto.DeliveryStatusSent
+		digest.Attempts = 1
+		digest.MaxAttempts = 1
+		digest.SentAt = &now
+	case dto.NotificationChannelEmail:
+		// the current account email wins over the one captured when the items were queued
+		if recipient != nil && recipient.Email != nil && strings.TrimSpace(*recipient.Email) != "" {
+			digest.Recipient = strings.TrimSpace(*recipient.Email)
+		}
+		if !s.sender.Enabled() {
+			reason := mailer.ErrDisabled.Error()
+			digest.Status = dto.DeliveryStatusSkipped
+			digest.LastError = &reason
+		}
+	}
+
+	created, err := s.notificationRepo.CreateDigest(ctx, notification, digest, itemIDs)
+	if err != nil {
+		return false, err
+	}
+	if created && notification != nil {
+		s.hub.NotifyCreated(ctx, notification)
+	}
+	return created, nil
+}
+
+// backoff doubles the wait after each failed attempt
+func (s *fnNotificationService) backoff(attempts int) time.Duration {
+	wait := s.cfg.RetryBackoff
+	for i := 1; i < attempts && wait < maxRetryBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return wait
+}
+
+func dedupKey(eventID uuid.UUID, channel, recipient string) string {
+	return truncate(eventID.String()+":"+channel+":"+recipient, 255)
+}
+
+func toNotificationDeliveryResponse(d *models.NotificationDelivery) *dto.NotificationDeliveryResponse {
+	return &dto.NotificationDeliveryResponse{
+		ID:             d.ID,
+		NotificationID: d.NotificationID,
+		UserDetailID:   d.UserDetailID,
+		UserID:         d.UserID,
+		Channel:        d.Channel,
+		Recipient:      d.Recipient,
+		TemplateKey:    d.TemplateKey,
+		Locale:         d.Locale,
+		Subject:        d.Subject,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		MaxAttempts:    d.MaxAttempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastError:      d.LastError,
+		SentAt:         d.SentAt,
+		DigestMode:     d.DigestMode,
+		DigestID:       d.DigestID,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
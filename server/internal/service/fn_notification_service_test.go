@@ -0,0 +1,372 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/client/mailer"
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// smtpSink is a minimal in-process SMTP server that keeps every accepted message.
+// Recipients listed in reject get a permanent 550 on RCPT TO.
+type smtpSink struct {
+	ln     net.Listener
+	reject map[string]bool
+
+	mu       sync.Mutex
+	messages []sinkMessage
+}
+
+type sinkMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+func newSMTPSink(t *testing.T, reject ...string) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpSink{ln: ln, reject: map[string]bool{}}
+	for _, r := range reject {
+		s.reject[r] = true
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 sink ESMTP")
+	var msg sinkMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 sink")
+		case "MAIL":
+			msg = sinkMessage{From: smtpPath(line)}
+			reply("250 OK")
+		case "RCPT":
+			to := smtpPath(line)
+			if s.reject[to] {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			msg.To = append(msg.To, to)
+			reply("250 OK")
+		case "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpSink) config() mailer.Config {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return mailer.Config{Host: host, Port: p, From: "Certificados <no-reply@example.com>", TLSMode: mailer.TLSNone, Timeout: 5 * time.Second}
+}
+
+func (s *smtpSink) received() []sinkMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sinkMessage(nil), s.messages...)
+}
+
+// smtpPath extracts the address of "MAIL FROM:<a>" / "RCPT TO:<a>"
+func smtpPath(line string) string {
+	start, end := strings.IndexByte(line, '<'), strings.IndexByte(line, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+type memNotificationRepo struct {
+	repository.FNNotificationRepository
+	recipients []dto.NotificationRecipient
+
+	mu            sync.Mutex
+	notifications []models.Notification
+	deliveries    []models.NotificationDelivery
+}
+
+func (r *memNotificationRepo) GetRecipientsByUserDetailIDs(context.Context, []uuid.UUID) ([]dto.NotificationRecipient, error) {
+	return r.recipients, nil
+}
+
+func (r *memNotificationRepo) GetPreferencesByType(context.Context, []uuid.UUID, string) ([]models.NotificationPreference, error) {
+	return nil, nil
+}
+
+func (r *memNotificationRepo) Create(_ context.Context, notification *models.Notification, deliveries []models.NotificationDelivery) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.DedupKey == deliveries[0].DedupKey {
+			return false, nil
+		}
+	}
+	if notification != nil {
+		r.notifications = append(r.notifications, *notification)
+	}
+	for _, d := range deliveries {
+		d.ID = uuid.New()
+		r.deliveries = append(r.deliveries, d)
+	}
+	return true, nil
+}
+
+func (r *memNotificationRepo) ClaimDueDeliveries(_ context.Context, now time.Time, limit int, _ time.Duration) ([]models.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []models.NotificationDelivery
+	for _, d := range r.deliveries {
+		if d.Channel == dto.NotificationChannelEmail && d.Status == dto.DeliveryStatusPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (r *memNotificationRepo) MarkDeliverySent(_ context.Context, id uuid.UUID, attempts int, at time.Time) error {
+	return r.update(id, func(d *models.NotificationDelivery) {
+		d.Status = dto.DeliveryStatusSent
+		d.Attempts = attempts
+		d.SentAt = &at
+	})
+}
+
+func (r *memNotificationRepo) MarkDeliveryFailed(_ context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt *time.Time, _ time.Time) error {
+	return r.update(id, func(d *models.NotificationDelivery) {
+		d.Attempts = attempts
+		d.LastError = &lastError
+		if nextAttemptAt != nil {
+			d.NextAttemptAt = *nextAttemptAt
+		} else {
+			d.Status = dto.DeliveryStatusFailed
+		}
+	})
+}
+
+func (r *memNotificationRepo) update(id uuid.UUID, fn func(*models.NotificationDelivery)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == id {
+			fn(&r.deliveries[i])
+		}
+	}
+	return nil
+}
+
+func (r *memNotificationRepo) emailDelivery(t *testing.T) models.NotificationDelivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.Channel == dto.NotificationChannelEmail {
+			return d
+		}
+	}
+	t.Fatal("no email delivery queued")
+	return models.NotificationDelivery{}
+}
+
+type stubNotificationDocRepo struct {
+	repository.FNDocumentRepository
+	doc *models.Document
+}
+
+func (r *stubNotificationDocRepo) GetByID(context.Context, uuid.UUID) (*models.Document, error) {
+	return r.doc, nil
+}
+
+// dispatchGenerated sends a document.generated domain event for doc through svc
+func dispatchGenerated(t *testing.T, svc FNNotificationService, doc *models.Document) {
+	t.Helper()
+	payload, _ := json.Marshal(dto.DocumentStatusChangedPayload{
+		DocumentID:     doc.ID,
+		UserDetailID:   doc.UserDetailID,
+		PreviousStatus: dto.DocStatusPDFUploading,
+		Status:         dto.DocStatusPDFCompleted,
+	})
+	event := dto.DomainEvent{ID: uuid.New(), EventType: dto.DomainEventDocumentStatusChanged, OccurredAt: time.Now(), Payload: payload}
+	if err := svc.Dispatch(context.Background(), event); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+}
+
+func notificationTestSetup(email string) (*memNotificationRepo, *models.Document) {
+	userDetailID, userID := uuid.New(), uuid.New()
+	doc := &models.Document{
+		ID:               uuid.New(),
+		UserDetailID:     userDetailID,
+		SerialCode:       "CERT-7",
+		VerificationCode: "VER123",
+		Event:            &models.Event{Title: "Taller de Go", Code: "EVT-7"},
+	}
+	repo := &memNotificationRepo{recipients: []dto.NotificationRecipient{{
+		UserDetailID: &userDetailID,
+		UserID:       &userID,
+		FirstName:    "José",
+		LastName:     "Núñez",
+		Email:        &email,
+	}}}
+	return repo, doc
+}
+
+func TestDispatcherDeliversEmailThroughSMTP(t *testing.T) {
+	sink := newSMTPSink(t)
+	repo, doc := notificationTestSetup("jose@example.com")
+	svc := NewFNNotificationService(repo, &stubNotificationDocRepo{doc: doc}, nil, mailer.New(sink.config()), nil,
+		NotificationConfig{VerifyBaseURL: "https://certs.example.com/verify/"})
+
+	dispatchGenerated(t, svc, doc)
+	if len(repo.notifications) != 1 {
+		t.Fatalf("in-app notifications = %d, want 1", len(repo.notifications))
+	}
+
+	sent, err := svc.DeliverDue(context.Background())
+	if err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("sent = %d, want 1", sent)
+	}
+
+	if d := repo.emailDelivery(t); d.Status != dto.DeliveryStatusSent || d.Attempts != 1 || d.SentAt == nil {
+		t.Fatalf("delivery not marked sent: status=%s attempts=%d", d.Status, d.Attempts)
+	}
+
+	msgs := sink.received()
+	if len(msgs) != 1 {
+		t.Fatalf("sink received %d messages, want 1", len(msgs))
+	}
+	if msgs[0].From != "no-reply@example.com" || len(msgs[0].To) != 1 || msgs[0].To[0] != "jose@example.com" {
+		t.Fatalf("envelope from=%q to=%v", msgs[0].From, msgs[0].To)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(msgs[0].Data))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+	if subject != "Tu certificado CERT-7 está disponible" {
+		t.Errorf("subject = %q", subject)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	if err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	for _, want := range []string{"Hola José", `"Taller de Go"`, "https://certs.example.com/verify/VER123"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body misses %q:\n%s", want, body)
+		}
+	}
+
+	// the queue is drained, nothing is sent twice
+	if sent, _ := svc.DeliverDue(context.Background()); sent != 0 || len(sink.received()) != 1 {
+		t.Fatalf("second run sent %d, sink has %d messages", sent, len(sink.received()))
+	}
+}
+
+func TestDispatcherRetriesRejectedEmail(t *testing.T) {
+	sink := newSMTPSink(t, "bounce@example.com")
+	repo, doc := notificationTestSetup("bounce@example.com")
+	svc := NewFNNotificationService(repo, &stubNotificationDocRepo{doc: doc}, nil, mailer.New(sink.config()), nil,
+		NotificationConfig{MaxAttempts: 2, RetryBackoff: time.Minute})
+
+	dispatchGenerated(t, svc, doc)
+
+	before := time.Now().UTC()
+	if sent, err := svc.DeliverDue(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("DeliverDue = %d, %v; want 0, nil", sent, err)
+	}
+
+	d := repo.emailDelivery(t)
+	if d.Status != dto.DeliveryStatusPending || d.Attempts != 1 {
+		t.Fatalf("after first attempt: status=%s attempts=%d, want PENDING/1", d.Status, d.Attempts)
+	}
+	if d.LastError == nil || !strings.Contains(*d.LastError, "RCPT TO") {
+		t.Fatalf("last error = %v, want the RCPT TO rejection", d.LastError)
+	}
+	if d.NextAttemptAt.Before(before.Add(time.Minute)) {
+		t.Fatalf("next attempt %s not backed off", d.NextAttemptAt)
+	}
+	if len(sink.received()) != 0 {
+		t.Fatal("sink accepted a rejected recipient")
+	}
+
+	// make the retry due; the second failure exhausts MaxAttempts
+	repo.update(d.ID, func(d *models.NotificationDelivery) { d.NextAttemptAt = time.Now().UTC() })
+	if _, err := svc.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if d := repo.emailDelivery(t); d.Status != dto.DeliveryStatusFailed || d.Attempts != 2 {
+		t.Fatalf("after last attempt: status=%s attempts=%d, want FAILED/2", d.Status, d.Attempts)
+	}
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// FNQuestionBankService defines the interface for the reusable question bank
+type FNQuestionBankService interface {
+	Create(ctx context.Context, userID uuid.UUID, req dto.BankQuestionRequest) (*dto.BankQuestionResponse, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*dto.BankQuestionResponse, error)
+	List(ctx context.Context, params dto.BankQuestionListQuery) ([]dto.BankQuestionResponse, int64, error)
+	// Update replaces a question; attempts that already drew it keep their own copy
+	Update(ctx context.Context, id uuid.UUID, req dto.BankQuestionRequest) (*dto.BankQuestionResponse, error)
+	// Enable and Disable control whether the question can be drawn
+	Enable(ctx context.Context, id uuid.UUID) error
+	Disable(ctx context.Context, id uuid.UUID) error
+	// ItemStats computes success rate and discrimination of the listed questions
+	// from the scores of graded attempts
+	ItemStats(ctx context.Context, params dto.BankQuestionListQuery) ([]dto.BankItemStats, int64, error)
+}
+
+type fnQuestionBankService struct {
+	repo repository.FNQuestionBankRepository
+}
+
+// NewFNQuestionBankService creates a new FN question bank service
+func NewFNQuestionBankService(repo repository.FNQuestionBankRepository) FNQuestionBankService {
+	return &fnQuestionBankService{repo: repo}
+}
+
+func (s *fnQuestionBankService) Create(ctx context.Context, userID uuid.UUID, req dto.BankQuestionRequest) (*dto.BankQuestionResponse, error) {
+	now := time.Now()
+	question := &models.BankQuestion{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyBankQuestion(question, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, question); err != nil {
+		return nil, fmt.Errorf("error creating bank question: %w", err)
+	}
+
+	return s.GetByID(ctx, question.ID)
+}
+
+func (s *fnQuestionBankService) GetByID(ctx context.Context, id uuid.UUID) (*dto.BankQuestionResponse, error) {
+	question, err := s.getQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toBankQuestionResponse(question), nil
+}
+
+func (s *fnQuestionBankService) List(ctx context.Context, params dto.BankQuestionListQuery) ([]dto.BankQuestionResponse, int64, error) {
+	questions, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing bank questions: %w", err)
+	}
+
+	items := make([]dto.BankQuestionResponse, 0, len(questions))
+	for i := range questions {
+		items = append(items, *toBankQuestionResponse(&questions[i]))
+	}
+	return items, total, nil
+}
+
+func (s *fnQuestionBankService) Update(ctx context.Context, id uuid.UUID, req dto.BankQuestionRequest) (*dto.BankQuestionResponse, error) {
+	question, err := s.getQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBankQuestion(question, req); err != nil {
+		return nil, err
+	}
+	question.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, question); err != nil {
+		return nil, fmt.Errorf("error updating bank question: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *fnQuestionBankService) Enable(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getQuestion(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.SetActive(ctx, id, true)
+}
+
+func (s *fnQuestionBankService) Disable(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getQuestion(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.SetActive(ctx, id, false)
+}
+
+func (s *fnQuestionBankService) ItemStats(ctx context.Context, params dto.BankQuestionListQuery) ([]dto.BankItemStats, int64, error) {
+	questions, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing bank questions: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	rows, err := s.repo.ListItemScores(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching item scores: %w", err)
+	}
+	byQuestion := make(map[uuid.UUID][]dto.BankItemScoreRow, len(questions))
+	for _, row := range rows {
+		byQuestion[row.BankQuestionID] = append(byQuestion[row.BankQuestionID], row)
+	}
+
+	items := make([]dto.BankItemStats, 0, len(questions))
+	for i := range questions {
+		items = append(items, bankItemStats(&questions[i], byQuestion[questions[i].ID]))
+	}
+	return items, total, nil
+}
+
+func (s *fnQuestionBankService) getQuestion(ctx context.Context, id uuid.UUID) (*models.BankQuestion, error) {
+	question, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching bank question: %w", err)
+	}
+	if question == nil {
+		return nil, fmt.Errorf("bank question not found")
+	}
+	return question, nil
+}
+
+// applyBankQuestion validates a request with the evaluation authoring rules and
+// copies it into the question, replacing its options
+func applyBankQuestion(question *models.BankQuestion, req dto.BankQuestionRequest) error {
+	topic := strings.TrimSpace(req.Topic)
+	if topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if len(topic) > 100 {
+		return fmt.Errorf("invalid topic: must be at most 100 characters")
+	}
+	difficulty := dto.DifficultyMedium
+	if value := strings.TrimSpace(req.Difficulty); value != "" {
+		difficulty = strings.ToUpper(value)
+		if !slices.Contains(dto.Difficulties, difficulty) {
+			return fmt.Errorf("invalid difficulty %q", req.Difficulty)
+		}
+	}
+
+	built, err := buildEvaluationQuestion(req.EvaluationQuestionInput)
+	if err != nil {
+		return err
+	}
+
+	question.Topic = topic
+	question.Difficulty = difficulty
+	question.QuestionText = built.QuestionText
+	question.QuestionType = built.QuestionType
+	question.MaxScore = built.MaxScore
+	question.Options = make([]models.BankQuestionOption, 0, len(built.Options))
+	for _, o := range built.Options {
+		question.Options = append(question.Options, models.BankQuestionOption{
+			QuestionID: question.ID,
+			OptionKey:  o.OptionKey,
+			OptionText: o.OptionText,
+			IsCorrect:  o.IsCorrect,
+			OrderIndex: o.OrderIndex,
+		})
+	}
+	return nil
+}
+
+// instanceQuestion copies a drawn bank question into an attempt; grading and
+// reports then work on the copy as on any fixed question
+func instanceQuestion(q *models.BankQuestion, evaluationID, attemptID uuid.UUID, number int) models.EvaluationQuestion {
+	options := make([]models.EvaluationQuestionOption, 0, len(q.Options))
+	for _, o := range q.Options {
+		options = append(options, models.EvaluationQuestionOption{
+			OptionKey:  o.OptionKey,
+			OptionText: o.OptionText,
+			IsCorrect:  o.IsCorrect,
+			OrderIndex: o.OrderIndex,
+		})
+	}
+	bankQuestionID := q.ID
+	return models.EvaluationQuestion{
+		ID:             uuid.New(),
+		EvaluationID:   evaluationID,
+		AttemptID:      &attemptID,
+		BankQuestionID: &bankQuestionID,
+		QuestionNumber: number,
+		QuestionText:   q.QuestionText,
+		QuestionType:   q.QuestionType,
+		MaxScore:       q.MaxScore,
+		Options:        options,
+	}
+}
+
+// bankItemStats runs the classical item analysis: the success rate is the mean share
+// of the item's points obtained, and the discrimination is the Pearson correlation
+// between that share and the share obtained in the rest of the attempt
+func bankItemStats(q *models.BankQuestion, rows []dto.BankItemScoreRow) dto.BankItemStats {
+	stats := dto.BankItemStats{
+		QuestionID:   q.ID,
+		Topic:        q.Topic,
+		Difficulty:   q.Difficulty,
+		QuestionText: q.QuestionText,
+		QuestionType: q.QuestionType,
+		IsActive:     q.IsActive,
+	}
+
+	var items, rests []float64
+	sum := 0.0
+	for _, row := range rows {
+		if row.ItemMax <= 0 {
+			continue
+		}
+		item := row.ItemScore / row.ItemMax
+		stats.Responses++
+		sum += item
+		if row.ItemScore >= row.ItemMax {
+			stats.FullCredit++
+		}
+		// an attempt made of this item alone says nothing about discrimination
+		if restMax := row.AttemptMax - row.ItemMax; restMax > 0 {
+			items = append(items, item)
+			rests = append(rests, (row.AttemptScore-row.ItemScore)/restMax)
+		}
+	}
+	if stats.Responses == 0 {
+		return stats
+	}
+
+	successRate := roundStat(sum / float64(stats.Responses))
+	stats.SuccessRate = &successRate
+	if r, ok := pearson(items, rests); ok {
+		discrimination := roundStat(r)
+		stats.Discrimination = &discrimination
+	}
+	return stats
+}
+
+// pearson returns the correlation of two samples; ok is false with fewer than two
+// pairs or when either sample has no variance
+func pearson(xs, ys []float64) (float64, bool) {
+	n := len(xs)
+	if n < 2 || len(ys) != n {
+		return 0, false
+	}
+
+	meanX, meanY := 0.0, 0.0
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+func roundStat(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+func toBankQuestionResponse(q *models.BankQuestion) *dto.BankQuestionResponse {
+	resp := &dto.BankQuestionResponse{
+		ID:           q.ID,
+		Topic:        q.Topic,
+		Difficulty:   q.Difficulty,
+		QuestionText: q.QuestionText,
+		QuestionType: q.QuestionType,
+		MaxScore:     q.MaxScore,
+		IsActive:     q.IsActive,
+		CreatedBy:    q.CreatedBy,
+		CreatedAt:    q.CreatedAt,
+		UpdatedAt:    q.UpdatedAt,
+	}
+	for _, o := range q.Options {
+		isCorrect := o.IsCorrect
+		resp.Options = append(resp.Options, dto.EvaluationOptionResponse{
+			Key:       o.OptionKey,
+			Text:      o.OptionText,
+			IsCorrect: &isCorrect,
+		})
+	}
+	return resp
+}
\ No newline at end of file
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// RevocationListConfig holds signing settings for the public revocation list
+type RevocationListConfig struct {
+	SigningKey ed25519.PrivateKey
+	Issuer     string
+	Refresh    time.Duration
+}
+
+// FNRevocationService defines the interface for public certificate status checks
+type FNRevocationService interface {
+	// SignedList returns the current signed revocation list and when it will be regenerated
+	SignedList(ctx context.Context) (*dto.SignedRevocationList, time.Time, error)
+	Keys() dto.JSONWebKeySet
+	Verify(ctx context.Context, verificationCode string) (*dto.PublicVerificationResponse, error)
+}
+
+type fnRevocationService struct {
+	revocationRepo repository.FNDocumentRevocationRepository
+	docRepo        repository.FNDocumentRepository
+	cfg            RevocationListConfig
+	kid            string
+
+	mu         sync.Mutex
+	cached     *dto.SignedRevocationList
+	nextUpdate time.Time
+	changedAt  time.Time
+}
+
+// NewFNRevocationService creates a new FN revocation service
+func NewFNRevocationService(
+	revocationRepo repository.FNDocumentRevocationRepository,
+	docRepo repository.FNDocumentRepository,
+	cfg RevocationListConfig,
+) FNRevocationService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "cert-server"
+	}
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = time.Hour
+	}
+
+	pub := cfg.SigningKey.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(pub)
+
+	return &fnRevocationService{
+		revocationRepo: revocationRepo,
+		docRepo:        docRepo,
+		cfg:            cfg,
+		kid:            base64.RawURLEncoding.EncodeToString(sum[:8]),
+	}
+}
+
+func (s *fnRevocationService) SignedList(ctx context.Context) (*dto.SignedRevocationList, time.Time, error) {
+	changedAt, err := s.revocationRepo.LastChangedAt(ctx)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error checking revocations: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	if s.cached != nil && now.Before(s.nextUpdate) && !changedAt.After(s.changedAt) {
+		return s.cached, s.nextUpdate, nil
+	}
+
+	revocations, err := s.revocationRepo.ListActive(ctx)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error fetching revocations: %w", err)
+	}
+
+	list := dto.RevocationList{
+		Version:     1,
+		Issuer:      s.cfg.Issuer,
+		GeneratedAt: now.Truncate(time.Second),
+		NextUpdate:  now.Add(s.cfg.Refresh).Truncate(time.Second),
+		Count:       len(revocations),
+		Entries:     make([]dto.RevocationListEntry, 0, len(revocations)),
+	}
+	for _, rev := range revocations {
+		list.Entries = append(list.Entries, dto.RevocationListEntry{
+			SerialCode:   rev.Document.SerialCode,
+			ReasonCode:   rev.ReasonCode,
+			RevokedAt:    rev.RevokedAt.UTC(),
+			SupersededBy: supersedingSerial(&rev),
+		})
+	}
+
+	signed, err := s.sign(list)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	s.cached = signed
+	s.nextUpdate = list.NextUpdate
+	s.changedAt = changedAt
+	return signed, s.nextUpdate, nil
+}
+
+func (s *fnRevocationService) Keys() dto.JSONWebKeySet {
+	pub := s.cfg.SigningKey.Public().(ed25519.PublicKey)
+	return dto.JSONWebKeySet{
+		Keys: []dto.JSONWebKey{{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+			Kid: s.kid,
+			Use: "sig",
+			Alg: "EdDSA",
+		}},
+	}
+}
+
+func (s *fnRevocationService) Verify(ctx context.Context, verificationCode string) (*dto.PublicVerificationResponse, error) {
+	doc, err := s.docRepo.GetByVerificationCode(ctx, verificationCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document not found")
+	}
+
+	resp := &dto.PublicVerificationResponse{
+		SerialCode:      doc.SerialCode,
+		Status:          verificationStatus(doc),
+		IssueDate:       doc.IssueDate,
+		BeneficiaryName: strings.TrimSpace(doc.UserDetail.FirstName + " " + doc.UserDetail.LastName),
+		Version:         doc.Version,
+	}
+	if doc.Event != nil {
+		resp.EventTitle = doc.Event.Title
+	}
+
+	if resp.Status == dto.VerificationStatusRevoked && len(doc.Revocations) > 0 {
+		rev := &doc.Revocations[0]
+		resp.Revocation = &dto.PublicRevocationResponse{
+			ReasonCode:   rev.ReasonCode,
+			RevokedAt:    rev.RevokedAt,
+			SupersededBy: supersedingSerial(rev),
+		}
+	}
+
+	// QR codes of reissued certificates point to the version in force
+	if doc.ReplacedByID != nil {
+		current, err := s.currentVersion(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil {
+			resp.CurrentVersion = &dto.PublicDocumentVersion{
+				SerialCode:       current.SerialCode,
+				VerificationCode: current.VerificationCode,
+				Version:          current.Version,
+				Status:           verificationStatus(current),
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// maxReissueChain bounds the walk along replaced_by links
+const maxReissueChain = 32
+
+// currentVersion follows the reissue chain up to the version in force
+func (s *fnRevocationService) currentVersion(ctx context.Context, doc *models.Document) (*models.Document, error) {
+	var current *models.Document
+	for next, hops := doc.ReplacedByID, 0; next != nil && hops < maxReissueChain; hops++ {
+		found, err := s.docRepo.GetByID(ctx, *next)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching current version: %w", err)
+		}
+		if found == nil {
+			break
+		}
+		current = found
+		next = found.ReplacedByID
+	}
+	return current, nil
+}
+
+func verificationStatus(doc *models.Document) string {
+	switch doc.Status {
+	case dto.DocStatusRejected:
+		return dto.VerificationStatusRevoked
+	case dto.DocStatusPDFCompleted:
+		return dto.VerificationStatusValid
+	default:
+		return dto.VerificationStatusNotIssued
+	}
+}
+
+// sign wraps the list in a JWS (flattened JSON serialization) signed with EdDSA
+func (s *fnRevocationService) sign(list dto.RevocationList) (*dto.SignedRevocationList, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "EdDSA",
+		"kid": s.kid,
+		"typ": "revocation-list+json",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding revocation list header: %w", err)
+	}
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding revocation list: %w", err)
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(s.cfg.SigningKey, []byte(protected+"."+encodedPayload))
+
+	return &dto.SignedRevocationList{
+		Protected: protected,
+		Payload:   encodedPayload,
+		Signature: base64.RawURLEncoding.EncodeToString(signature),
+	}, nil
+}
+
+func supersedingSerial(rev *models.DocumentRevocation) *string {
+	if rev.SupersededBy == nil {
+		return nil
+	}
+	return &rev.SupersededBy.SerialCode
+}
+
+// LoadRevocationSigningKey reads an Ed25519 private key (PKCS#8 PEM).
+// An empty path generates an ephemeral key, valid until the process restarts.
+func LoadRevocationSigningKey(path string) (ed25519.PrivateKey, bool, error) {
+	if path == "" {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, true, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading revocation signing key: %w", err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, false, fmt.Errorf("invalid revocation signing key: no PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid revocation signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, false, fmt.Errorf("invalid revocation signing key: not an Ed25519 key")
+	}
+	return key, false, nil
+}
@@ -0,0 +1,587 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// FNStudyMaterialService defines the interface for the learning path of study materials
+type FNStudyMaterialService interface {
+	// learning
+	// GetOutline returns the sections and subsections in order with the user's completion state
+	GetOutline(ctx context.Context, materialID, userID uuid.UUID) (*dto.StudyOutlineResponse, error)
+	GetProgress(ctx context.Context, materialID, userID uuid.UUID) (*dto.StudyProgressResponse, error)
+	// SetSubsectionProgress marks a subsection complete or incomplete and returns the material's progress
+	SetSubsectionProgress(ctx context.Context, subsectionID, userID uuid.UUID, req dto.StudyProgressRequest) (*dto.StudyProgressResponse, error)
+	ListAnnotations(ctx context.Context, subsectionID, userID uuid.UUID) ([]dto.StudyAnnotationResponse, error)
+	CreateAnnotation(ctx context.Context, subsectionID, userID uuid.UUID, req dto.StudyAnnotationRequest) (*dto.StudyAnnotationResponse, error)
+	// UpdateAnnotation and DeleteAnnotation only accept the author's own annotations
+	UpdateAnnotation(ctx context.Context, annotationID, userID uuid.UUID, req dto.StudyAnnotationRequest) (*dto.StudyAnnotationResponse, error)
+	DeleteAnnotation(ctx context.Context, annotationID, userID uuid.UUID) error
+
+	// authoring
+	CreateSection(ctx context.Context, materialID uuid.UUID, req dto.StudySectionRequest) (*dto.StudySectionResponse, error)
+	UpdateSection(ctx context.Context, sectionID uuid.UUID, req dto.StudySectionRequest) (*dto.StudySectionResponse, error)
+	// DeleteSection removes the section with its subsections, progress and annotations
+	DeleteSection(ctx context.Context, sectionID uuid.UUID) error
+	// ReorderSections renumbers the sections; the request must list all of them
+	ReorderSections(ctx context.Context, materialID uuid.UUID, req dto.StudyReorderRequest) error
+	CreateSubsection(ctx context.Context, sectionID uuid.UUID, req dto.StudySubsectionRequest) (*dto.StudySubsectionResponse, error)
+	UpdateSubsection(ctx context.Context, subsectionID uuid.UUID, req dto.StudySubsectionRequest) (*dto.StudySubsectionResponse, error)
+	DeleteSubsection(ctx context.Context, subsectionID uuid.UUID) error
+	// ReorderSubsections renumbers the subsections of a section; the request must list all of them
+	ReorderSubsections(ctx context.Context, sectionID uuid.UUID, req dto.StudyReorderRequest) error
+}
+
+type fnStudyMaterialService struct {
+	repo          repository.FNStudyMaterialRepository
+	certification FNEventCertificationService
+}
+
+// NewFNStudyMaterialService creates a new FN study material service. Completed progress
+// is handed to certification, which issues the certificates waiting on the material.
+func NewFNStudyMaterialService(repo repository.FNStudyMaterialRepository, certification FNEventCertificationService) FNStudyMaterialService {
+	return &fnStudyMaterialService{repo: repo, certification: certification}
+}
+
+// -- learning
+
+func (s *fnStudyMaterialService) GetOutline(ctx context.Context, materialID, userID uuid.UUID) (*dto.StudyOutlineResponse, error) {
+	material, err := s.repo.GetOutline(ctx, materialID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching study material: %w", err)
+	}
+	if material == nil {
+		return nil, fmt.Errorf("study material not found")
+	}
+
+	progress, err := s.repo.ListProgress(ctx, materialID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching progress: %w", err)
+	}
+	completed := make(map[uuid.UUID]*models.StudyProgress, len(progress))
+	for i := range progress {
+		if progress[i].Completed {
+			completed[progress[i].SubsectionID] = &progress[i]
+		}
+	}
+	annotations, err := s.repo.CountAnnotations(ctx, materialID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting annotations: %w", err)
+	}
+
+	resp := &dto.StudyOutlineResponse{
+		ID:          material.ID,
+		Title:       material.Title,
+		Description: material.Description,
+		Progress:    dto.StudyProgressResponse{MaterialID: material.ID},
+		Sections:    make([]dto.StudySectionResponse, 0, len(material.Sections)),
+	}
+	for i := range material.Sections {
+		section := toStudySectionResponse(&material.Sections[i])
+		for j := range material.Sections[i].Subsections {
+			sub := &material.Sections[i].Subsections[j]
+			item := toStudySubsectionResponse(sub)
+			item.AnnotationCount = annotations[sub.ID]
+			if p, ok := completed[sub.ID]; ok {
+				item.Completed = true
+				item.CompletedAt = p.CompletedAt
+				section.CompletedSubsections++
+			}
+			section.Subsections = append(section.Subsections, *item)
+		}
+		section.TotalSubsections = len(section.Subsections)
+		resp.Progress.TotalSubsections += int64(section.TotalSubsections)
+		resp.Progress.CompletedSubsections += int64(section.CompletedSubsections)
+		resp.Sections = append(resp.Sections, *section)
+	}
+	resp.Progress.Percentage = studyPercentage(resp.Progress.CompletedSubsections, resp.Progress.TotalSubsections)
+
+	return resp, nil
+}
+
+func (s *fnStudyMaterialService) GetProgress(ctx context.Context, materialID, userID uuid.UUID) (*dto.StudyProgressResponse, error) {
+	if _, err := s.getMaterial(ctx, materialID); err != nil {
+		return nil, err
+	}
+	return studyProgress(ctx, s.repo, materialID, userID)
+}
+
+func (s *fnStudyMaterialService) SetSubsectionProgress(ctx context.Context, subsectionID, userID uuid.UUID, req dto.StudyProgressRequest) (*dto.StudyProgressResponse, error) {
+	subsection, err := s.getSubsection(ctx, subsectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	materialID := subsection.Section.MaterialID
+	var previous *dto.StudyProgressResponse
+	if req.Completed {
+		if previous, err = studyProgress(ctx, s.repo, materialID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	progress := &models.StudyProgress{
+		ID:           uuid.New(),
+		UserID:       userID,
+		SubsectionID: subsectionID,
+		Completed:    req.Completed,
+	}
+	if req.Completed {
+		now := time.Now()
+		progress.CompletedAt = &now
+	}
+	if err := s.repo.SetProgress(ctx, progress); err != nil {
+		return nil, fmt.Errorf("error saving progress: %w", err)
+	}
+
+	current, err := studyProgress(ctx, s.repo, materialID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		s.certification.StudyProgressed(ctx, userID, previous, current)
+	}
+	return current, nil
+}
+
+func (s *fnStudyMaterialService) ListAnnotations(ctx context.Context, subsectionID, userID uuid.UUID) ([]dto.StudyAnnotationResponse, error) {
+	if _, err := s.getSubsection(ctx, subsectionID); err != nil {
+		return nil, err
+	}
+
+	annotations, err := s.repo.ListAnnotations(ctx, subsectionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing annotations: %w", err)
+	}
+
+	items := make([]dto.StudyAnnotationResponse, 0, len(annotations))
+	for i := range annotations {
+		items = append(items, *toStudyAnnotationResponse(&annotations[i]))
+	}
+	return items, nil
+}
+
+func (s *fnStudyMaterialService) CreateAnnotation(ctx context.Context, subsectionID, userID uuid.UUID, req dto.StudyAnnotationRequest) (*dto.StudyAnnotationResponse, error) {
+	if _, err := s.getSubsection(ctx, subsectionID); err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	now := time.Now()
+	annotation := &models.StudyAnnotation{
+		ID:           uuid.New(),
+		UserID:       userID,
+		SubsectionID: subsectionID,
+		Content:      content,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.CreateAnnotation(ctx, annotation); err != nil {
+		return nil, fmt.Errorf("error creating annotation: %w", err)
+	}
+
+	return toStudyAnnotationResponse(annotation), nil
+}
+
+func (s *fnStudyMaterialService) UpdateAnnotation(ctx context.Context, annotationID, userID uuid.UUID, req dto.StudyAnnotationRequest) (*dto.StudyAnnotationResponse, error) {
+	annotation, err := s.getOwnAnnotation(ctx, annotationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	annotation.Content = content
+	annotation.UpdatedAt = time.Now()
+	if err := s.repo.UpdateAnnotation(ctx, annotation); err != nil {
+		return nil, fmt.Errorf("error updating annotation: %w", err)
+	}
+
+	return toStudyAnnotationResponse(annotation), nil
+}
+
+func (s *fnStudyMaterialService) DeleteAnnotation(ctx context.Context, annotationID, userID uuid.UUID) error {
+	if _, err := s.getOwnAnnotation(ctx, annotationID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteAnnotation(ctx, annotationID); err != nil {
+		return fmt.Errorf("error deleting annotation: %w", err)
+	}
+	return nil
+}
+
+// -- authoring
+
+func (s *fnStudyMaterialService) CreateSection(ctx context.Context, materialID uuid.UUID, req dto.StudySectionRequest) (*dto.StudySectionResponse, error) {
+	if _, err := s.getMaterial(ctx, materialID); err != nil {
+		return nil, err
+	}
+
+	section := &models.StudySection{
+		ID:         uuid.New(),
+		MaterialID: materialID,
+		CreatedAt:  time.Now(),
+	}
+	if err := applyStudySection(section, req); err != nil {
+		return nil, err
+	}
+	if section.OrderIndex == nil {
+		next, err := s.repo.NextSectionIndex(ctx, materialID)
+		if err != nil {
+			return nil, fmt.Errorf("error computing section order: %w", err)
+		}
+		section.OrderIndex = &next
+	}
+
+	if err := s.repo.CreateSection(ctx, section); err != nil {
+		return nil, fmt.Errorf("error creating section: %w", err)
+	}
+
+	return toStudySectionResponse(section), nil
+}
+
+func (s *fnStudyMaterialService) UpdateSection(ctx context.Context, sectionID uuid.UUID, req dto.StudySectionRequest) (*dto.StudySectionResponse, error) {
+	section, err := s.getSection(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyStudySection(section, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSection(ctx, section); err != nil {
+		return nil, fmt.Errorf("error updating section: %w", err)
+	}
+
+	return toStudySectionResponse(section), nil
+}
+
+func (s *fnStudyMaterialService) DeleteSection(ctx context.Context, sectionID uuid.UUID) error {
+	if _, err := s.getSection(ctx, sectionID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSection(ctx, sectionID); err != nil {
+		return fmt.Errorf("error deleting section: %w", err)
+	}
+	return nil
+}
+
+func (s *fnStudyMaterialService) ReorderSections(ctx context.Context, materialID uuid.UUID, req dto.StudyReorderRequest) error {
+	material, err := s.repo.GetOutline(ctx, materialID)
+	if err != nil {
+		return fmt.Errorf("error fetching study material: %w", err)
+	}
+	if material == nil {
+		return fmt.Errorf("study material not found")
+	}
+
+	current := make([]uuid.UUID, 0, len(material.Sections))
+	for _, section := range material.Sections {
+		current = append(current, section.ID)
+	}
+	ids, err := parseStudyOrder(req.IDs, current, "section")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ReorderSections(ctx, materialID, ids); err != nil {
+		return fmt.Errorf("error reordering sections: %w", err)
+	}
+	return nil
+}
+
+func (s *fnStudyMaterialService) CreateSubsection(ctx context.Context, sectionID uuid.UUID, req dto.StudySubsectionRequest) (*dto.StudySubsectionResponse, error) {
+	if _, err := s.getSection(ctx, sectionID); err != nil {
+		return nil, err
+	}
+
+	subsection := &models.StudySubsection{
+		ID:        uuid.New(),
+		SectionID: sectionID,
+		CreatedAt: time.Now(),
+	}
+	if err := applyStudySubsection(subsection, req); err != nil {
+		return nil, err
+	}
+	if subsection.OrderIndex == nil {
+		next, err := s.repo.NextSubsectionIndex(ctx, sectionID)
+		if err != nil {
+			return nil, fmt.Errorf("error computing subsection order: %w", err)
+		}
+		subsection.OrderIndex = &next
+	}
+
+	if err := s.repo.CreateSubsection(ctx, subsection); err != nil {
+		return nil, fmt.Errorf("error creating subsection: %w", err)
+	}
+
+	return toStudySubsectionResponse(subsection), nil
+}
+
+func (s *fnStudyMaterialService) UpdateSubsection(ctx context.Context, subsectionID uuid.UUID, req dto.StudySubsectionRequest) (*dto.StudySubsectionResponse, error) {
+	subsection, err := s.getSubsection(ctx, subsectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyStudySubsection(subsection, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSubsection(ctx, subsection); err != nil {
+		return nil, fmt.Errorf("error updating subsection: %w", err)
+	}
+
+	return toStudySubsectionResponse(subsection), nil
+}
+
+func (s *fnStudyMaterialService) DeleteSubsection(ctx context.Context, subsectionID uuid.UUID) error {
+	if _, err := s.getSubsection(ctx, subsectionID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubsection(ctx, subsectionID); err != nil {
+		return fmt.Errorf("error deleting subsection: %w", err)
+	}
+	return nil
+}
+
+func (s *fnStudyMaterialService) ReorderSubsections(ctx context.Context, sectionID uuid.UUID, req dto.StudyReorderRequest) error {
+	section, err := s.getSection(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	material, err := s.repo.GetOutline(ctx, section.MaterialID)
+	if err != nil {
+		return fmt.Errorf("error fetching study material: %w", err)
+	}
+
+	var current []uuid.UUID
+	if material != nil {
+		for _, sec := range material.Sections {
+			if sec.ID != sectionID {
+				continue
+			}
+			for _, sub := range sec.Subsections {
+				current = append(current, sub.ID)
+			}
+		}
+	}
+	ids, err := parseStudyOrder(req.IDs, current, "subsection")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ReorderSubsections(ctx, sectionID, ids); err != nil {
+		return fmt.Errorf("error reordering subsections: %w", err)
+	}
+	return nil
+}
+
+// -- helpers
+
+// studyProgress computes a user's completion of a study material; events requiring the
+// material use it as well to gate their evaluation and certificates
+func studyProgress(ctx context.Context, repo repository.FNStudyMaterialRepository, materialID, userID uuid.UUID) (*dto.StudyProgressResponse, error) {
+	total, err := repo.CountSubsections(ctx, materialID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting subsections: %w", err)
+	}
+	completed, err := repo.CountCompleted(ctx, materialID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting completed subsections: %w", err)
+	}
+
+	return &dto.StudyProgressResponse{
+		MaterialID:           materialID,
+		TotalSubsections:     total,
+		CompletedSubsections: completed,
+		Percentage:           studyPercentage(completed, total),
+	}, nil
+}
+
+// studyRequirementMet reports whether the progress reaches an event's minimum; a
+// material without subsections has nothing to complete
+func studyRequirementMet(progress *dto.StudyProgressResponse, minPercentage float64) bool {
+	return progress.TotalSubsections == 0 || progress.Percentage >= minPercentage
+}
+
+// studyRequirement computes the user's progress in the event's study material and
+// whether it reaches the event's minimum
+func studyRequirement(ctx context.Context, repo repository.FNStudyMaterialRepository, event *models.Event, userID uuid.UUID) (*dto.StudyProgressResponse, bool, error) {
+	progress, err := studyProgress(ctx, repo, *event.StudyMaterialID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	return progress, studyRequirementMet(progress, event.StudyMinPercentage), nil
+}
+
+func (s *fnStudyMaterialService) getMaterial(ctx context.Context, id uuid.UUID) (*models.StudyMaterial, error) {
+	material, err := s.repo.GetMaterial(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching study material: %w", err)
+	}
+	if material == nil {
+		return nil, fmt.Errorf("study material not found")
+	}
+	return material, nil
+}
+
+func (s *fnStudyMaterialService) getSection(ctx context.Context, id uuid.UUID) (*models.StudySection, error) {
+	section, err := s.repo.GetSection(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching section: %w", err)
+	}
+	if section == nil {
+		return nil, fmt.Errorf("section not found")
+	}
+	return section, nil
+}
+
+func (s *fnStudyMaterialService) getSubsection(ctx context.Context, id uuid.UUID) (*models.StudySubsection, error) {
+	subsection, err := s.repo.GetSubsection(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching subsection: %w", err)
+	}
+	if subsection == nil {
+		return nil, fmt.Errorf("subsection not found")
+	}
+	return subsection, nil
+}
+
+func (s *fnStudyMaterialService) getOwnAnnotation(ctx context.Context, id, userID uuid.UUID) (*models.StudyAnnotation, error) {
+	annotation, err := s.repo.GetAnnotation(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching annotation: %w", err)
+	}
+	if annotation == nil {
+		return nil, fmt.Errorf("annotation not found")
+	}
+	if annotation.UserID != userID {
+		return nil, fmt.Errorf("access denied: annotation belongs to another user")
+	}
+	return annotation, nil
+}
+
+func applyStudySection(section *models.StudySection, req dto.StudySectionRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if req.OrderIndex != nil && *req.OrderIndex < 0 {
+		return fmt.Errorf("invalid order_index: must not be negative")
+	}
+
+	section.Title = title
+	section.Description = req.Description
+	if req.OrderIndex != nil {
+		section.OrderIndex = req.OrderIndex
+	}
+	return nil
+}
+
+func applyStudySubsection(subsection *models.StudySubsection, req dto.StudySubsectionRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if req.OrderIndex != nil && *req.OrderIndex < 0 {
+		return fmt.Errorf("invalid order_index: must not be negative")
+	}
+
+	subsection.Title = title
+	subsection.Description = req.Description
+	subsection.VideoURL = req.VideoURL
+	if req.OrderIndex != nil {
+		subsection.OrderIndex = req.OrderIndex
+	}
+	return nil
+}
+
+// parseStudyOrder checks that ids lists every current child exactly once
+func parseStudyOrder(values []string, current []uuid.UUID, kind string) ([]uuid.UUID, error) {
+	known := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+
+	ids := make([]uuid.UUID, 0, len(values))
+	seen := make(map[uuid.UUID]bool, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s id %q", kind, value)
+		}
+		if !known[id] {
+			return nil, fmt.Errorf("invalid order: %s %s does not belong here", kind, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("invalid order: %s %s is listed twice", kind, id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) != len(current) {
+		return nil, fmt.Errorf("invalid order: every %s must be listed (%d of %d)", kind, len(ids), len(current))
+	}
+	return ids, nil
+}
+
+func studyPercentage(completed, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundScore(float64(completed) / float64(total) * 100)
+}
+
+func toStudySectionResponse(section *models.StudySection) *dto.StudySectionResponse {
+	return &dto.StudySectionResponse{
+		ID:          section.ID,
+		Title:       section.Title,
+		Description: section.Description,
+		OrderIndex:  section.OrderIndex,
+		Subsections: []dto.StudySubsectionResponse{},
+	}
+}
+
+func toStudySubsectionResponse(subsection *models.StudySubsection) *dto.StudySubsectionResponse {
+	resp := &dto.StudySubsectionResponse{
+		ID:          subsection.ID,
+		SectionID:   subsection.SectionID,
+		Title:       subsection.Title,
+		Description: subsection.Description,
+		VideoURL:    subsection.VideoURL,
+		OrderIndex:  subsection.OrderIndex,
+	}
+	for _, r := range subsection.Resources {
+		resp.Resources = append(resp.Resources, dto.StudyResourceResponse{
+			ID:       r.ID,
+			FileName: r.FileName,
+			FileURL:  r.FileURL,
+			FileType: r.FileType,
+		})
+	}
+	return resp
+}
+
+func toStudyAnnotationResponse(annotation *models.StudyAnnotation) *dto.StudyAnnotationResponse {
+	return &dto.StudyAnnotationResponse{
+		ID:           annotation.ID,
+		SubsectionID: annotation.SubsectionID,
+		Content:      annotation.Content,
+		CreatedAt:    annotation.CreatedAt,
+		UpdatedAt:    annotation.UpdatedAt,
+	}
+}
\ No newline at end of file
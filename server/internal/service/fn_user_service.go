@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+// nationalIDPattern matches the national IDs accepted from tokens (DNI, CE, passport);
+// the length matches the users.national_id column
+var nationalIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{8,20}$`)
+
+// FNUserService defines the interface for local accounts linked to Keycloak subjects
+type FNUserService interface {
+	Provision(ctx context.Context, req dto.UserProvisionRequest) (uuid.UUID, error)
+	GetMe(ctx context.Context, userID uuid.UUID) (*dto.MeResponse, error)
+}
+
+// UserProvisioningConfig controls how much the national_id claim is trusted
+type UserProvisioningConfig struct {
+	// RequireNationalID rejects new accounts whose token has no national_id claim
+	RequireNationalID bool
+	// NationalIDAdminManaged must only be set when users cannot edit the attribute in
+	// Keycloak; only then may the claim link an existing account or replace a stored ID
+	NationalIDAdminManaged bool
+}
+
+type fnUserService struct {
+	repo           repository.FNUserRepository
+	userDetailRepo repository.FNUserDetailRepository
+	cfg            UserProvisioningConfig
+}
+
+// NewFNUserService creates a new FN user service
+func NewFNUserService(repo repository.FNUserRepository, userDetailRepo repository.FNUserDetailRepository, cfg UserProvisioningConfig) FNUserService {
+	return &fnUserService{
+		repo:           repo,
+		userDetailRepo: userDetailRepo,
+		cfg:            cfg,
+	}
+}
+
+// Provision upserts the local user for a Keycloak subject and returns its local ID.
+// Accounts created before provisioning (seeded, or keyed by the subject itself)
+// are linked instead of duplicated.
+func (s *fnUserService) Provision(ctx context.Context, req dto.UserProvisionRequest) (uuid.UUID, error) {
+	req.Subject = strings.TrimSpace(req.Subject)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.NationalID = strings.TrimSpace(req.NationalID)
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+
+	if req.Subject == "" {
+		return uuid.Nil, fmt.Errorf("invalid token: subject is required")
+	}
+	if req.NationalID != "" && !nationalIDPattern.MatchString(req.NationalID) {
+		return uuid.Nil, fmt.Errorf("invalid token: malformed national_id claim")
+	}
+
+	user, err := s.repo.GetByKeycloakSub(ctx, req.Subject)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	if user == nil {
+		user, err = s.findUnlinked(ctx, req)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+
+	if user == nil {
+		user, err = s.create(ctx, req, now)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		log.Info().
+			Str("user_id", user.ID.String()).
+			Str("keycloak_sub", req.Subject).
+			Msg("Provisioned local user")
+		return user.ID, nil
+	}
+
+	sub := req.Subject
+	user.KeycloakSub = &sub
+	if req.Email != "" {
+		user.Email = req.Email
+	}
+	if err := s.applyNationalID(ctx, user, req.NationalID); err != nil {
+		return uuid.Nil, err
+	}
+	if req.FirstName != "" {
+		user.FirstName = req.FirstName
+	}
+	if req.LastName != "" {
+		user.LastName = req.LastName
+	}
+	user.Roles = joinRoles(req.Roles)
+	user.LastLoginAt = &now
+	user.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		return uuid.Nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return user.ID, nil
+}
+
+func (s *fnUserService) GetMe(ctx context.Context, userID uuid.UUID) (*dto.MeResponse, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	resp := &dto.MeResponse{
+		ID:          user.ID,
+		Email:       user.Email,
+		NationalID:  user.NationalID,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+	}
+	if user.KeycloakSub != nil {
+		resp.KeycloakSub = *user.KeycloakSub
+	}
+
+	// the beneficiary record shares the national ID with the account
+	if user.NationalID != "" {
+		detail, err := s.userDetailRepo.GetByNationalID(ctx, user.NationalID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching user detail: %w", err)
+		}
+		if detail != nil {
+			resp.UserDetail = &dto.UserDetailEmbedded{
+				ID:         detail.ID,
+				NationalID: detail.NationalID,
+				FirstName:  detail.FirstName,
+				LastName:   detail.LastName,
+				Email:      detail.Email,
+				Phone:      detail.Phone,
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// findUnlinked looks for an existing account without a subject: first by the subject
+// used as ID (rows written before provisioning existed), then by email and national ID.
+// An unverified email could belong to anyone, so it is never used to link; neither is a
+// national ID the user can edit in Keycloak.
+func (s *fnUserService) findUnlinked(ctx context.Context, req dto.UserProvisionRequest) (*models.User, error) {
+	lookups := make([]func() (*models.User, error), 0, 3)
+	if id, err := uuid.Parse(req.Subject); err == nil {
+		lookups = append(lookups, func() (*models.User, error) { return s.repo.GetByID(ctx, id) })
+	}
+	if req.Email != "" && req.EmailVerified {
+		lookups = append(lookups, func() (*models.User, error) { return s.repo.GetByEmail(ctx, req.Email) })
+	}
+	if req.NationalID != "" && s.cfg.NationalIDAdminManaged {
+		lookups = append(lookups, func() (*models.User, error) { return s.repo.GetByNationalID(ctx, req.NationalID) })
+	}
+
+	for _, lookup := range lookups {
+		user, err := lookup()
+		if err != nil {
+			return nil, fmt.Errorf("error fetching user: %w", err)
+		}
+		if user != nil && user.KeycloakSub == nil {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fnUserService) create(ctx context.Context, req dto.UserProvisionRequest, now time.Time) (*models.User, error) {
+	if req.Email == "" {
+		return nil, fmt.Errorf("invalid token: email claim is required")
+	}
+	if req.NationalID == "" && s.cfg.RequireNationalID {
+		return nil, fmt.Errorf("invalid token: national_id claim is required")
+	}
+	if req.NationalID != "" {
+		holder, err := s.repo.GetByNationalID(ctx, req.NationalID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching user: %w", err)
+		}
+		if holder != nil {
+			// another account holds this national ID; it is not linked unless admin-managed
+			logNationalIDConflict(req.Subject, holder)
+			req.NationalID = ""
+		}
+	}
+
+	// keep the subject as local ID when possible so existing created_by values stay valid
+	id, err := uuid.Parse(req.Subject)
+	if err != nil {
+		id = uuid.New()
+	} else if taken, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	} else if taken != nil {
+		id = uuid.New()
+	}
+
+	sub := req.Subject
+	user := &models.User{
+		ID:          id,
+		Email:       req.Email,
+		NationalID:  req.NationalID,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Roles:       joinRoles(req.Roles),
+		KeycloakSub: &sub,
+		LastLoginAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		// a concurrent request may have provisioned the same subject
+		existing, getErr := s.repo.GetByKeycloakSub(ctx, req.Subject)
+		if getErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+	return user, nil
+}
+
+// applyNationalID stores the claim on an existing account. A user-editable claim only
+// fills an empty national ID, and never one already held by another account.
+func (s *fnUserService) applyNationalID(ctx context.Context, user *models.User, nationalID string) error {
+	if nationalID == "" || nationalID == user.NationalID {
+		return nil
+	}
+	if user.NationalID != "" && !s.cfg.NationalIDAdminManaged {
+		return nil
+	}
+
+	holder, err := s.repo.GetByNationalID(ctx, nationalID)
+	if err != nil {
+		return fmt.Errorf("error fetching user: %w", err)
+	}
+	if holder != nil && holder.ID != user.ID {
+		logNationalIDConflict(*user.KeycloakSub, holder)
+		return nil
+	}
+
+	user.NationalID = nationalID
+	return nil
+}
+
+func logNationalIDConflict(subject string, holder *models.User) {
+	log.Warn().
+		Str("keycloak_sub", subject).
+		Str("holder_id", holder.ID.String()).
+		Msg("national_id claim already belongs to another account, not stored")
+}
+
+// joinRoles stores the roles sorted and without duplicates
+func joinRoles(roles []string) string {
+	set := make(map[string]bool, len(roles))
+	sorted := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role = strings.TrimSpace(role); role != "" && !set[role] {
+			set[role] = true
+			sorted = append(sorted, role)
+		}
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
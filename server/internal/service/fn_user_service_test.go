@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain/models"
+	"server/internal/dto"
+	"server/internal/repository"
+)
+
+type memUserRepo struct {
+	repository.FNUserRepository
+	users []*models.User
+}
+
+func (r *memUserRepo) find(match func(*models.User) bool) *models.User {
+	for _, u := range r.users {
+		if match(u) {
+			copied := *u
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (r *memUserRepo) GetByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.ID == id }), nil
+}
+
+func (r *memUserRepo) GetByKeycloakSub(_ context.Context, sub string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.KeycloakSub != nil && *u.KeycloakSub == sub }), nil
+}
+
+func (r *memUserRepo) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) }), nil
+}
+
+func (r *memUserRepo) GetByNationalID(_ context.Context, nationalID string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.NationalID == nationalID }), nil
+}
+
+func (r *memUserRepo) Create(_ context.Context, user *models.User) error {
+	copied := *user
+	r.users = append(r.users, &copied)
+	return nil
+}
+
+func (r *memUserRepo) Update(_ context.Context, user *models.User) error {
+	for i, u := range r.users {
+		if u.ID == user.ID {
+			copied := *user
+			r.users[i] = &copied
+		}
+	}
+	return nil
+}
+
+func TestProvisionAccountsWithoutNationalID(t *testing.T) {
+	ctx := context.Background()
+	staff := dto.UserProvisionRequest{Subject: "staff-sub", Email: "staff@example.com"}
+
+	repo := &memUserRepo{}
+	id, err := NewFNUserService(repo, nil, UserProvisioningConfig{}).Provision(ctx, staff)
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if user, _ := repo.GetByID(ctx, id); user == nil || user.NationalID != "" {
+		t.Fatalf("staff account = %+v", user)
+	}
+
+	required := NewFNUserService(&memUserRepo{}, nil, UserProvisioningConfig{RequireNationalID: true})
+	if _, err := required.Provision(ctx, staff); err == nil || !strings.Contains(err.Error(), "national_id claim is required") {
+		t.Fatalf("got %v, want national_id claim is required", err)
+	}
+}
+
+func TestProvisionTrustsNationalIDOnlyWhenAdminManaged(t *testing.T) {
+	ctx := context.Background()
+	seeded := func() *memUserRepo {
+		return &memUserRepo{users: []*models.User{{ID: uuid.New(), Email: "victim@example.com", NationalID: "12345678"}}}
+	}
+	req := dto.UserProvisionRequest{Subject: "new-sub", Email: "other@example.com", NationalID: "12345678"}
+
+	t.Run("user-editable claim does not link", func(t *testing.T) {
+		repo := seeded()
+		id, err := NewFNUserService(repo, nil, UserProvisioningConfig{}).Provision(ctx, req)
+		if err != nil {
+			t.Fatalf("Provision: %v", err)
+		}
+		if id == repo.users[0].ID || repo.users[0].KeycloakSub != nil {
+			t.Fatal("existing account linked on a user-editable national_id")
+		}
+		if created, _ := repo.GetByID(ctx, id); created.NationalID != "" {
+			t.Fatalf("new account took the held national ID %q", created.NationalID)
+		}
+	})
+
+	t.Run("admin-managed claim links", func(t *testing.T) {
+		repo := seeded()
+		id, err := NewFNUserService(repo, nil, UserProvisioningConfig{NationalIDAdminManaged: true}).Provision(ctx, req)
+		if err != nil {
+			t.Fatalf("Provision: %v", err)
+		}
+		if id != repo.users[0].ID {
+			t.Fatal("account not linked on an admin-managed national_id")
+		}
+	})
+}
+
+func TestProvisionKeepsStoredNationalID(t *testing.T) {
+	ctx := context.Background()
+	sub := "sub-1"
+	owner := &models.User{ID: uuid.New(), Email: "ana@example.com", NationalID: "11111111", KeycloakSub: &sub}
+	other := &models.User{ID: uuid.New(), Email: "luis@example.com", NationalID: "22222222"}
+
+	cases := []struct {
+		name    string
+		cfg     UserProvisioningConfig
+		claim   string
+		current string
+		want    string
+	}{
+		{"user-editable claim never replaces", UserProvisioningConfig{}, "33333333", "11111111", "11111111"},
+		{"user-editable claim fills an empty ID", UserProvisioningConfig{}, "33333333", "", "33333333"},
+		{"admin-managed claim replaces", UserProvisioningConfig{NationalIDAdminManaged: true}, "33333333", "11111111", "33333333"},
+		{"ID held by another account is not taken", UserProvisioningConfig{NationalIDAdminManaged: true}, "22222222", "11111111", "11111111"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := *owner
+			current.NationalID = tc.current
+			held := *other
+			repo := &memUserRepo{users: []*models.User{&current, &held}}
+
+			_, err := NewFNUserService(repo, nil, tc.cfg).Provision(ctx, dto.UserProvisionRequest{
+				Subject: sub, Email: owner.Email, NationalID: tc.claim,
+			})
+			if err != nil {
+				t.Fatalf("Provision: %v", err)
+			}
+			if got, _ := repo.GetByID(ctx, owner.ID); got.NationalID != tc.want {
+				t.Fatalf("national ID = %q, want %q", got.NationalID, tc.want)
+			}
+		})
+	}
+}
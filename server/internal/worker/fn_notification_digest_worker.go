@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"server/internal/service"
+)
+
+type FNNotificationDigestWorker struct {
+	svc      service.FNNotificationService
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewFNNotificationDigestWorker creates the worker that folds hourly/daily notifications
+// into digests. interval is how often closed digest windows are checked.
+func NewFNNotificationDigestWorker(svc service.FNNotificationService, interval time.Duration) *FNNotificationDigestWorker {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &FNNotificationDigestWorker{
+		svc:      svc,
+		interval: interval,
+	}
+}
+
+// Start runs a first pass right away (digests missed while the server was down) and then ticks
+func (w *FNNotificationDigestWorker) Start(ctx context.Context) error {
+	loopCtx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.wg.Add(1)
+	go w.loop(loopCtx)
+
+	log.Info().Dur("interval", w.interval).Msg("Notification digest worker started")
+	return nil
+}
+
+// Stop waits for the current pass to finish
+func (w *FNNotificationDigestWorker) Stop() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Info().Msg("Notification digest worker stopped")
+	return nil
+}
+
+func (w *FNNotificationDigestWorker) loop(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		built, err := w.svc.BuildDigests(ctx, time.Now())
+		if err != nil {
+			log.Error().Err(err).Msg("error building notification digests")
+		} else if built > 0 {
+			log.Info().Int("digests", built).Msg("notification digests built")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"server/internal/dto"
+	"server/internal/service"
+)
+
+// notificationQueueGroup makes each domain event reach a single server instance
+const notificationQueueGroup = "notification-dispatcher"
+
+type FNNotificationWorker struct {
+	natsConn *nats.Conn
+	svc      service.FNNotificationService
+	interval time.Duration
+	subs     []*nats.Subscription
+	kick     chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewFNNotificationWorker creates a new FN notification worker.
+// interval is how often the email retry queue is polled.
+func NewFNNotificationWorker(natsConn *nats.Conn, svc service.FNNotificationService, interval time.Duration) *FNNotificationWorker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &FNNotificationWorker{
+		natsConn: natsConn,
+		svc:      svc,
+		interval: interval,
+		subs:     make([]*nats.Subscription, 0),
+		kick:     make(chan struct{}, 1),
+	}
+}
+
+// Start subscribes to domain events (when NATS is available) and starts the delivery loop
+func (w *FNNotificationWorker) Start(ctx context.Context) error {
+	if w.natsConn != nil {
+		sub, err := w.natsConn.QueueSubscribe(service.SubjectDomainEvents, notificationQueueGroup, func(msg *nats.Msg) {
+			w.handleDomainEvent(ctx, msg)
+		})
+		if err != nil {
+			return err
+		}
+		w.subs = append(w.subs, sub)
+	} else {
+		log.Warn().Msg("NATS unavailable, notification worker only delivers queued emails")
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.wg.Add(1)
+	go w.deliveryLoop(loopCtx)
+
+	log.Info().
+		Str("subject", service.SubjectDomainEvents).
+		Str("queue", notificationQueueGroup).
+		Dur("interval", w.interval).
+		Msg("Notification worker started")
+
+	return nil
+}
+
+// Stop unsubscribes and waits for the delivery loop to finish
+func (w *FNNotificationWorker) Stop() error {
+	for _, sub := range w.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			log.Warn().Err(err).Str("subject", sub.Subject).Msg("failed to unsubscribe")
+		}
+	}
+	w.subs = nil
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Info().Msg("Notification worker stopped")
+	return nil
+}
+
+func (w *FNNotificationWorker) handleDomainEvent(ctx context.Context, msg *nats.Msg) {
+	var event dto.DomainEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Error().Err(err).Str("subject", msg.Subject).Msg("error unmarshaling domain event")
+		return
+	}
+
+	if err := w.svc.Dispatch(ctx, event); err != nil {
+		log.Error().Err(err).
+			Str("event_id", event.ID.String()).
+			Str("event_type", event.EventType).
+			Msg("error dispatching notifications")
+	}
+
+	// send the new emails now instead of waiting for the next tick
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (w *FNNotificationWorker) deliveryLoop(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.kick:
+		}
+
+		// drain the queue: a full batch means more deliveries may be due
+		for ctx.Err() == nil {
+			sent, err := w.svc.DeliverDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("error delivering notifications")
+				break
+			}
+			if sent == 0 {
+				break
+			}
+		}
+	}
+}
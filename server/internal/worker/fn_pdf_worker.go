@@ -12,17 +12,20 @@ import (
 )
 
 type FNPDFWorker struct {
-	natsConn *nats.Conn
-	docSvc   service.FNDocumentActionService
-	subs     []*nats.Subscription
+	natsConn  *nats.Conn
+	docSvc    service.FNDocumentActionService
+	reportSvc service.FNEvaluationReportService
+	subs      []*nats.Subscription
 }
 
-// NewFNPDFWorker creates a new FN PDF worker
-func NewFNPDFWorker(natsConn *nats.Conn, docSvc service.FNDocumentActionService) *FNPDFWorker {
+// NewFNPDFWorker creates a new FN PDF worker. Batches of evaluation reports go to
+// reportSvc, all others to the certificate pipeline.
+func NewFNPDFWorker(natsConn *nats.Conn, docSvc service.FNDocumentActionService, reportSvc service.FNEvaluationReportService) *FNPDFWorker {
 	return &FNPDFWorker{
-		natsConn: natsConn,
-		docSvc:   docSvc,
-		subs:     make([]*nats.Subscription, 0),
+		natsConn:  natsConn,
+		docSvc:    docSvc,
+		reportSvc: reportSvc,
+		subs:      make([]*nats.Subscription, 0),
 	}
 }
 
@@ -105,6 +108,14 @@ func (w *FNPDFWorker) handleBatchCompleted(ctx context.Context, msg *nats.Msg) {
 		Int64("processing_time_ms", event.Payload.ProcessingTimeMS).
 		Msg("processing batch completed event")
 
+	handled, err := w.reportSvc.ProcessPDFBatchCompleted(ctx, event.Payload)
+	if err != nil {
+		log.Error().Err(err).Str("pdf_job_id", event.Payload.PDFJobID).Msg("error processing evaluation report batch")
+	}
+	if handled {
+		return
+	}
+
 	if err := w.docSvc.ProcessPDFBatchCompleted(ctx, event.Payload); err != nil {
 		log.Error().Err(err).Str("pdf_job_id", event.Payload.PDFJobID).Msg("error processing batch completed")
 	} else {
@@ -126,6 +137,14 @@ func (w *FNPDFWorker) handleBatchFailed(ctx context.Context, msg *nats.Msg) {
 		Str("code", event.Payload.Code).
 		Msg("processing batch failed event")
 
+	handled, err := w.reportSvc.ProcessPDFBatchFailed(ctx, event.Payload)
+	if err != nil {
+		log.Error().Err(err).Str("pdf_job_id", event.Payload.PDFJobID).Msg("error processing evaluation report batch")
+	}
+	if handled {
+		return
+	}
+
 	if err := w.docSvc.ProcessPDFBatchFailed(ctx, event.Payload); err != nil {
 		log.Error().Err(err).Str("pdf_job_id", event.Payload.PDFJobID).Msg("error processing batch failed")
 	} else {
@@ -0,0 +1,30 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter creates a CSV table writer. A UTF-8 BOM is emitted so that
+// spreadsheet software detects accented characters correctly.
+func NewCSVWriter(w io.Writer) TableWriter {
+	_, _ = w.Write([]byte{0xEF, 0xBB, 0xBF})
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) WriteHeader(columns []string) error {
+	return cw.w.Write(columns)
+}
+
+func (cw *csvWriter) WriteRow(values []string) error {
+	return cw.w.Write(neutralizeRow(values))
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// TableWriter writes tabular data row by row without buffering the whole table
+type TableWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []string) error
+	Close() error
+}
+
+// Options holds presentation settings used by formats that support them
+type Options struct {
+	Title    string
+	Subtitle string
+	Sheet    string
+}
+
+// NewTableWriter returns a TableWriter for the given format
+func NewTableWriter(format string, w io.Writer, opts Options) (TableWriter, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w, opts.Sheet)
+	case FormatPDF:
+		return NewPDFWriter(w, opts.Title, opts.Subtitle), nil
+	default:
+		return nil, fmt.Errorf("invalid export format: %s", format)
+	}
+}
+
+// IsSupportedFormat reports whether the format can be exported
+func IsSupportedFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatCSV, FormatXLSX, FormatPDF:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type for the given format
+func ContentType(format string) string {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// neutralizeFormula prefixes cell values that spreadsheet software would run as a
+// formula (=, +, -, @, or a leading tab/CR) with a quote, so exported user data is
+// shown as text. Plain numbers such as "-3.5" are left alone.
+func neutralizeFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+// neutralizeRow applies neutralizeFormula to every value of a row
+func neutralizeRow(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = neutralizeFormula(v)
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNeutralizeFormula(t *testing.T) {
+	cases := map[string]string{
+		"":                   "",
+		"Pérez":              "Pérez",
+		"=HYPERLINK(\"x\")":  "'=HYPERLINK(\"x\")",
+		"+51 999":            "'+51 999",
+		"-cmd":               "'-cmd",
+		"@SUM(A1)":           "'@SUM(A1)",
+		"\t=1":               "'\t=1",
+		"-3.5":               "-3.5",
+		"+7":                 "+7",
+		"a=b":                "a=b",
+		"ana@example.com":    "ana@example.com",
+		"12345678":           "12345678",
+		"=1+2 and more text": "'=1+2 and more text",
+	}
+	for in, want := range cases {
+		if got := neutralizeFormula(in); got != want {
+			t.Errorf("neutralizeFormula(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewCSVWriter(&buf)
+	if err := tw.WriteHeader([]string{"DNI", "Nombres"}); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	if err := tw.WriteRow([]string{"12345678", "=cmd|' /C calc'!A0"}); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
+		t.Fatal("missing UTF-8 BOM")
+	}
+	records, err := csv.NewReader(bytes.NewReader(data[3:])).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 2 || records[1][1] != "'=cmd|' /C calc'!A0" {
+		t.Fatalf("records = %q", records)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sheet := strings.Repeat("Padrón ", 6) // 42 characters, more bytes
+	tw, err := NewXLSXWriter(&buf, sheet)
+	if err != nil {
+		t.Fatalf("NewXLSXWriter: %v", err)
+	}
+	if err := tw.WriteHeader([]string{"DNI", "Nombres"}); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	if err := tw.WriteRow([]string{"12345678", "@SUM(1)<b>"}); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	entries := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[f.Name] = string(body)
+	}
+
+	workbook := entries["xl/workbook.xml"]
+	want := string([]rune(sheet)[:31])
+	if !utf8.ValidString(workbook) || !strings.Contains(workbook, `name="`+want+`"`) {
+		t.Fatalf("sheet name not cut at 31 characters: %s", workbook)
+	}
+
+	worksheet := entries["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(worksheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">&#39;@SUM(1)&lt;b&gt;</t></is></c>`) {
+		t.Fatalf("row cell not escaped: %s", worksheet)
+	}
+	if !strings.Contains(worksheet, `<c r="A1" s="1"`) {
+		t.Fatalf("header not styled: %s", worksheet)
+	}
+}
+
+func TestPDFWriterPaginates(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewPDFWriter(&buf, "Padrón (prueba)", "Evento EV-1")
+	if err := tw.WriteHeader([]string{"N°", "DNI", "Apellidos"}); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := tw.WriteRow([]string{"1", "12345678", "Pérez"}); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatal("not a complete PDF")
+	}
+	if !strings.Contains(out, "/Count 3") {
+		t.Fatal("100 rows should span three pages")
+	}
+	if !strings.Contains(out, "Padr\xf3n \\(prueba\\)") {
+		t.Fatal("title not encoded as WinAnsi with escaped parentheses")
+	}
+}
+
+func TestNewTableWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewTableWriter("ods", io.Discard, Options{}); err == nil || !strings.Contains(err.Error(), "invalid export format") {
+		t.Fatalf("got %v, want invalid export format", err)
+	}
+}
@@ -0,0 +1,245 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// page layout in points (A4 landscape)
+const (
+	pdfPageWidth     = 842.0
+	pdfPageHeight    = 595.0
+	pdfMargin        = 36.0
+	pdfFontSize      = 8.0
+	pdfTitleSize     = 13.0
+	pdfLineHeight    = 13.0
+	pdfCharWidth     = 0.5 // average Helvetica glyph width relative to font size
+	pdfCatalogObjNum = 1
+	pdfPagesObjNum   = 2
+	pdfFontObjNum    = 3
+	pdfBoldObjNum    = 4
+	pdfFirstPageNo   = 5
+)
+
+type pdfWriter struct {
+	w        io.Writer
+	offset   int64
+	offsets  map[int]int64
+	nextObj  int
+	pageObjs []int
+
+	title    string
+	subtitle string
+	columns  []string
+	widths   []float64
+
+	content *bytes.Buffer
+	y       float64
+	err     error
+}
+
+// NewPDFWriter creates a printable PDF report writer. Pages are flushed to w as
+// soon as they are full, so only the current page is kept in memory.
+func NewPDFWriter(w io.Writer, title, subtitle string) TableWriter {
+	pw := &pdfWriter{
+		w:        w,
+		offsets:  make(map[int]int64),
+		nextObj:  pdfFirstPageNo,
+		title:    title,
+		subtitle: subtitle,
+	}
+
+	pw.write("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	pw.writeObject(pdfFontObjNum, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	pw.writeObject(pdfBoldObjNum, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	return pw
+}
+
+func (pw *pdfWriter) WriteHeader(columns []string) error {
+	pw.columns = columns
+
+	// width proportional to header length, with a sensible minimum
+	total := 0.0
+	weights := make([]float64, len(columns))
+	for i, col := range columns {
+		weights[i] = float64(len(col))
+		if weights[i] < 8 {
+			weights[i] = 8
+		}
+		total += weights[i]
+	}
+
+	available := pdfPageWidth - 2*pdfMargin
+	pw.widths = make([]float64, len(columns))
+	for i := range columns {
+		pw.widths[i] = available * weights[i] / total
+	}
+
+	pw.startPage()
+	return pw.err
+}
+
+func (pw *pdfWriter) WriteRow(values []string) error {
+	if pw.content == nil {
+		pw.startPage()
+	}
+	if pw.y < pdfMargin+pdfLineHeight {
+		pw.finishPage()
+		pw.startPage()
+	}
+
+	pw.drawRow(values, false)
+	return pw.err
+}
+
+func (pw *pdfWriter) Close() error {
+	if pw.content == nil {
+		pw.startPage()
+	}
+	pw.finishPage()
+
+	kids := make([]string, 0, len(pw.pageObjs))
+	for _, n := range pw.pageObjs {
+		kids = append(kids, fmt.Sprintf("%d 0 R", n))
+	}
+	pw.writeObject(pdfPagesObjNum, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pw.pageObjs)))
+
+	pw.writeObject(pdfCatalogObjNum, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObjNum))
+
+	infoObj := pw.nextObj
+	pw.nextObj++
+	pw.writeObject(infoObj, fmt.Sprintf("<< /Title (%s) /Producer (cert-server) /CreationDate (D:%s) >>",
+		pdfEscape(pw.title), time.Now().UTC().Format("20060102150405Z")))
+
+	xrefOffset := pw.offset
+	size := pw.nextObj
+	pw.write(fmt.Sprintf("xref\n0 %d\n", size))
+	pw.write("0000000000 65535 f \n")
+	for i := 1; i < size; i++ {
+		pw.write(fmt.Sprintf("%010d 00000 n \n", pw.offsets[i]))
+	}
+	pw.write(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, pdfCatalogObjNum, infoObj, xrefOffset))
+
+	return pw.err
+}
+
+func (pw *pdfWriter) startPage() {
+	pw.content = &bytes.Buffer{}
+	pw.y = pdfPageHeight - pdfMargin
+
+	pageNo := len(pw.pageObjs) + 1
+	if pageNo == 1 {
+		pw.text(pdfMargin, pw.y-pdfTitleSize, pdfTitleSize, true, pw.title)
+		pw.y -= pdfTitleSize + 6
+		if pw.subtitle != "" {
+			pw.text(pdfMargin, pw.y-pdfFontSize-2, pdfFontSize+1, false, pw.subtitle)
+			pw.y -= pdfLineHeight + 2
+		}
+		pw.y -= 6
+	}
+
+	pw.text(pdfPageWidth-pdfMargin-60, pdfMargin/2, pdfFontSize, false, fmt.Sprintf("Página %d", pageNo))
+
+	if len(pw.columns) > 0 {
+		pw.drawRow(pw.columns, true)
+		// underline the header
+		fmt.Fprintf(pw.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, pw.y+3, pdfPageWidth-pdfMargin, pw.y+3)
+	}
+}
+
+func (pw *pdfWriter) finishPage() {
+	if pw.content == nil {
+		return
+	}
+
+	contentObj := pw.nextObj
+	pageObj := pw.nextObj + 1
+	pw.nextObj += 2
+
+	data := pw.content.Bytes()
+	pw.writeObject(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data))
+	pw.writeObject(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObjNum, pdfPageWidth, pdfPageHeight, pdfFontObjNum, pdfBoldObjNum, contentObj,
+	))
+
+	pw.pageObjs = append(pw.pageObjs, pageObj)
+	pw.content = nil
+}
+
+func (pw *pdfWriter) drawRow(values []string, bold bool) {
+	x := pdfMargin
+	pw.y -= pdfLineHeight
+	for i, width := range pw.widths {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pw.text(x+1, pw.y, pdfFontSize, bold, fitText(value, width-4, pdfFontSize))
+		x += width
+	}
+}
+
+func (pw *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(pw.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+}
+
+func (pw *pdfWriter) writeObject(num int, body string) {
+	pw.offsets[num] = pw.offset
+	pw.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", num, body))
+}
+
+func (pw *pdfWriter) write(s string) {
+	if pw.err != nil {
+		return
+	}
+	n, err := io.WriteString(pw.w, s)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+// fitText truncates s so that it fits in the given width
+func fitText(s string, width, size float64) string {
+	maxChars := int(width / (size * pdfCharWidth))
+	runes := []rune(s)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return s
+	}
+	if maxChars <= 1 {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
+
+// pdfEscape converts s to WinAnsi (Latin-1 subset) and escapes PDF string delimiters
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '…':
+			b.WriteByte(0x85)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 0x20:
+			// skip control characters
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}